@@ -7,3 +7,13 @@ output "serviceaccount-email" {
   description = "The email of the service account for the bot."
   value       = var.service_account_email == "" ? google_service_account.sa[0].email : var.service_account_email
 }
+
+output "audit-recorder-schemas" {
+  description = "The recorder schema of the audit events bots emit with sdk.Auditor, to record them with cloudevent-recorder."
+  value = {
+    "dev.chainguard.bot.action" : {
+      schema                = file("${path.module}/audit.schema.json")
+      retention_period_days = 548 # 18 months (365 * 1.5)
+    }
+  }
+}
@@ -0,0 +1,343 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/errgroup"
+)
+
+// AuditEventType is the type of the CloudEvents an Auditor emits.
+const AuditEventType = "dev.chainguard.bot.action"
+
+// maxAuditSummary is the length to which AuditAction.Summary is truncated.
+const maxAuditSummary = 1024
+
+// maxAuditSends is how many events of a batch an Auditor sends at once.
+const maxAuditSends = 10
+
+// auditEvents tracks audit events by result: sent, failed, or dropped
+// because the queue was full or the Auditor closed.
+var auditEvents = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "github_bots_audit_events_total",
+		Help: "Total number of bot action audit events, by result",
+	},
+	[]string{"bot", "result"},
+)
+
+// AuditAction is the data of an audit event: a mutating GitHub API call made
+// by a bot. The schema of its table in BigQuery is audit.schema.json.
+type AuditAction struct {
+	When time.Time `json:"when"`
+	Bot  string    `json:"bot"`
+	// EventID is the ID of the event the bot was handling, if any.
+	EventID string `json:"event_id,omitempty"`
+	// Target is the pull request or issue acted on, as "owner/repo#number",
+	// or else the repository, as "owner/repo", or the node ID a GraphQL
+	// mutation acted on.
+	Target string `json:"target"`
+	// Action is the HTTP method and the API path below the repository,
+	// with numeric IDs replaced by "{id}", such as
+	// "POST issues/{id}/labels", or "graphql" and the mutation, such as
+	// "graphql enablePullRequestAutoMerge".
+	Action string `json:"action"`
+	// Status is the HTTP status GitHub answered with.
+	Status int `json:"status"`
+	// Summary is the request body, truncated to 1024 bytes, which holds
+	// the change made, such as the labels added or a comment's text.
+	Summary string `json:"summary,omitempty"`
+}
+
+// Auditor emits an AuditEventType CloudEvent for each mutating GitHub API
+// call made by clients created with WithAuditor, so that changes made across
+// a fleet of bots can be traced back to the bot and event that made them.
+// Send the events to the broker, and record them to BigQuery with the
+// cloudevent-recorder module like any other event.
+//
+// Events are queued and sent in batches in the background, so auditing never
+// slows down a bot. The events of a batch are sent concurrently, so may
+// arrive out of order; order them by their time. Events are dropped when the
+// queue is full. Close the Auditor to send the events still queued.
+type Auditor struct {
+	bot      string
+	client   cloudevents.Client
+	size     int
+	interval time.Duration
+
+	mu     sync.RWMutex
+	queue  chan cloudevents.Event
+	closed bool
+	done   chan struct{}
+}
+
+// AuditorOption configures an Auditor.
+type AuditorOption func(*Auditor)
+
+// WithAuditBatching sets how many events the Auditor sends at once, and how
+// long it waits for a batch to fill. It defaults to 50 events, for up to 5
+// seconds. Each event is still its own request; up to 10 of a batch are in
+// flight at a time.
+func WithAuditBatching(size int, interval time.Duration) AuditorOption {
+	return func(a *Auditor) {
+		a.size, a.interval = size, interval
+	}
+}
+
+// WithAuditQueueSize sets how many events may wait to be sent before new ones
+// are dropped. It defaults to 1000.
+func WithAuditQueueSize(n int) AuditorOption {
+	return func(a *Auditor) {
+		a.queue = make(chan cloudevents.Event, n)
+	}
+}
+
+// NewAuditor returns an Auditor for the named bot that sends events with
+// client, typically created with mce.NewClientHTTP and mce.WithTarget to
+// send them to the broker ingress.
+func NewAuditor(bot string, client cloudevents.Client, opts ...AuditorOption) *Auditor {
+	a := &Auditor{
+		bot:      bot,
+		client:   client,
+		size:     50,
+		interval: 5 * time.Second,
+		queue:    make(chan cloudevents.Event, 1000),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	go a.run()
+	return a
+}
+
+// WithAuditor makes the client report its mutating API calls to a.
+// Writes recorded by WithDryRun aren't sent, so aren't audited either. Git
+// pushes aren't audited.
+func WithAuditor(a *Auditor) GitHubClientOption {
+	return func(c *GitHubClient) {
+		c.auditor = a
+	}
+}
+
+// Close sends the queued events, waiting until they are sent or ctx is done.
+// Events recorded after Close are dropped.
+func (a *Auditor) Close(ctx context.Context) error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// record queues the audit event of action.
+func (a *Auditor) record(ctx context.Context, action AuditAction) {
+	event := cloudevents.NewEvent()
+	event.SetID(rand.Text())
+	event.SetType(AuditEventType)
+	event.SetSource("github-bots/" + a.bot)
+	event.SetSubject(action.Target)
+	event.SetTime(action.When)
+	// Needs to be an extension to be a filterable attribute.
+	event.SetExtension("bot", a.bot)
+	if err := event.SetData(cloudevents.ApplicationJSON, action); err != nil {
+		clog.FromContext(ctx).Errorf("failed to encode audit event: %v", err)
+		return
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		auditEvents.WithLabelValues(a.bot, "dropped").Inc()
+		return
+	}
+	select {
+	case a.queue <- event:
+	default:
+		clog.FromContext(ctx).Warnf("audit queue full, dropping audit event of %s on %s", action.Action, action.Target)
+		auditEvents.WithLabelValues(a.bot, "dropped").Inc()
+	}
+}
+
+// run sends the queued events in batches until the queue is closed.
+func (a *Auditor) run() {
+	defer close(a.done)
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	batch := make([]cloudevents.Event, 0, a.size)
+	for {
+		select {
+		case event, ok := <-a.queue:
+			if !ok {
+				a.send(batch)
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= a.size {
+				a.send(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			a.send(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (a *Auditor) send(batch []cloudevents.Event) {
+	const retryDelay = 10 * time.Millisecond
+	const maxRetry = 3
+	ctx := cloudevents.ContextWithRetriesExponentialBackoff(context.Background(), retryDelay, maxRetry)
+	var g errgroup.Group
+	g.SetLimit(maxAuditSends)
+	for _, event := range batch {
+		g.Go(func() error {
+			if result := a.client.Send(ctx, event); cloudevents.IsUndelivered(result) || cloudevents.IsNACK(result) {
+				clog.FromContext(ctx).Errorf("failed to send audit event %s: %v", event.ID(), result)
+				auditEvents.WithLabelValues(a.bot, "failed").Inc()
+				return nil
+			}
+			auditEvents.WithLabelValues(a.bot, "sent").Inc()
+			return nil
+		})
+	}
+	// Failures are logged and counted; there is nothing to return.
+	_ = g.Wait()
+}
+
+// auditTransport reports the successful writes sent through it to an
+// Auditor.
+type auditTransport struct {
+	base    http.RoundTripper
+	auditor *Auditor
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.base.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	graphql := strings.HasSuffix(req.URL.Path, "/graphql")
+	if graphql && !isMutation(body) {
+		return t.base.RoundTrip(req)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		return resp, err
+	}
+
+	ctx := req.Context()
+	action := AuditAction{
+		When:    time.Now(),
+		Bot:     t.auditor.bot,
+		Status:  resp.StatusCode,
+		Summary: auditSummary(body),
+	}
+	if id, ok := ctx.Value(ContextKeyID).(string); ok {
+		action.EventID = id
+	}
+	if graphql {
+		action.Target, action.Action = graphQLAuditTarget(body)
+	} else {
+		action.Target, action.Action = restAuditTarget(req.Method, req.URL.Path)
+	}
+	t.auditor.record(ctx, action)
+	return resp, nil
+}
+
+var numericSegment = regexp.MustCompile(`^\d+$`)
+
+// restAuditTarget returns the target and action of a REST API call.
+func restAuditTarget(method, path string) (string, string) {
+	target, rest := "", path
+	if _, after, ok := strings.Cut(path, "/repos/"); ok {
+		if parts := strings.SplitN(after, "/", 3); len(parts) >= 2 {
+			target = parts[0] + "/" + parts[1]
+			rest = ""
+			if len(parts) == 3 {
+				rest = parts[2]
+			}
+		}
+	}
+	segments := strings.Split(strings.Trim(rest, "/"), "/")
+	if target != "" && len(segments) >= 2 && (segments[0] == "issues" || segments[0] == "pulls") && numericSegment.MatchString(segments[1]) {
+		target += "#" + segments[1]
+	}
+	for i, s := range segments {
+		if numericSegment.MatchString(s) {
+			segments[i] = "{id}"
+		}
+	}
+	if target == "" {
+		target = path
+	}
+	return target, method + " " + strings.Join(segments, "/")
+}
+
+var mutationField = regexp.MustCompile(`^\s*mutation\b[^{]*\{\s*(\w+)`)
+
+// graphQLAuditTarget returns the target and action of a GraphQL mutation.
+func graphQLAuditTarget(body []byte) (string, string) {
+	var q struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+	_ = json.Unmarshal(body, &q)
+	action := "graphql"
+	if m := mutationField.FindStringSubmatch(q.Query); m != nil {
+		action += " " + m[1]
+	}
+	target, _ := q.Variables["id"].(string)
+	return target, action
+}
+
+// auditSummary returns the request body, compacted if JSON, and truncated.
+func auditSummary(body []byte) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, body); err == nil {
+		body = buf.Bytes()
+	}
+	if len(body) > maxAuditSummary {
+		return string(body[:maxAuditSummary]) + "…"
+	}
+	return string(body)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-github/v88/github"
+)
+
+type fakeCEClient struct {
+	cloudevents.Client
+
+	mu     sync.Mutex
+	events []cloudevents.Event
+}
+
+func (f *fakeCEClient) Send(_ context.Context, event cloudevents.Event) cloudevents.Result {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeCEClient) actions(t *testing.T) []AuditAction {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var actions []AuditAction
+	for _, event := range f.events {
+		if event.Type() != AuditEventType {
+			t.Errorf("event type = %q, want %q", event.Type(), AuditEventType)
+		}
+		if got, want := event.Source(), "github-bots/test-bot"; got != want {
+			t.Errorf("event source = %q, want %q", got, want)
+		}
+		var action AuditAction
+		if err := event.DataAs(&action); err != nil {
+			t.Fatalf("DataAs: %v", err)
+		}
+		if event.Subject() != action.Target {
+			t.Errorf("event subject = %q, want %q", event.Subject(), action.Target)
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+func TestAuditor(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ContextKeyID, "event-1")
+	srv := githubtest.NewServer(t)
+	srv.SetFile("acme", "widgets", "main", "README.md", "# widgets")
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{
+		MergeableState: github.Ptr("blocked"),
+	})
+
+	ce := &fakeCEClient{}
+	auditor := NewAuditor("test-bot", ce, WithAuditBatching(2, time.Hour))
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithAuditor(auditor), WithClient(srv.Client()))
+
+	if err := gh.AddLabel(ctx, pr, "lgtm"); err != nil {
+		t.Errorf("AddLabel: %v", err)
+	}
+	if err := gh.EnableAutoMerge(ctx, pr, MergeMethodSquash); err != nil {
+		t.Errorf("EnableAutoMerge: %v", err)
+	}
+	if _, err := gh.GetFileContent(ctx, "acme", "widgets", "README.md", "main"); err != nil {
+		t.Errorf("GetFileContent: %v", err)
+	}
+	// Failed writes change nothing, so aren't audited.
+	srv.SetMergeability("acme", "widgets", pr.GetNumber(), github.Ptr(false), "dirty")
+	if err := gh.UpdateBranch(ctx, pr); err == nil {
+		t.Error("UpdateBranch of a conflicting pull request succeeded")
+	}
+	if err := gh.SetComment(ctx, pr, "test-bot", "hello"); err != nil {
+		t.Errorf("SetComment: %v", err)
+	}
+
+	if err := auditor.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// Events of a batch are sent concurrently, so arrive in any order.
+	got := ce.actions(t)
+	slices.SortFunc(got, func(a, b AuditAction) int { return a.When.Compare(b.When) })
+	want := []struct{ target, action, summary string }{
+		{"acme/widgets#1", "POST issues/{id}/labels", `["lgtm"]`},
+		{pr.GetNodeID(), "graphql enablePullRequestAutoMerge", "mergeMethod"},
+		{"acme/widgets#1", "POST issues/{id}/comments", "hello"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d audit events, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		a := got[i]
+		if a.Target != w.target || a.Action != w.action {
+			t.Errorf("event %d = %s on %s, want %s on %s", i, a.Action, a.Target, w.action, w.target)
+		}
+		if !strings.Contains(a.Summary, w.summary) {
+			t.Errorf("event %d summary = %q, want it to contain %q", i, a.Summary, w.summary)
+		}
+		if a.Bot != "test-bot" || a.EventID != "event-1" || a.When.IsZero() || a.Status >= 400 {
+			t.Errorf("event %d = %+v", i, a)
+		}
+	}
+}
+
+func TestAuditorDryRun(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{})
+
+	ce := &fakeCEClient{}
+	auditor := NewAuditor("test-bot", ce)
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithDryRun(), WithAuditor(auditor), WithClient(srv.Client()))
+
+	if err := gh.AddLabel(ctx, pr, "lgtm"); err != nil {
+		t.Errorf("AddLabel: %v", err)
+	}
+	if err := auditor.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := ce.actions(t); len(got) != 0 {
+		t.Errorf("got audit events of dry run writes: %+v", got)
+	}
+}
+
+func TestAuditorClosed(t *testing.T) {
+	ctx := context.Background()
+	ce := &fakeCEClient{}
+	auditor := NewAuditor("test-bot", ce)
+	if err := auditor.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// Closing twice is fine, and later events are dropped.
+	if err := auditor.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	auditor.record(ctx, AuditAction{Target: "acme/widgets", Action: "POST labels"})
+	if got := ce.actions(t); len(got) != 0 {
+		t.Errorf("got audit events after Close: %+v", got)
+	}
+}
+
+// blockingCEClient blocks each Send until release is closed, counting the
+// sends in flight.
+type blockingCEClient struct {
+	cloudevents.Client
+
+	release  chan struct{}
+	inflight atomic.Int32
+	sent     atomic.Int32
+}
+
+func (b *blockingCEClient) Send(context.Context, cloudevents.Event) cloudevents.Result {
+	b.inflight.Add(1)
+	<-b.release
+	b.sent.Add(1)
+	return nil
+}
+
+func TestAuditorSendsConcurrently(t *testing.T) {
+	ctx := context.Background()
+	ce := &blockingCEClient{release: make(chan struct{})}
+	auditor := NewAuditor("test-bot", ce, WithAuditBatching(2*maxAuditSends, time.Hour))
+	for range 2 * maxAuditSends {
+		auditor.record(ctx, AuditAction{Target: "acme/widgets", Action: "POST labels"})
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for ce.inflight.Load() < maxAuditSends {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d sends in flight, want %d", ce.inflight.Load(), maxAuditSends)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if got := ce.inflight.Load(); got != maxAuditSends {
+		t.Errorf("%d sends in flight, want at most %d", got, maxAuditSends)
+	}
+
+	close(ce.release)
+	if err := auditor.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := ce.sent.Load(); got != 2*maxAuditSends {
+		t.Errorf("sent %d events, want %d", got, 2*maxAuditSends)
+	}
+}
+
+func TestRestAuditTarget(t *testing.T) {
+	for _, tt := range []struct {
+		method, path, target, action string
+	}{
+		{"POST", "/api/v3/repos/acme/widgets/issues/12/labels", "acme/widgets#12", "POST issues/{id}/labels"},
+		{"DELETE", "/repos/acme/widgets/issues/12/labels/lgtm", "acme/widgets#12", "DELETE issues/{id}/labels/lgtm"},
+		{"PATCH", "/repos/acme/widgets/issues/comments/987", "acme/widgets", "PATCH issues/comments/{id}"},
+		{"PUT", "/repos/acme/widgets/pulls/3/merge", "acme/widgets#3", "PUT pulls/{id}/merge"},
+		{"POST", "/repos/acme/widgets/check-runs", "acme/widgets", "POST check-runs"},
+		{"POST", "/app/installations/5/access_tokens", "/app/installations/5/access_tokens", "POST app/installations/{id}/access_tokens"},
+	} {
+		target, action := restAuditTarget(tt.method, tt.path)
+		if target != tt.target || action != tt.action {
+			t.Errorf("restAuditTarget(%s %s) = %q, %q, want %q, %q", tt.method, tt.path, target, action, tt.target, tt.action)
+		}
+	}
+}
@@ -7,11 +7,13 @@ package sdk
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"runtime/debug"
 	"strconv"
+	"time"
 
 	"github.com/chainguard-dev/clog"
 	_ "github.com/chainguard-dev/clog/gcp/init" // enable GCP logging
@@ -19,7 +21,6 @@ import (
 	"github.com/chainguard-dev/terraform-infra-common/pkg/httpmetrics"
 	mce "github.com/chainguard-dev/terraform-infra-common/pkg/httpmetrics/cloudevents"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
-	"github.com/google/go-github/v88/github"
 )
 
 // Define a type for keys used in context to prevent key collisions.
@@ -30,19 +31,30 @@ const (
 	ContextKeyAttributes contextKey = "ce-attributes"
 	ContextKeyType       contextKey = "ce-type"
 	ContextKeySubject    contextKey = "ce-subject"
+	// ContextKeyID holds the ID of the event being handled, which audit
+	// events reference.
+	ContextKeyID contextKey = "ce-id"
 )
 
 type Bot struct {
 	Name     string
 	Handlers map[EventType]EventHandlerFunc
+	// CoalesceKeys holds the key extractors of event types whose events are
+	// coalesced by key, see BotWithCoalescing.
+	CoalesceKeys map[EventType]CoalesceKeyFunc
+
+	// failures reports handler failures on pull requests, see
+	// BotWithFailureReporting.
+	failures *failureReporter
 }
 
 type BotOptions func(*Bot)
 
 func NewBot(name string, opts ...BotOptions) Bot {
 	bot := Bot{
-		Name:     name,
-		Handlers: make(map[EventType]EventHandlerFunc),
+		Name:         name,
+		Handlers:     make(map[EventType]EventHandlerFunc),
+		CoalesceKeys: make(map[EventType]CoalesceKeyFunc),
 	}
 
 	for _, opt := range opts {
@@ -70,7 +82,8 @@ func (b *Bot) RegisterHandler(handler EventHandlerFunc) {
 type ServeOption func(*serveConfig)
 
 type serveConfig struct {
-	port int
+	port             int
+	coalesceDebounce time.Duration
 }
 
 // WithPort sets the port for the bot's HTTP server.
@@ -81,8 +94,10 @@ func WithPort(port int) ServeOption {
 	}
 }
 
-func Serve(b Bot, opts ...ServeOption) {
-	cfg := &serveConfig{}
+func newServeConfig(opts []ServeOption) *serveConfig {
+	cfg := &serveConfig{
+		coalesceDebounce: 2 * time.Second,
+	}
 	for _, opt := range opts {
 		opt(cfg)
 	}
@@ -94,18 +109,30 @@ func Serve(b Bot, opts ...ServeOption) {
 			}
 		}
 	}
+	return cfg
+}
 
-	ctx := context.Background()
-
-	log := clog.FromContext(ctx)
-
+// instrument sets up the metrics and tracing of a bot's server, and returns a
+// function that flushes the traces.
+func instrument(ctx context.Context) func() {
 	http.DefaultTransport = httpmetrics.Transport
 	go httpmetrics.ServeMetrics()
-	defer httpmetrics.SetupTracer(ctx)()
+	shutdown := httpmetrics.SetupTracer(ctx)
 	httpmetrics.SetBuckets(map[string]string{
 		"api.github.com": "github",
 		"octo-sts.dev":   "octosts",
 	})
+	return shutdown
+}
+
+func Serve(b Bot, opts ...ServeOption) {
+	cfg := newServeConfig(opts)
+
+	ctx := context.Background()
+
+	log := clog.FromContext(ctx)
+
+	defer instrument(ctx)()
 
 	c, err := mce.NewClientHTTP(b.Name,
 		cloudevents.WithPort(cfg.port),
@@ -115,192 +142,139 @@ func Serve(b Bot, opts ...ServeOption) {
 	}
 
 	log.Infof("starting bot %s receiver on port %d", b.Name, cfg.port)
-	if err := c.StartReceiver(ctx, func(ctx context.Context, event cloudevents.Event) error {
-		clog.FromContext(ctx).With("event", event).Debugf("received event")
+	if err := c.StartReceiver(ctx, b.receiver(cfg)); err != nil {
+		clog.Fatalf("failed to start event receiver, %v", err)
+	}
+}
 
+// handleEvent dispatches a single event to the bot's handler for its type,
+// and reports permanent failures as configured with BotWithFailureReporting.
+// Reported failures are acknowledged rather than retried.
+func (b Bot) handleEvent(ctx context.Context, event cloudevents.Event) error {
+	var failure error
+	err := func() error {
 		defer func() {
 			if err := recover(); err != nil {
 				clog.Errorf("panic: %s", debug.Stack())
+				failure = fmt.Errorf("panic: %v", err)
 			}
 		}()
+		return b.dispatch(ctx, event)
+	}()
 
-		log.With("type", event.Type(),
-			"subject", event.Subject(),
-			"action", event.Extensions()["action"]).Debug("handling event")
+	if _, ok := b.Handlers[EventType(event.Type())]; !ok || b.failures == nil {
+		return err
+	}
+	if _, ok := errors.AsType[*PermanentError](err); ok {
+		failure = err
+	} else if err != nil && failure == nil {
+		// Transient failures are retried, and may yet succeed.
+		return err
+	}
+	b.failures.report(ctx, event, failure)
+	if err != nil {
+		// Retrying a permanent failure would only report it again, so
+		// acknowledge the event once it is reported.
+		clog.ErrorContextf(ctx, "handler failed permanently for event %s: %v", event.ID(), err)
+	}
+	return nil
+}
 
-		// dispatch event to n handlers
-		if handler, ok := b.Handlers[EventType(event.Type())]; ok {
-			// loop over all event headers and add them to the context so they can be used by the handlers
-			for k, v := range event.Context.GetExtensions() {
-				ctx = context.WithValue(ctx, contextKey(k), v)
-			}
+// dispatch dispatches a single event to the bot's handler for its type.
+func (b Bot) dispatch(ctx context.Context, event cloudevents.Event) error {
+	log := clog.FromContext(ctx)
+	log.With("event", event).Debugf("received event")
 
-			// add existing event attributes to context so they can be used by the handlers
-			ctx = context.WithValue(ctx, ContextKeyAttributes, event.Extensions())
-			ctx = context.WithValue(ctx, ContextKeyType, event.Type())
-			ctx = context.WithValue(ctx, ContextKeySubject, event.Subject())
-
-			switch h := handler.(type) {
-			case WorkflowRunArtifactHandler:
-				log.Debug("handling workflow run artifact event")
-
-				var wre schemas.Wrapper[github.WorkflowRunEvent]
-				if err := event.DataAs(&wre); err != nil {
-					log.Errorf("failed to unmarshal workflow run event: %v", err)
-					return err
-				}
-
-				if err := h(ctx, wre.Body); err != nil {
-					log.Errorf("failed to handle workflow run event: %v", err)
-					return err
-				}
-				return nil
-
-			case WorkflowRunHandler:
-				log.Debug("handling workflow run event")
-
-				var wre schemas.Wrapper[github.WorkflowRunEvent]
-				if err := event.DataAs(&wre); err != nil {
-					log.Errorf("failed to unmarshal workflow run event: %v", err)
-					return err
-				}
-
-				if err := h(ctx, wre.Body); err != nil {
-					log.Errorf("failed to handle workflow run event: %v", err)
-					return err
-				}
-				return nil
-
-			case WorkflowRunLogsHandler:
-				log.Debug("handling workflow run logs event")
-
-				var wre schemas.Wrapper[github.WorkflowRunEvent]
-				if err := event.DataAs(&wre); err != nil {
-					log.Errorf("failed to unmarshal workflow run with logs event: %v", err)
-					return err
-				}
-
-				if err := h(ctx, wre.Body); err != nil {
-					log.Errorf("failed to handle workflow run with logs event: %v", err)
-					return err
-				}
-				return nil
-
-			case PullRequestHandler:
-				log.Debug("handling pull request event")
-
-				var pre schemas.Wrapper[github.PullRequestEvent]
-				if err := event.DataAs(&pre); err != nil {
-					log.Errorf("failed to unmarshal pull request event: %v", err)
-					return err
-				}
-
-				if err := h(ctx, pre.Body); err != nil {
-					log.Errorf("failed to handle pull request event: %v", err)
-					return err
-				}
-				return nil
-
-			case IssuesHandler:
-				log.Debug("handling issue event")
-
-				var ie schemas.Wrapper[github.IssueEvent]
-				if err := event.DataAs(&ie); err != nil {
-					log.Errorf("failed to unmarshal issue event: %v", err)
-					return err
-				}
-
-				if err := h(ctx, ie.Body); err != nil {
-					log.Errorf("failed to handle issue event: %v", err)
-					return err
-				}
-				return nil
-
-			case IssueCommentHandler:
-				log.Debug("handling issue comment event")
-
-				var ice schemas.Wrapper[github.IssueCommentEvent]
-				if err := event.DataAs(&ice); err != nil {
-					log.Errorf("failed to unmarshal issue comment event: %v", err)
-					return err
-				}
-
-				if err := h(ctx, ice.Body); err != nil {
-					log.Errorf("failed to handle issue comment event: %v", err)
-					return err
-				}
-				return nil
-
-			case PushHandler:
-				log.Debug("handling push event")
-
-				var pe schemas.Wrapper[github.PushEvent]
-				if err := event.DataAs(&pe); err != nil {
-					log.Errorf("failed to unmarshal push event: %v", err)
-					return err
-				}
-
-				if err := h(ctx, pe.Body); err != nil {
-					log.Errorf("failed to handle push event: %v", err)
-					return err
-				}
-				return nil
-
-			case CheckRunHandler:
-				log.Debug("handling check_run event")
-
-				var pe schemas.Wrapper[github.CheckRunEvent]
-				if err := event.DataAs(&pe); err != nil {
-					log.Errorf("failed to unmarshal push event: %v", err)
-					return err
-				}
-
-				if err := h(ctx, pe.Body); err != nil {
-					log.Errorf("failed to handle check_run event: %v", err)
-					return err
-				}
-				return nil
-
-			case CheckSuiteHandler:
-				log.Debug("handling check_suite event")
-
-				var pe schemas.Wrapper[github.CheckSuiteEvent]
-				if err := event.DataAs(&pe); err != nil {
-					log.Errorf("failed to unmarshal check_suite event: %v", err)
-					return err
-				}
-
-				if err := h(ctx, pe.Body); err != nil {
-					log.Errorf("failed to handle check_suite event: %v", err)
-					return err
-				}
-				return nil
-
-			case ProjectsV2ItemHandler:
-				log.Debug("handling projects_v2_item event")
-
-				var pie schemas.Wrapper[ProjectsV2ItemEvent]
-				if err := event.DataAs(&pie); err != nil {
-					log.Errorf("failed to unmarshal projects_v2_item event: %v", err)
-					return err
-				}
-
-				if err := h(ctx, pie.Body); err != nil {
-					log.Errorf("failed to handle projects_v2_item event: %v", err)
-					return err
-				}
-				return nil
-
-			default:
-				return fmt.Errorf("unknown handler type %T", handler)
-			}
+	log.With("type", event.Type(),
+		"subject", event.Subject(),
+		"action", event.Extensions()["action"]).Debug("handling event")
+
+	// dispatch event to n handlers
+	if handler, ok := b.Handlers[EventType(event.Type())]; ok {
+		// loop over all event headers and add them to the context so they can be used by the handlers
+		for k, v := range event.Context.GetExtensions() {
+			ctx = context.WithValue(ctx, contextKey(k), v)
 		}
 
-		clog.FromContext(ctx).With("event", event).Debugf("ignoring event")
-		return nil
-	}); err != nil {
-		clog.Fatalf("failed to start event receiver, %v", err)
+		// add existing event attributes to context so they can be used by the handlers
+		ctx = context.WithValue(ctx, ContextKeyAttributes, event.Extensions())
+		ctx = context.WithValue(ctx, ContextKeyType, event.Type())
+		ctx = context.WithValue(ctx, ContextKeySubject, event.Subject())
+		ctx = context.WithValue(ctx, ContextKeyID, event.ID())
+
+		switch h := handler.(type) {
+		case WorkflowRunArtifactHandler:
+			return handle(ctx, event, "workflow run artifact", h)
+		case WorkflowRunHandler:
+			return handle(ctx, event, "workflow run", h)
+		case WorkflowRunLogsHandler:
+			return handle(ctx, event, "workflow run logs", h)
+		case WorkflowJobHandler:
+			return handle(ctx, event, "workflow_job", h)
+		case PullRequestHandler:
+			return handle(ctx, event, "pull request", h)
+		case PullRequestReviewHandler:
+			return handle(ctx, event, "pull_request_review", h)
+		case PullRequestReviewCommentHandler:
+			return handle(ctx, event, "pull_request_review_comment", h)
+		case IssuesHandler:
+			return handle(ctx, event, "issue", h)
+		case IssueCommentHandler:
+			return handle(ctx, event, "issue comment", h)
+		case PushHandler:
+			return handle(ctx, event, "push", h)
+		case CheckRunHandler:
+			return handle(ctx, event, "check_run", h)
+		case CheckSuiteHandler:
+			return handle(ctx, event, "check_suite", h)
+		case MergeGroupHandler:
+			return handle(ctx, event, "merge_group", h)
+		case ProjectsV2ItemHandler:
+			return handle(ctx, event, "projects_v2_item", h)
+		case ReleaseHandler:
+			return handle(ctx, event, "release", h)
+		case CreateHandler:
+			return handle(ctx, event, "create", h)
+		case DeleteHandler:
+			return handle(ctx, event, "delete", h)
+		case StatusHandler:
+			return handle(ctx, event, "status", h)
+		case DeploymentHandler:
+			return handle(ctx, event, "deployment", h)
+		case DeploymentStatusHandler:
+			return handle(ctx, event, "deployment_status", h)
+		case InstallationHandler:
+			return handle(ctx, event, "installation", h)
+		case InstallationRepositoriesHandler:
+			return handle(ctx, event, "installation_repositories", h)
+		case RepositoryHandler:
+			return handle(ctx, event, "repository", h)
+		default:
+			return fmt.Errorf("unknown handler type %T", handler)
+		}
+	}
+
+	clog.FromContext(ctx).With("event", event).Debugf("ignoring event")
+	return nil
+}
+
+// handle decodes the data of event, a GitHub event wrapped by github-events,
+// and passes its body to h.
+func handle[E any](ctx context.Context, event cloudevents.Event, name string, h func(context.Context, E) error) error {
+	log := clog.FromContext(ctx)
+	log.Debugf("handling %s event", name)
+
+	var w schemas.Wrapper[E]
+	if err := event.DataAs(&w); err != nil {
+		log.Errorf("failed to unmarshal %s event: %v", name, err)
+		return err
+	}
+
+	if err := h(ctx, w.Body); err != nil {
+		log.Errorf("failed to handle %s event: %v", name, err)
+		return err
 	}
+	return nil
 }
 
 // AttributeFromContext retrieves an attribute by key from the context.
@@ -59,6 +59,18 @@ func NewBuilder(name, headSHA string) *Builder {
 	}
 }
 
+// NewMergeGroupBuilder returns a Builder for a check run on the head commit of
+// a merge queue's merge group. Required checks must report on this commit, or
+// the merge queue waits for them until it times out.
+func NewMergeGroupBuilder(name string, mge github.MergeGroupEvent) *Builder {
+	return NewBuilder(name, mge.GetMergeGroup().GetHeadSHA())
+}
+
+// HeadSHA returns the SHA of the commit the check run reports on.
+func (b *Builder) HeadSHA() string {
+	return b.headSHA
+}
+
 // Writef appends a formatted string to the CheckRun output.
 //
 // If the output exceeds the maximum length, it will be truncated and a message will be appended.
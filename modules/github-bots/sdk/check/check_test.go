@@ -95,3 +95,20 @@ func TestWritef(t *testing.T) {
 		t.Errorf("CheckRunCreate().Output.Text does not have truncation message, ends with %q", last100)
 	}
 }
+
+func TestMergeGroupBuilder(t *testing.T) {
+	b := NewMergeGroupBuilder("name", github.MergeGroupEvent{
+		Action: github.Ptr("checks_requested"),
+		MergeGroup: &github.MergeGroup{
+			HeadSHA: github.Ptr("mergeGroupSHA"),
+			HeadRef: github.Ptr("refs/heads/gh-readonly-queue/main/pr-1-headSHA"),
+			BaseRef: github.Ptr("refs/heads/main"),
+		},
+	})
+	if got, want := b.HeadSHA(), "mergeGroupSHA"; got != want {
+		t.Errorf("HeadSHA() = %q, want %q", got, want)
+	}
+	if got, want := b.CheckRunCreate().HeadSHA, "mergeGroupSHA"; got != want {
+		t.Errorf("CheckRunCreate().HeadSHA = %q, want %q", got, want)
+	}
+}
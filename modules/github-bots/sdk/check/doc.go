@@ -10,6 +10,10 @@ SPDX-License-Identifier: Apache-2.0
 // [Builder.Conclusion] fields, then call [Builder.CheckRunCreate] or
 // [Builder.CheckRunUpdate] to produce the GitHub API options struct.
 //
+// Checks required by a merge queue must also report on merge_group events;
+// use [NewMergeGroupBuilder] to build a check run for the merge group's head
+// commit.
+//
 // Output is automatically truncated to GitHub's maximum check run output
 // length of 65536 bytes.
 package check
@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/check"
+	"github.com/google/go-github/v88/github"
 )
 
 func ExampleNewBuilder() {
@@ -57,3 +58,22 @@ func ExampleBuilder_CheckRunUpdate() {
 	fmt.Println(u.GetConclusion())
 	// Output: failure
 }
+
+func ExampleNewMergeGroupBuilder() {
+	mge := github.MergeGroupEvent{
+		Action: github.Ptr("checks_requested"),
+		MergeGroup: &github.MergeGroup{
+			HeadSHA: github.Ptr("abc123"),
+			BaseRef: github.Ptr("refs/heads/main"),
+		},
+	}
+	b := check.NewMergeGroupBuilder("my-check", mge)
+	b.Conclusion = check.ConclusionSuccess
+
+	cr := b.CheckRunCreate()
+	fmt.Println(cr.HeadSHA)
+	fmt.Println(cr.GetConclusion())
+	// Output:
+	// abc123
+	// success
+}
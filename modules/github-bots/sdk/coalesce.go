@@ -0,0 +1,230 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/jonboulle/clockwork"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// coalescePendingEvents tracks events waiting for their debounce window
+	// or for an earlier event with the same key to finish
+	coalescePendingEvents = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "github_bots_coalesce_pending_events",
+			Help: "Current number of coalesced events waiting to be handled",
+		},
+		[]string{"event_type"},
+	)
+
+	// coalescedEvents tracks events superseded by a later event with the
+	// same key and type
+	coalescedEvents = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "github_bots_coalesced_events_total",
+			Help: "Total number of events superseded by a later event with the same key",
+		},
+		[]string{"event_type"},
+	)
+)
+
+// CoalesceKeyFunc extracts the coalescing key of an event. Events with an
+// empty key are handled immediately without coalescing.
+type CoalesceKeyFunc func(event cloudevents.Event) string
+
+// PullRequestURLKey keys events by the pull request they relate to, using the
+// pullrequesturl extension set by github-events.
+func PullRequestURLKey(event cloudevents.Event) string {
+	return ExtensionKey("pullrequesturl")(event)
+}
+
+// ExtensionKey keys events by the value of the given CloudEvents extension.
+func ExtensionKey(name string) CoalesceKeyFunc {
+	return func(event cloudevents.Event) string {
+		v, ok := event.Extensions()[name]
+		if !ok {
+			return ""
+		}
+		return fmt.Sprint(v)
+	}
+}
+
+// BotWithCoalescing coalesces events of the given type by the key returned by
+// key, for example [PullRequestURLKey].
+//
+// Events that share a key are handled one at a time, across all coalesced
+// event types. Each event waits out a debounce window (see
+// WithCoalesceDebounce) before it is handled, and an event arriving while an
+// earlier event of the same type and key is still waiting replaces it and
+// restarts the window, so only the latest event of a burst is handled. Superseded events are acknowledged with
+// the result of the event that replaced them.
+func BotWithCoalescing(etype EventType, key CoalesceKeyFunc) BotOptions {
+	return func(b *Bot) {
+		b.CoalesceKeys[etype] = key
+	}
+}
+
+// WithCoalesceDebounce sets how long coalesced events wait for later events
+// with the same key before they are handled. Each later event restarts the
+// wait. It defaults to 2 seconds.
+func WithCoalesceDebounce(d time.Duration) ServeOption {
+	return func(c *serveConfig) {
+		c.coalesceDebounce = d
+	}
+}
+
+// receiver returns the CloudEvents receiver of the bot, coalescing events as
+// configured with BotWithCoalescing.
+func (b Bot) receiver(cfg *serveConfig) func(context.Context, cloudevents.Event) error {
+	if len(b.CoalesceKeys) == 0 {
+		return b.handleEvent
+	}
+	co := newCoalescer(cfg.coalesceDebounce)
+	return func(ctx context.Context, event cloudevents.Event) error {
+		etype := EventType(event.Type())
+		keyFn, ok := b.CoalesceKeys[etype]
+		if !ok {
+			return b.handleEvent(ctx, event)
+		}
+		key := keyFn(event)
+		if key == "" {
+			return b.handleEvent(ctx, event)
+		}
+		return co.do(ctx, key, etype, func(ctx context.Context) error {
+			return b.handleEvent(ctx, event)
+		})
+	}
+}
+
+// coalescer serializes work by key, keeping only the latest pending work of
+// each event type.
+type coalescer struct {
+	debounce time.Duration
+	clock    clockwork.Clock
+
+	mu     sync.Mutex
+	queues map[string]*coalesceQueue
+}
+
+// coalesceQueue holds the pending work of a single key, in arrival order and
+// with at most one entry per event type. It exists while its key is being
+// drained.
+type coalesceQueue struct {
+	pending []*coalescedRun
+}
+
+type coalescedRun struct {
+	etype EventType
+
+	// readyAt is the end of the latest event's debounce window, and ctx
+	// and fn belong to it, guarded by coalescer.mu.
+	readyAt time.Time
+	ctx     context.Context
+	fn      func(context.Context) error
+
+	done chan struct{}
+	err  error
+}
+
+func newCoalescer(debounce time.Duration) *coalescer {
+	return &coalescer{
+		debounce: debounce,
+		clock:    clockwork.NewRealClock(),
+		queues:   make(map[string]*coalesceQueue),
+	}
+}
+
+// do queues fn under key and waits for it, or the work that superseded it,
+// to finish.
+func (c *coalescer) do(ctx context.Context, key string, etype EventType, fn func(context.Context) error) error {
+	c.mu.Lock()
+	q, draining := c.queues[key]
+	if !draining {
+		q = &coalesceQueue{}
+		c.queues[key] = q
+	}
+
+	var run *coalescedRun
+	for _, r := range q.pending {
+		if r.etype == etype {
+			run = r
+		}
+	}
+	if run != nil {
+		clog.DebugContextf(ctx, "coalescing %s event for %s", etype, key)
+		coalescedEvents.WithLabelValues(string(etype)).Inc()
+		run.readyAt = c.clock.Now().Add(c.debounce)
+		run.ctx, run.fn = ctx, fn
+	} else {
+		run = &coalescedRun{
+			etype:   etype,
+			readyAt: c.clock.Now().Add(c.debounce),
+			ctx:     ctx,
+			fn:      fn,
+			done:    make(chan struct{}),
+		}
+		q.pending = append(q.pending, run)
+		coalescePendingEvents.WithLabelValues(string(etype)).Inc()
+	}
+	c.mu.Unlock()
+
+	if !draining {
+		go c.drain(key, q)
+	}
+
+	select {
+	case <-run.done:
+		return run.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// drain runs the pending work of key one at a time until none is left.
+func (c *coalescer) drain(key string, q *coalesceQueue) {
+	for {
+		c.mu.Lock()
+		if len(q.pending) == 0 {
+			delete(c.queues, key)
+			c.mu.Unlock()
+			return
+		}
+		next := q.pending[0]
+		c.mu.Unlock()
+
+		// Later events of the same type replace next, pushing back when it
+		// is ready, until none arrives within the debounce window.
+		for {
+			c.mu.Lock()
+			wait := c.clock.Until(next.readyAt)
+			c.mu.Unlock()
+			if wait <= 0 {
+				break
+			}
+			<-c.clock.After(wait)
+		}
+
+		c.mu.Lock()
+		q.pending = q.pending[1:]
+		ctx, fn := next.ctx, next.fn
+		c.mu.Unlock()
+		coalescePendingEvents.WithLabelValues(string(next.etype)).Dec()
+
+		// The work outlives the request of the event that queued it if that
+		// event stops waiting.
+		next.err = fn(context.WithoutCancel(ctx))
+		close(next.done)
+	}
+}
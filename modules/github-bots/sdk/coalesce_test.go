@@ -0,0 +1,232 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-events/schemas"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-github/v88/github"
+	"github.com/jonboulle/clockwork"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// waitFor polls cond until it holds, failing the test after a few seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCoalescer_LatestWins(t *testing.T) {
+	ctx := context.Background()
+	const etype EventType = "test.latest_wins"
+	co := newCoalescer(time.Second)
+	clock := clockwork.NewFakeClock()
+	co.clock = clock
+
+	var mu sync.Mutex
+	var handled []int
+	errs := make(chan error, 3)
+	coalesced := testutil.ToFloat64(coalescedEvents.WithLabelValues(string(etype)))
+	for i := range 3 {
+		go func() {
+			errs <- co.do(ctx, "pr-1", etype, func(context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				handled = append(handled, i)
+				return errors.New("boom")
+			})
+		}()
+		if i == 0 {
+			if err := clock.BlockUntilContext(ctx, 1); err != nil {
+				t.Fatalf("BlockUntilContext: %v", err)
+			}
+		} else {
+			waitFor(t, "event to be coalesced", func() bool {
+				return testutil.ToFloat64(coalescedEvents.WithLabelValues(string(etype))) == coalesced+float64(i)
+			})
+		}
+	}
+	if got := testutil.ToFloat64(coalescePendingEvents.WithLabelValues(string(etype))); got != 1 {
+		t.Errorf("pending events = %v, want 1", got)
+	}
+
+	clock.Advance(time.Second)
+	// Every superseded event gets the result of the latest one.
+	for range 3 {
+		if err := <-errs; err == nil || err.Error() != "boom" {
+			t.Errorf("do() = %v, want boom", err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 1 || handled[0] != 2 {
+		t.Errorf("handled = %v, want [2]", handled)
+	}
+	if got := testutil.ToFloat64(coalescePendingEvents.WithLabelValues(string(etype))); got != 0 {
+		t.Errorf("pending events = %v, want 0", got)
+	}
+}
+
+func TestCoalescer_DebounceRestarts(t *testing.T) {
+	ctx := context.Background()
+	const etype EventType = "test.debounce_restarts"
+	co := newCoalescer(time.Second)
+	clock := clockwork.NewFakeClock()
+	co.clock = clock
+
+	var mu sync.Mutex
+	var handled []int
+	errs := make(chan error, 2)
+	handle := func(i int) {
+		errs <- co.do(ctx, "pr-1", etype, func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			handled = append(handled, i)
+			return nil
+		})
+	}
+	coalesced := testutil.ToFloat64(coalescedEvents.WithLabelValues(string(etype)))
+	go handle(0)
+	if err := clock.BlockUntilContext(ctx, 1); err != nil {
+		t.Fatalf("BlockUntilContext: %v", err)
+	}
+	clock.Advance(600 * time.Millisecond)
+	go handle(1)
+	waitFor(t, "event to be coalesced", func() bool {
+		return testutil.ToFloat64(coalescedEvents.WithLabelValues(string(etype))) == coalesced+1
+	})
+
+	// The first event's window has passed, but the second restarted it.
+	clock.Advance(400 * time.Millisecond)
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := clock.BlockUntilContext(waitCtx, 1); err != nil {
+		t.Fatalf("coalescer didn't wait out the restarted window: %v", err)
+	}
+	mu.Lock()
+	if len(handled) != 0 {
+		t.Errorf("handled = %v before the restarted window passed, want none", handled)
+	}
+	mu.Unlock()
+
+	clock.Advance(600 * time.Millisecond)
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Errorf("do() = %v", err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 1 || handled[0] != 1 {
+		t.Errorf("handled = %v, want [1]", handled)
+	}
+}
+
+func TestCoalescer_SerializesByKey(t *testing.T) {
+	ctx := context.Background()
+	const first, second EventType = "test.serial_first", "test.serial_second"
+	co := newCoalescer(time.Second)
+	clock := clockwork.NewFakeClock()
+	co.clock = clock
+
+	release := make(chan struct{})
+	firstStarted, secondStarted := make(chan struct{}), make(chan struct{})
+	errs := make(chan error, 2)
+	go func() {
+		errs <- co.do(ctx, "pr-1", first, func(context.Context) error {
+			close(firstStarted)
+			<-release
+			return nil
+		})
+	}()
+	if err := clock.BlockUntilContext(ctx, 1); err != nil {
+		t.Fatalf("BlockUntilContext: %v", err)
+	}
+	go func() {
+		errs <- co.do(ctx, "pr-1", second, func(context.Context) error {
+			close(secondStarted)
+			return nil
+		})
+	}()
+	waitFor(t, "second event to be queued", func() bool {
+		return testutil.ToFloat64(coalescePendingEvents.WithLabelValues(string(second))) == 1
+	})
+
+	clock.Advance(time.Second)
+	<-firstStarted
+	select {
+	case <-secondStarted:
+		t.Fatal("second event handled while the first was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-secondStarted
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Errorf("do() = %v", err)
+		}
+	}
+}
+
+func TestBot_ReceiverCoalescing(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var handled []int
+	bot := NewBot("test",
+		BotWithHandler(PullRequestHandler(func(_ context.Context, pre github.PullRequestEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			handled = append(handled, pre.GetNumber())
+			return nil
+		})),
+		BotWithCoalescing(PullRequestEvent, PullRequestURLKey),
+	)
+	receive := bot.receiver(&serveConfig{coalesceDebounce: 10 * time.Millisecond})
+
+	event := func(number int, url string) cloudevents.Event {
+		e := cloudevents.NewEvent()
+		e.SetID("id")
+		e.SetSource("test")
+		e.SetType(string(PullRequestEvent))
+		if url != "" {
+			e.SetExtension("pullrequesturl", url)
+		}
+		if err := e.SetData(cloudevents.ApplicationJSON, schemas.Wrapper[github.PullRequestEvent]{
+			Body: github.PullRequestEvent{Number: github.Ptr(number)},
+		}); err != nil {
+			t.Fatalf("SetData: %v", err)
+		}
+		return e
+	}
+
+	// Events without the key are handled as usual.
+	if err := receive(ctx, event(1, "")); err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	if err := receive(ctx, event(2, "https://github.com/acme/widgets/pull/2")); err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 2 || handled[0] != 1 || handled[1] != 2 {
+		t.Errorf("handled = %v, want [1 2]", handled)
+	}
+}
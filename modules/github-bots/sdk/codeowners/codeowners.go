@@ -0,0 +1,249 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package codeowners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk"
+	"github.com/google/go-github/v88/github"
+)
+
+// Docs for CODEOWNERS: https://docs.github.com/en/repositories/managing-your-repositorys-settings-and-features/customizing-your-repository/about-code-owners
+
+// Paths are the locations of a repository's CODEOWNERS file, in the order
+// GitHub looks for them. The first one that exists is used.
+var Paths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// ErrNotFound is returned by Load when a repository has no CODEOWNERS file.
+var ErrNotFound = errors.New("no CODEOWNERS file found")
+
+// Rule is a line of a CODEOWNERS file.
+type Rule struct {
+	Pattern string
+	// Owners are users ("@user"), teams ("@org/team") or email addresses.
+	// A rule without owners makes matching paths unowned.
+	Owners []string
+	// Line is the rule's line number in the file.
+	Line int
+
+	re *regexp.Regexp
+}
+
+// Match reports whether the rule's pattern matches path.
+func (r Rule) Match(path string) bool {
+	return r.re != nil && r.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// File is a parsed CODEOWNERS file.
+type File struct {
+	// Path is where the file was loaded from, if it was loaded with Load.
+	Path  string
+	Rules []Rule
+	// Errors lists the lines GitHub would also reject, which are skipped.
+	Errors []error
+}
+
+// Parse parses the content of a CODEOWNERS file. Lines using syntax GitHub
+// does not support are skipped and reported in File.Errors.
+func Parse(content string) *File {
+	f := &File{}
+	for i, line := range strings.Split(content, "\n") {
+		n := i + 1
+		if before, _, ok := strings.Cut(line, "#"); ok {
+			line = before
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		pattern, owners := fields[0], fields[1:]
+		re, err := compile(pattern)
+		if err != nil {
+			f.Errors = append(f.Errors, fmt.Errorf("line %d: %w", n, err))
+			continue
+		}
+		if i := slices.IndexFunc(owners, func(o string) bool {
+			return !strings.Contains(o, "@")
+		}); i >= 0 {
+			f.Errors = append(f.Errors, fmt.Errorf("line %d: invalid owner %q", n, owners[i]))
+			continue
+		}
+		f.Rules = append(f.Rules, Rule{Pattern: pattern, Owners: owners, Line: n, re: re})
+	}
+	return f
+}
+
+// Match returns the rule that applies to path: the last one matching it.
+func (f *File) Match(path string) (Rule, bool) {
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].Match(path) {
+			return f.Rules[i], true
+		}
+	}
+	return Rule{}, false
+}
+
+// Owners returns the owners of path, or nil if it is unowned.
+func (f *File) Owners(path string) []string {
+	r, _ := f.Match(path)
+	return r.Owners
+}
+
+// Result is the ownership of a set of paths.
+type Result struct {
+	// Owners maps each owned path to its owners.
+	Owners map[string][]string
+	// Reviewers is the sorted set of owners of all paths.
+	Reviewers []string
+	// Unowned lists the paths without owners, in the order given.
+	Unowned []string
+}
+
+// Resolve returns the ownership of paths.
+func (f *File) Resolve(paths []string) Result {
+	res := Result{Owners: make(map[string][]string, len(paths))}
+	for _, p := range paths {
+		owners := f.Owners(p)
+		if len(owners) == 0 {
+			res.Unowned = append(res.Unowned, p)
+			continue
+		}
+		res.Owners[p] = owners
+		res.Reviewers = append(res.Reviewers, owners...)
+	}
+	slices.Sort(res.Reviewers)
+	res.Reviewers = slices.Compact(res.Reviewers)
+	return res
+}
+
+// ChangedFiles returns the paths changed by a comparison, as returned by
+// GitHubClient.CompareCommits. Renamed files contribute both their previous
+// and their new path.
+func ChangedFiles(comparison *github.CommitsComparison) []string {
+	var paths []string
+	for _, f := range comparison.Files {
+		paths = append(paths, f.GetFilename())
+		if prev := f.GetPreviousFilename(); prev != "" && prev != f.GetFilename() {
+			paths = append(paths, prev)
+		}
+	}
+	return paths
+}
+
+// Load fetches and parses the CODEOWNERS file of owner/repo at ref, looking
+// in each of Paths in turn. It returns ErrNotFound if there is none.
+func Load(ctx context.Context, gh sdk.GitHubClient, owner, repo, ref string) (*File, error) {
+	for _, path := range Paths {
+		content, err := gh.GetFileContent(ctx, owner, repo, path, ref)
+		var gherr *github.ErrorResponse
+		switch {
+		case errors.As(err, &gherr) && gherr.Response != nil && gherr.Response.StatusCode == http.StatusNotFound:
+			continue
+		case err != nil:
+			return nil, err
+		}
+		f := Parse(content)
+		f.Path = path
+		return f, nil
+	}
+	return nil, ErrNotFound
+}
+
+// ExpandTeams replaces the teams among owners with their members, fetched
+// from GitHub, and returns the sorted set of resulting owners. Users and
+// email addresses are kept as they are.
+func ExpandTeams(ctx context.Context, gh sdk.GitHubClient, owners []string) ([]string, error) {
+	var expanded []string
+	for _, o := range owners {
+		org, slug, isTeam := strings.Cut(strings.TrimPrefix(o, "@"), "/")
+		if !strings.HasPrefix(o, "@") || !isTeam {
+			expanded = append(expanded, o)
+			continue
+		}
+		opts := &github.TeamListTeamMembersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+		for {
+			members, resp, err := gh.Client().Teams.ListTeamMembersBySlug(ctx, org, slug, opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list members of team %s: %w", o, err)
+			}
+			for _, m := range members {
+				expanded = append(expanded, "@"+m.GetLogin())
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+	}
+	slices.Sort(expanded)
+	return slices.Compact(expanded), nil
+}
+
+// compile translates a CODEOWNERS pattern to a regular expression matching
+// the paths it applies to, following gitignore rules with GitHub's
+// exceptions.
+func compile(pattern string) (*regexp.Regexp, error) {
+	switch {
+	case strings.HasPrefix(pattern, "!"):
+		return nil, fmt.Errorf("negated pattern %q is not supported", pattern)
+	case strings.ContainsAny(pattern, "[]"):
+		return nil, fmt.Errorf("character range in pattern %q is not supported", pattern)
+	}
+
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.Trim(pattern, "/")
+	// Patterns containing a slash other than a trailing one are relative to
+	// the repository root; others match at any depth.
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(p, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for rest := p; rest != ""; {
+		switch {
+		case strings.HasPrefix(rest, "**/"):
+			b.WriteString("(?:.*/)?")
+			rest = rest[3:]
+		case strings.HasPrefix(rest, "**"):
+			b.WriteString(".*")
+			rest = rest[2:]
+		case rest[0] == '*':
+			b.WriteString("[^/]*")
+			rest = rest[1:]
+		case rest[0] == '?':
+			b.WriteString("[^/]")
+			rest = rest[1:]
+		default:
+			n := strings.IndexAny(rest, "*?")
+			if n < 0 {
+				n = len(rest)
+			}
+			b.WriteString(regexp.QuoteMeta(rest[:n]))
+			rest = rest[n:]
+		}
+	}
+	switch {
+	case dirOnly:
+		// A directory's contents.
+		b.WriteString("/.*")
+	case strings.HasSuffix(p, "/*"):
+		// Unlike gitignore, "dir/*" does not match files in subdirectories.
+	default:
+		// The path itself, or the contents of a directory it names.
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
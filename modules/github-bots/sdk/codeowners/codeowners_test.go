@@ -0,0 +1,183 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package codeowners
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk"
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v88/github"
+)
+
+func TestRuleMatch(t *testing.T) {
+	for _, tt := range []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "a/b/c.txt", true},
+		{"*.js", "app.js", true},
+		{"*.js", "src/app/app.js", true},
+		{"*.js", "app.jsx", false},
+		{"/build/logs/", "build/logs/a.log", true},
+		{"/build/logs/", "build/logs/deep/a.log", true},
+		{"/build/logs/", "x/build/logs/a.log", false},
+		{"/build/logs/", "build/logs", false},
+		{"docs/*", "docs/getting-started.md", true},
+		{"docs/*", "docs/build-app/troubleshooting.md", false},
+		{"docs/*", "x/docs/a.md", false},
+		{"apps/", "apps/a.go", true},
+		{"apps/", "src/apps/a.go", true},
+		{"apps/", "apps", false},
+		{"/docs", "docs", true},
+		{"/docs", "docs/a/b.md", true},
+		{"/docs", "src/docs/a.md", false},
+		{"docs", "src/docs/a.md", true},
+		{"**/logs", "logs/a.log", true},
+		{"**/logs", "deploy/logs/a.log", true},
+		{"/scripts/**", "scripts/a/b.sh", true},
+		{"/scripts/**", "scripts", false},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file/.txt", false},
+		{"/README.md", "README.md", true},
+		{"/README.md", "docs/README.md", false},
+		{"/dots.in.name", "dotsxinxname", false},
+		{"/ünïcode/", "ünïcode/a", true},
+	} {
+		re, err := compile(tt.pattern)
+		if err != nil {
+			t.Fatalf("compile(%q): %v", tt.pattern, err)
+		}
+		if got := (Rule{Pattern: tt.pattern, re: re}).Match(tt.path); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	f := Parse(`# Owners
+*                 @org/everyone  # default owners
+/docs/            docs@example.com @writer
+!/docs/internal/  @org/security
+/src/[ab]/        @someone
+/bin/             not-an-owner
+/third_party/
+`)
+	type rule struct {
+		Pattern string
+		Owners  []string
+		Line    int
+	}
+	var got []rule
+	for _, r := range f.Rules {
+		got = append(got, rule{r.Pattern, r.Owners, r.Line})
+	}
+	want := []rule{
+		{"*", []string{"@org/everyone"}, 2},
+		{"/docs/", []string{"docs@example.com", "@writer"}, 3},
+		{"/third_party/", []string{}, 7},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("rules mismatch (-want +got):\n%s", diff)
+	}
+	if len(f.Errors) != 3 {
+		t.Errorf("got %d errors, want 3: %v", len(f.Errors), f.Errors)
+	}
+
+	// The last matching rule wins, even when it has no owners.
+	if got := f.Owners("docs/index.md"); !cmp.Equal(got, []string{"docs@example.com", "@writer"}) {
+		t.Errorf("Owners(docs/index.md) = %v", got)
+	}
+	if got := f.Owners("third_party/lib.go"); len(got) != 0 {
+		t.Errorf("Owners(third_party/lib.go) = %v, want none", got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	f := Parse(`
+*.go     @go @org/core
+/api/    @api
+/gen/
+`)
+	comparison := &github.CommitsComparison{Files: []*github.CommitFile{
+		{Filename: github.Ptr("main.go")},
+		{Filename: github.Ptr("api/v1/types.go"), PreviousFilename: github.Ptr("api/types.go")},
+		{Filename: github.Ptr("gen/zz.go")},
+		{Filename: github.Ptr("README.md")},
+	}}
+	paths := ChangedFiles(comparison)
+	if diff := cmp.Diff([]string{"main.go", "api/v1/types.go", "api/types.go", "gen/zz.go", "README.md"}, paths); diff != "" {
+		t.Errorf("ChangedFiles mismatch (-want +got):\n%s", diff)
+	}
+
+	got := f.Resolve(paths)
+	want := Result{
+		Owners: map[string][]string{
+			"main.go":         {"@go", "@org/core"},
+			"api/v1/types.go": {"@api"},
+			"api/types.go":    {"@api"},
+		},
+		Reviewers: []string{"@api", "@go", "@org/core"},
+		Unowned:   []string{"gen/zz.go", "README.md"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Resolve mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	gh := sdk.NewGitHubClient(ctx, "acme", "widgets", "test", sdk.WithClient(srv.Client()))
+
+	if _, err := Load(ctx, gh, "acme", "widgets", "main"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load() = %v, want ErrNotFound", err)
+	}
+
+	srv.SetFile("acme", "widgets", "main", "docs/CODEOWNERS", "* @docs")
+	srv.SetFile("acme", "widgets", "main", "CODEOWNERS", "* @root")
+	f, err := Load(ctx, gh, "acme", "widgets", "main")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if f.Path != "CODEOWNERS" {
+		t.Errorf("Path = %q, want CODEOWNERS", f.Path)
+	}
+
+	srv.SetFile("acme", "widgets", "main", ".github/CODEOWNERS", "* @github")
+	f, err = Load(ctx, gh, "acme", "widgets", "main")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := f.Owners("any/file"); !cmp.Equal(got, []string{"@github"}) {
+		t.Errorf("Owners = %v, want [@github]", got)
+	}
+}
+
+func TestExpandTeams(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.SetTeamMembers("org", "core", "alice", "bob")
+	gh := sdk.NewGitHubClient(ctx, "org", "repo", "test", sdk.WithClient(srv.Client()))
+
+	got, err := ExpandTeams(ctx, gh, []string{"@org/core", "@bob", "carol@example.com"})
+	if err != nil {
+		t.Fatalf("ExpandTeams: %v", err)
+	}
+	if diff := cmp.Diff([]string{"@alice", "@bob", "carol@example.com"}, got); diff != "" {
+		t.Errorf("ExpandTeams mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := ExpandTeams(ctx, gh, []string{"@org/missing"}); err == nil {
+		t.Error("ExpandTeams() = nil error for missing team")
+	}
+}
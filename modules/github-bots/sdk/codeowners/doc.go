@@ -0,0 +1,18 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package codeowners parses CODEOWNERS files and resolves the owners of the
+// paths a change touches, for bots that route reviews.
+//
+// [Load] fetches a repository's CODEOWNERS file from the first of [Paths]
+// that exists, and [Parse] parses one directly. Paths are matched like
+// GitHub does: patterns follow gitignore rules, except that negation and
+// character ranges are not supported, and the last matching rule wins.
+//
+// [File.Resolve] returns the owners of each path, the aggregated set of
+// reviewers and the unowned paths. Pass it the paths from [ChangedFiles] to
+// resolve the owners of a comparison, and expand teams to their members with
+// [ExpandTeams].
+package codeowners
@@ -0,0 +1,31 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package codeowners_test
+
+import (
+	"fmt"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/codeowners"
+)
+
+func ExampleParse() {
+	f := codeowners.Parse(`
+*          @org/everyone
+*.go       @org/go-reviewers
+/docs/     @docs-owner
+/vendor/
+`)
+	res := f.Resolve([]string{"main.go", "docs/index.md", "vendor/x/y.go", "README.md"})
+	fmt.Println(res.Owners["main.go"])
+	fmt.Println(res.Owners["docs/index.md"])
+	fmt.Println(res.Reviewers)
+	fmt.Println(res.Unowned)
+	// Output:
+	// [@org/go-reviewers]
+	// [@docs-owner]
+	// [@docs-owner @org/everyone @org/go-reviewers]
+	// [vendor/x/y.go]
+}
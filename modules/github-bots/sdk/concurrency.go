@@ -0,0 +1,304 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/go-github/v88/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/semaphore"
+)
+
+var (
+	// concurrencyWaiting tracks goroutines waiting for a concurrency permit
+	concurrencyWaiting = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "github_bots_concurrency_waiting",
+			Help: "Current number of goroutines waiting for a per-repository concurrency permit",
+		},
+		[]string{"operation"},
+	)
+
+	// concurrencyInFlight tracks goroutines holding a concurrency permit
+	concurrencyInFlight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "github_bots_concurrency_in_flight",
+			Help: "Current number of goroutines holding a per-repository concurrency permit",
+		},
+		[]string{"operation"},
+	)
+)
+
+// Operation is the kind of operation a concurrency permit is held for.
+type Operation int
+
+const (
+	// ReadOperation permits are held while reading from GitHub.
+	ReadOperation Operation = iota
+	// WriteOperation permits are held while mutating GitHub state.
+	WriteOperation
+)
+
+func (o Operation) String() string {
+	if o == WriteOperation {
+		return "write"
+	}
+	return "read"
+}
+
+// ConcurrencyKey identifies the repository, and optionally the issue or pull
+// request, an operation acts on.
+type ConcurrencyKey struct {
+	Owner, Repo string
+	// Number is the issue or pull request number, or 0 for the repository.
+	Number int
+}
+
+func (k ConcurrencyKey) String() string {
+	if k.Number == 0 {
+		return k.Owner + "/" + k.Repo
+	}
+	return fmt.Sprintf("%s/%s#%d", k.Owner, k.Repo, k.Number)
+}
+
+// ConcurrencyLimiter bounds the number of concurrent read and write
+// operations on each repository, or on each issue and pull request with
+// WithPerPullRequestLimits. This keeps bots that handle many events for one
+// repository under GitHub's secondary rate limits for content creation, and
+// stops them from racing themselves, for example when two SetComment calls
+// both create a comment.
+//
+// Permits are held either around whole handlers, by wrapping them with the
+// limiter's handler methods, or inside GitHubClient's methods, by passing the
+// limiter to WithConcurrencyLimiter. Permits are reentrant: a handler holding
+// a permit does not wait for another of the same kind on the same key.
+type ConcurrencyLimiter struct {
+	readLimit, writeLimit int64
+	perPullRequest        bool
+
+	mu   sync.Mutex
+	sems map[concurrencySemKey]*concurrencySem
+}
+
+type concurrencySemKey struct {
+	key ConcurrencyKey
+	op  Operation
+}
+
+type concurrencySem struct {
+	sem  *semaphore.Weighted
+	refs int
+}
+
+// ConcurrencyLimiterOption configures a ConcurrencyLimiter.
+type ConcurrencyLimiterOption func(*ConcurrencyLimiter)
+
+// WithReadLimit sets the number of concurrent read operations allowed per
+// key. It defaults to 10.
+func WithReadLimit(n int) ConcurrencyLimiterOption {
+	return func(l *ConcurrencyLimiter) {
+		l.readLimit = int64(n)
+	}
+}
+
+// WithWriteLimit sets the number of concurrent write operations allowed per
+// key. It defaults to 1.
+func WithWriteLimit(n int) ConcurrencyLimiterOption {
+	return func(l *ConcurrencyLimiter) {
+		l.writeLimit = int64(n)
+	}
+}
+
+// WithPerPullRequestLimits applies limits to each issue and pull request
+// rather than to each repository.
+func WithPerPullRequestLimits() ConcurrencyLimiterOption {
+	return func(l *ConcurrencyLimiter) {
+		l.perPullRequest = true
+	}
+}
+
+// NewConcurrencyLimiter returns a ConcurrencyLimiter.
+func NewConcurrencyLimiter(opts ...ConcurrencyLimiterOption) *ConcurrencyLimiter {
+	l := &ConcurrencyLimiter{
+		readLimit:  10,
+		writeLimit: 1,
+		sems:       make(map[concurrencySemKey]*concurrencySem),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// heldPermit records a permit held by a context, so nested acquisitions of
+// the same permit do not deadlock.
+type heldPermit struct {
+	limiter *ConcurrencyLimiter
+	key     concurrencySemKey
+	parent  *heldPermit
+}
+
+type heldPermitKey struct{}
+
+// Acquire waits for a permit for op on key, and returns a context recording
+// that it is held and a function that releases it. If ctx already holds the
+// permit, Acquire returns immediately.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context, key ConcurrencyKey, op Operation) (context.Context, func(), error) {
+	if !l.perPullRequest {
+		key.Number = 0
+	}
+	sk := concurrencySemKey{key: key, op: op}
+
+	parent, _ := ctx.Value(heldPermitKey{}).(*heldPermit)
+	for p := parent; p != nil; p = p.parent {
+		if p.limiter == l && p.key == sk {
+			return ctx, func() {}, nil
+		}
+	}
+
+	l.mu.Lock()
+	s, ok := l.sems[sk]
+	if !ok {
+		limit := l.readLimit
+		if op == WriteOperation {
+			limit = l.writeLimit
+		}
+		s = &concurrencySem{sem: semaphore.NewWeighted(max(limit, 1))}
+		l.sems[sk] = s
+	}
+	s.refs++
+	l.mu.Unlock()
+
+	waiting := concurrencyWaiting.WithLabelValues(op.String())
+	waiting.Inc()
+	err := s.sem.Acquire(ctx, 1)
+	waiting.Dec()
+	if err != nil {
+		l.unref(sk, s)
+		return ctx, nil, fmt.Errorf("waiting for %s permit on %s: %w", op, key, err)
+	}
+
+	inFlight := concurrencyInFlight.WithLabelValues(op.String())
+	inFlight.Inc()
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			inFlight.Dec()
+			s.sem.Release(1)
+			l.unref(sk, s)
+		})
+	}
+	return context.WithValue(ctx, heldPermitKey{}, &heldPermit{limiter: l, key: sk, parent: parent}), release, nil
+}
+
+// unref drops the semaphore of sk once nobody holds or waits for it.
+func (l *ConcurrencyLimiter) unref(sk concurrencySemKey, s *concurrencySem) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s.refs--
+	if s.refs == 0 {
+		delete(l.sems, sk)
+	}
+}
+
+// limitHandler wraps h so that it holds a permit for op on the key returned
+// by key while it runs.
+func limitHandler[E any](l *ConcurrencyLimiter, op Operation, key func(E) ConcurrencyKey, h func(context.Context, E) error) func(context.Context, E) error {
+	return func(ctx context.Context, event E) error {
+		ctx, release, err := l.Acquire(ctx, key(event), op)
+		if err != nil {
+			return err
+		}
+		defer release()
+		return h(ctx, event)
+	}
+}
+
+func repoConcurrencyKey(repo interface {
+	GetOwner() *github.User
+	GetName() string
+}, number int) ConcurrencyKey {
+	return ConcurrencyKey{Owner: repo.GetOwner().GetLogin(), Repo: repo.GetName(), Number: number}
+}
+
+// PullRequestHandler wraps h to hold a permit for op on the pull request.
+func (l *ConcurrencyLimiter) PullRequestHandler(op Operation, h PullRequestHandler) PullRequestHandler {
+	return limitHandler(l, op, func(pre github.PullRequestEvent) ConcurrencyKey {
+		return repoConcurrencyKey(pre.GetRepo(), pre.GetNumber())
+	}, h)
+}
+
+// IssueCommentHandler wraps h to hold a permit for op on the issue or pull
+// request commented on.
+func (l *ConcurrencyLimiter) IssueCommentHandler(op Operation, h IssueCommentHandler) IssueCommentHandler {
+	return limitHandler(l, op, func(ice github.IssueCommentEvent) ConcurrencyKey {
+		return repoConcurrencyKey(ice.GetRepo(), ice.GetIssue().GetNumber())
+	}, h)
+}
+
+// CheckRunHandler wraps h to hold a permit for op on the repository, or on
+// the check run's first pull request with WithPerPullRequestLimits.
+func (l *ConcurrencyLimiter) CheckRunHandler(op Operation, h CheckRunHandler) CheckRunHandler {
+	return limitHandler(l, op, func(cre github.CheckRunEvent) ConcurrencyKey {
+		var number int
+		if prs := cre.GetCheckRun().PullRequests; len(prs) > 0 {
+			number = prs[0].GetNumber()
+		}
+		return repoConcurrencyKey(cre.GetRepo(), number)
+	}, h)
+}
+
+// CheckSuiteHandler wraps h to hold a permit for op on the repository, or on
+// the check suite's first pull request with WithPerPullRequestLimits.
+func (l *ConcurrencyLimiter) CheckSuiteHandler(op Operation, h CheckSuiteHandler) CheckSuiteHandler {
+	return limitHandler(l, op, func(cse github.CheckSuiteEvent) ConcurrencyKey {
+		var number int
+		if prs := cse.GetCheckSuite().PullRequests; len(prs) > 0 {
+			number = prs[0].GetNumber()
+		}
+		return repoConcurrencyKey(cse.GetRepo(), number)
+	}, h)
+}
+
+// PushHandler wraps h to hold a permit for op on the repository.
+func (l *ConcurrencyLimiter) PushHandler(op Operation, h PushHandler) PushHandler {
+	return limitHandler(l, op, func(pe github.PushEvent) ConcurrencyKey {
+		owner := pe.GetRepo().GetOwner().GetLogin()
+		if owner == "" {
+			owner = pe.GetRepo().GetOwner().GetName()
+		}
+		return ConcurrencyKey{Owner: owner, Repo: pe.GetRepo().GetName()}
+	}, h)
+}
+
+// WorkflowRunHandler wraps h to hold a permit for op on the repository.
+func (l *ConcurrencyLimiter) WorkflowRunHandler(op Operation, h WorkflowRunHandler) WorkflowRunHandler {
+	return limitHandler(l, op, func(wre github.WorkflowRunEvent) ConcurrencyKey {
+		return repoConcurrencyKey(wre.GetRepo(), 0)
+	}, h)
+}
+
+// WithConcurrencyLimiter makes the client hold permits of l in its methods:
+// write permits on the issue or pull request in methods that mutate it, and
+// read permits on the repository in methods that read its contents.
+func WithConcurrencyLimiter(l *ConcurrencyLimiter) GitHubClientOption {
+	return func(c *GitHubClient) {
+		c.limiter = l
+	}
+}
+
+// acquire holds a permit of the client's ConcurrencyLimiter, if it has one,
+// until the returned function is called.
+func (c GitHubClient) acquire(ctx context.Context, owner, repo string, number int, op Operation) (context.Context, func(), error) {
+	if c.limiter == nil {
+		return ctx, func() {}, nil
+	}
+	return c.limiter.Acquire(ctx, ConcurrencyKey{Owner: owner, Repo: repo, Number: number}, op)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	"github.com/google/go-github/v88/github"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestConcurrencyLimiter_Acquire(t *testing.T) {
+	ctx := context.Background()
+	pr1 := ConcurrencyKey{Owner: "acme", Repo: "widgets", Number: 1}
+	pr2 := ConcurrencyKey{Owner: "acme", Repo: "widgets", Number: 2}
+
+	tests := []struct {
+		name       string
+		opts       []ConcurrencyLimiterOption
+		first      ConcurrencyKey
+		firstOp    Operation
+		second     ConcurrencyKey
+		secondOp   Operation
+		wantBlocks bool
+	}{{
+		name:  "writes to a repository are serialized",
+		first: pr1, firstOp: WriteOperation,
+		second: pr2, secondOp: WriteOperation,
+		wantBlocks: true,
+	}, {
+		name:  "writes to different pull requests with per pull request limits",
+		opts:  []ConcurrencyLimiterOption{WithPerPullRequestLimits()},
+		first: pr1, firstOp: WriteOperation,
+		second: pr2, secondOp: WriteOperation,
+		wantBlocks: false,
+	}, {
+		name:  "reads are not limited by writes",
+		first: pr1, firstOp: WriteOperation,
+		second: pr1, secondOp: ReadOperation,
+		wantBlocks: false,
+	}, {
+		name:  "read limit",
+		opts:  []ConcurrencyLimiterOption{WithReadLimit(1)},
+		first: pr1, firstOp: ReadOperation,
+		second: pr2, secondOp: ReadOperation,
+		wantBlocks: true,
+	}, {
+		name:  "write limit",
+		opts:  []ConcurrencyLimiterOption{WithWriteLimit(2)},
+		first: pr1, firstOp: WriteOperation,
+		second: pr1, secondOp: WriteOperation,
+		wantBlocks: false,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewConcurrencyLimiter(tt.opts...)
+			_, release, err := l.Acquire(ctx, tt.first, tt.firstOp)
+			if err != nil {
+				t.Fatalf("Acquire: %v", err)
+			}
+
+			tctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+			defer cancel()
+			_, release2, err := l.Acquire(tctx, tt.second, tt.secondOp)
+			if gotBlocks := err != nil; gotBlocks != tt.wantBlocks {
+				t.Errorf("second Acquire blocked = %v (%v), want %v", gotBlocks, err, tt.wantBlocks)
+			}
+			if err == nil {
+				release2()
+			}
+
+			release()
+			if _, release, err := l.Acquire(ctx, tt.second, tt.secondOp); err != nil {
+				t.Errorf("Acquire after release: %v", err)
+			} else {
+				release()
+			}
+			if n := len(l.sems); n != 0 {
+				t.Errorf("limiter holds %d semaphores after release, want 0", n)
+			}
+		})
+	}
+}
+
+func TestConcurrencyLimiter_Reentrant(t *testing.T) {
+	l := NewConcurrencyLimiter()
+	key := ConcurrencyKey{Owner: "acme", Repo: "widgets"}
+
+	before := testutil.ToFloat64(concurrencyWaiting.WithLabelValues("write"))
+	ctx, release, err := l.Acquire(context.Background(), key, WriteOperation)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer release()
+
+	tctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if _, release, err := l.Acquire(tctx, key, WriteOperation); err != nil {
+		t.Errorf("nested Acquire: %v", err)
+	} else {
+		release()
+	}
+	if got := testutil.ToFloat64(concurrencyWaiting.WithLabelValues("write")); got != before {
+		t.Errorf("waiting = %v, want %v", got, before)
+	}
+}
+
+func TestConcurrencyLimiter_SetComment(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{})
+	l := NewConcurrencyLimiter()
+	c := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()), WithConcurrencyLimiter(l))
+
+	// The handler holds the permit, and so do the client's methods it calls.
+	h := l.PullRequestHandler(WriteOperation, func(ctx context.Context, pre github.PullRequestEvent) error {
+		return c.SetComment(ctx, pre.GetPullRequest(), "test-bot", fmt.Sprintf("run %d", pre.GetNumber()))
+	})
+
+	var wg sync.WaitGroup
+	for i := range 5 {
+		wg.Go(func() {
+			if err := c.SetComment(ctx, pr, "test-bot", fmt.Sprintf("call %d", i)); err != nil {
+				t.Errorf("SetComment: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if err := h(ctx, github.PullRequestEvent{
+		Number:      pr.Number,
+		Repo:        pr.Base.Repo,
+		PullRequest: pr,
+	}); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+
+	if got := len(srv.Comments("acme", "widgets", pr.GetNumber())); got != 1 {
+		t.Errorf("got %d comments, want 1", got)
+	}
+	srv.AssertComment("acme", "widgets", pr.GetNumber(), fmt.Sprintf("run %d", pr.GetNumber()))
+}
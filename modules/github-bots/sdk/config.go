@@ -0,0 +1,231 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/google/go-github/v88/github"
+	"github.com/jonboulle/clockwork"
+	"gopkg.in/yaml.v3"
+)
+
+// orgConfigRepo is the repository holding organization-wide defaults.
+const orgConfigRepo = ".github"
+
+// ConfigLoader loads a per-repository bot configuration file of type T from
+// the default branch of a repository, falling back to the same path in the
+// organization's .github repository when the repository has none.
+//
+// Decoded configurations are cached by blob SHA, and the blob SHA resolved
+// for each repository is cached until a push to its default branch changes
+// the file (see ConfigLoader.HandlePush) or the TTL expires.
+type ConfigLoader[T any] struct {
+	path  string
+	ttl   time.Duration
+	clock clockwork.Clock
+
+	mu      sync.Mutex
+	sources map[string]configSource // "owner/repo" -> resolved file
+	decoded map[string]T            // blob SHA -> decoded config
+}
+
+// configSource records the file found in a single repository, if any.
+type configSource struct {
+	found   bool
+	sha     string
+	fetched time.Time
+}
+
+type configLoaderOptions struct {
+	path string
+	ttl  time.Duration
+}
+
+// ConfigLoaderOption configures a ConfigLoader.
+type ConfigLoaderOption func(*configLoaderOptions)
+
+// WithConfigPath sets the path of the configuration file within each
+// repository. It defaults to .github/chainguard/<bot>.yaml.
+func WithConfigPath(path string) ConfigLoaderOption {
+	return func(o *configLoaderOptions) {
+		o.path = path
+	}
+}
+
+// WithConfigTTL sets how long a resolved configuration file is trusted
+// without seeing a push that changes it. It defaults to 10 minutes, which
+// bounds staleness when push events are missed.
+func WithConfigTTL(d time.Duration) ConfigLoaderOption {
+	return func(o *configLoaderOptions) {
+		o.ttl = d
+	}
+}
+
+// NewConfigLoader returns a ConfigLoader for the bot with the given name.
+func NewConfigLoader[T any](botName string, opts ...ConfigLoaderOption) *ConfigLoader[T] {
+	o := &configLoaderOptions{
+		path: fmt.Sprintf(".github/chainguard/%s.yaml", botName),
+		ttl:  10 * time.Minute,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &ConfigLoader[T]{
+		path:    strings.TrimPrefix(o.path, "/"),
+		ttl:     o.ttl,
+		clock:   clockwork.NewRealClock(),
+		sources: make(map[string]configSource),
+		decoded: make(map[string]T),
+	}
+}
+
+// Path returns the path of the configuration file within each repository.
+func (l *ConfigLoader[T]) Path() string { return l.path }
+
+// Load returns the configuration of owner/repo, read with the given client.
+// If neither the repository nor the organization's .github repository has a
+// configuration file, Load returns the zero value of T and found is false.
+//
+// The returned value is shared with other callers, so any maps, slices or
+// pointers within it must not be modified.
+func (l *ConfigLoader[T]) Load(ctx context.Context, gh GitHubClient, owner, repo string) (cfg T, found bool, err error) {
+	src, cfg, err := l.resolve(ctx, gh, owner, repo)
+	if err != nil {
+		return cfg, false, err
+	}
+	if !src.found && repo != orgConfigRepo {
+		clog.DebugContextf(ctx, "no %s in %s/%s, falling back to %s/%s", l.path, owner, repo, owner, orgConfigRepo)
+		if src, cfg, err = l.resolve(ctx, gh, owner, orgConfigRepo); err != nil {
+			return cfg, false, err
+		}
+	}
+	return cfg, src.found, nil
+}
+
+// resolve returns the configuration file of a single repository and its
+// decoded contents, fetching and decoding it if it is not cached.
+func (l *ConfigLoader[T]) resolve(ctx context.Context, gh GitHubClient, owner, repo string) (configSource, T, error) {
+	key := owner + "/" + repo
+	var cfg T
+
+	l.mu.Lock()
+	src, ok := l.sources[key]
+	if ok && l.clock.Since(src.fetched) < l.ttl {
+		cfg = l.decoded[src.sha]
+		l.mu.Unlock()
+		return src, cfg, nil
+	}
+	l.mu.Unlock()
+
+	file, _, resp, err := gh.inner.Repositories.GetContents(ctx, owner, repo, l.path, nil)
+	switch {
+	case resp != nil && resp.StatusCode == http.StatusNotFound:
+		src = configSource{fetched: l.clock.Now()}
+
+	default:
+		if err := validateResponse(ctx, err, resp, fmt.Sprintf("get %s from %s", l.path, key)); err != nil {
+			return configSource{}, cfg, err
+		}
+		if file == nil {
+			return configSource{}, cfg, fmt.Errorf("%s in %s is not a file", l.path, key)
+		}
+		src = configSource{found: true, sha: file.GetSHA(), fetched: l.clock.Now()}
+
+		l.mu.Lock()
+		cfg, ok = l.decoded[src.sha]
+		l.mu.Unlock()
+		if !ok {
+			content, err := file.GetContent()
+			if err != nil {
+				return configSource{}, cfg, fmt.Errorf("failed to decode content of %s in %s: %w", l.path, key, err)
+			}
+			if err := yaml.Unmarshal([]byte(content), &cfg); err != nil {
+				return configSource{}, cfg, fmt.Errorf("failed to parse %s in %s: %w", l.path, key, err)
+			}
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sources[key] = src
+	if src.found {
+		l.decoded[src.sha] = cfg
+	}
+	l.pruneLocked()
+	return src, cfg, nil
+}
+
+// Invalidate drops the cached configuration file of owner/repo. Invalidating
+// an organization's .github repository affects every repository in the
+// organization that falls back to it.
+func (l *ConfigLoader[T]) Invalidate(owner, repo string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.sources, owner+"/"+repo)
+	l.pruneLocked()
+}
+
+// pruneLocked drops decoded configurations no repository refers to.
+func (l *ConfigLoader[T]) pruneLocked() {
+	inUse := make(map[string]bool, len(l.sources))
+	for _, src := range l.sources {
+		inUse[src.sha] = true
+	}
+	for sha := range l.decoded {
+		if !inUse[sha] {
+			delete(l.decoded, sha)
+		}
+	}
+}
+
+// HandlePush invalidates the cached configuration of the pushed repository
+// when the push changes the configuration file on its default branch. Bots
+// that handle push events themselves should call it from their handler;
+// other bots can register ConfigLoader.PushHandler.
+func (l *ConfigLoader[T]) HandlePush(ctx context.Context, pe github.PushEvent) {
+	owner, repo := pe.GetRepo().GetOwner().GetLogin(), pe.GetRepo().GetName()
+	if owner == "" {
+		// Pushes carry the owner's name rather than its login.
+		owner = pe.GetRepo().GetOwner().GetName()
+	}
+	if pe.GetRef() != "refs/heads/"+pe.GetRepo().GetDefaultBranch() || !l.pushTouchesConfig(pe) {
+		return
+	}
+	clog.InfoContextf(ctx, "%s changed in %s/%s, invalidating cached config", l.path, owner, repo)
+	l.Invalidate(owner, repo)
+}
+
+// PushHandler returns a PushHandler that calls ConfigLoader.HandlePush.
+func (l *ConfigLoader[T]) PushHandler() PushHandler {
+	return func(ctx context.Context, pe github.PushEvent) error {
+		l.HandlePush(ctx, pe)
+		return nil
+	}
+}
+
+func (l *ConfigLoader[T]) pushTouchesConfig(pe github.PushEvent) bool {
+	// Push events list at most 20 commits, so assume the file changed when
+	// the list may be incomplete.
+	if len(pe.Commits) == 0 || len(pe.Commits) >= 20 || pe.GetForced() {
+		return true
+	}
+	for _, c := range pe.Commits {
+		for _, files := range [][]string{c.Added, c.Modified, c.Removed} {
+			if slices.Contains(files, l.path) {
+				return true
+			}
+		}
+	}
+	return false
+}
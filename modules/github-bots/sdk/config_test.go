@@ -0,0 +1,129 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	"github.com/google/go-github/v88/github"
+	"github.com/jonboulle/clockwork"
+)
+
+type testBotConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	Labels  []string `yaml:"labels"`
+}
+
+const testConfigPath = "/repos/acme/widgets/contents/.github/chainguard/test-bot.yaml"
+
+func TestConfigLoader(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.SetFile("acme", "widgets", "main", ".github/chainguard/test-bot.yaml", "enabled: true\nlabels: [a, b]\n")
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+
+	l := NewConfigLoader[testBotConfig]("test-bot")
+	clock := clockwork.NewFakeClock()
+	l.clock = clock
+
+	for range 3 {
+		cfg, found, err := l.Load(ctx, gh, "acme", "widgets")
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if !found || !cfg.Enabled || len(cfg.Labels) != 2 {
+			t.Errorf("Load = %+v, %v, want enabled with 2 labels", cfg, found)
+		}
+	}
+	srv.AssertRequestCount(http.MethodGet, testConfigPath, 1)
+
+	// A push to another file leaves the cache alone.
+	push := func(files ...string) github.PushEvent {
+		return github.PushEvent{
+			Ref: github.Ptr("refs/heads/main"),
+			Repo: &github.PushEventRepository{
+				Name:          github.Ptr("widgets"),
+				Owner:         &github.User{Login: github.Ptr("acme")},
+				DefaultBranch: github.Ptr("main"),
+			},
+			Commits: []*github.HeadCommit{{Modified: files}},
+		}
+	}
+	l.HandlePush(ctx, push("README.md"))
+	srv.SetFile("acme", "widgets", "main", ".github/chainguard/test-bot.yaml", "enabled: false\n")
+	if cfg, _, err := l.Load(ctx, gh, "acme", "widgets"); err != nil || !cfg.Enabled {
+		t.Errorf("Load after unrelated push = %+v, %v, want cached config", cfg, err)
+	}
+
+	// A push to the config file invalidates it.
+	l.HandlePush(ctx, push(".github/chainguard/test-bot.yaml"))
+	if cfg, _, err := l.Load(ctx, gh, "acme", "widgets"); err != nil || cfg.Enabled {
+		t.Errorf("Load after push = %+v, %v, want updated config", cfg, err)
+	}
+	srv.AssertRequestCount(http.MethodGet, testConfigPath, 2)
+
+	// The TTL bounds staleness when pushes are missed.
+	srv.SetFile("acme", "widgets", "main", ".github/chainguard/test-bot.yaml", "enabled: true\n")
+	clock.Advance(11 * time.Minute)
+	if cfg, _, err := l.Load(ctx, gh, "acme", "widgets"); err != nil || !cfg.Enabled {
+		t.Errorf("Load after TTL = %+v, %v, want updated config", cfg, err)
+	}
+	srv.AssertRequestCount(http.MethodGet, testConfigPath, 3)
+}
+
+func TestConfigLoader_OrgFallback(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.SetFile("acme", ".github", "main", "bots/test.yaml", "enabled: true\n")
+	srv.SetFile("acme", "gadgets", "main", "bots/test.yaml", "enabled: false\n")
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+
+	l := NewConfigLoader[testBotConfig]("test-bot", WithConfigPath("bots/test.yaml"))
+
+	for _, tc := range []struct {
+		repo        string
+		wantEnabled bool
+	}{
+		{repo: "widgets", wantEnabled: true},
+		{repo: "sprockets", wantEnabled: true},
+		{repo: "gadgets", wantEnabled: false},
+	} {
+		cfg, found, err := l.Load(ctx, gh, "acme", tc.repo)
+		if err != nil {
+			t.Fatalf("Load(%s): %v", tc.repo, err)
+		}
+		if !found || cfg.Enabled != tc.wantEnabled {
+			t.Errorf("Load(%s) = %+v, %v, want enabled=%v", tc.repo, cfg, found, tc.wantEnabled)
+		}
+	}
+	// The org-level file is fetched once for both repositories without one.
+	srv.AssertRequestCount(http.MethodGet, "/repos/acme/.github/contents/bots/test.yaml", 1)
+
+	// Nothing anywhere.
+	cfg, found, err := l.Load(ctx, gh, "other", "repo")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if found || cfg.Enabled {
+		t.Errorf("Load = %+v, %v, want zero config", cfg, found)
+	}
+}
+
+func TestConfigLoader_InvalidConfig(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.SetFile("acme", "widgets", "main", ".github/chainguard/test-bot.yaml", "enabled: [\n")
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+
+	l := NewConfigLoader[testBotConfig]("test-bot")
+	if _, _, err := l.Load(ctx, gh, "acme", "widgets"); err == nil {
+		t.Error("Load of invalid YAML succeeded")
+	}
+}
@@ -21,6 +21,17 @@ SPDX-License-Identifier: Apache-2.0
 //   - [PushHandler] — push events
 //   - [CheckRunHandler] — check run events
 //   - [CheckSuiteHandler] — check suite events
+//   - [MergeGroupHandler] — merge queue merge group events
+//   - [WorkflowJobHandler] — workflow job events
+//   - [PullRequestReviewHandler] — pull request review events
+//   - [PullRequestReviewCommentHandler] — pull request review comment events
+//   - [ReleaseHandler] — release events
+//   - [CreateHandler] and [DeleteHandler] — branch and tag creation and deletion
+//   - [StatusHandler] — commit status events
+//   - [DeploymentHandler] and [DeploymentStatusHandler] — deployment events
+//   - [InstallationHandler] and [InstallationRepositoriesHandler] — GitHub App
+//     installation events
+//   - [RepositoryHandler] — repository events
 //
 // # Serving
 //
@@ -28,9 +39,93 @@ SPDX-License-Identifier: Apache-2.0
 // to the PORT environment variable, or 8080 if unset. Use [WithPort] to
 // override the port programmatically.
 //
+// [ServeWebhook] instead receives GitHub webhooks directly, for small
+// deployments and local development without the github-events broker. It
+// converts them into the same CloudEvents the broker delivers, so handlers
+// don't change between the two. [WebhookHandler] returns its handler.
+//
+// [BotWithCoalescing] coalesces bursts of events that share a key, such as
+// the pull request they relate to ([PullRequestURLKey]). Events with the same
+// key are debounced, each restarting the wait, and handled one at a time,
+// and only the latest event of each type is handled.
+//
+// [StaleEventGuard] wraps handlers to drop events whose payload is older than
+// one already processed for the same resource, recording versions in memory
+// or in Valkey.
+//
+// [ConcurrencyLimiter] bounds concurrent reads and writes per repository or
+// pull request, around whole handlers or inside [GitHubClient] methods with
+// [WithConcurrencyLimiter].
+//
+// [Resyncer] replays the bot's handlers over open pull requests and issues
+// with the "resync" action, to catch up on missed webhooks from a cron job.
+//
+// [BotWithFailureReporting] reports handlers that panic or return a
+// [PermanentError], made with [Permanent], on the pull request of the event,
+// in a failing check run or, with [WithFailureComments], a sticky comment.
+// Reported events are acknowledged rather than retried. The next successful
+// run for the pull request clears the failure.
+//
 // # GitHub Clients
 //
 // [NewGitHubClient] creates an authenticated GitHub API client using OctoSTS
-// for token management. [NewInstallationClient] creates a client using a
-// GitHub App installation transport.
+// for token management. [ClientPool] shares these clients across events. It
+// leaves refreshed tokens to expire, as other events may still be using
+// them, and revokes the current token when a client is evicted or the pool
+// is closed. [GitHubClient.GitAuth] reads the current token on every git
+// request, so long clones survive refreshes.
+//
+// [NewInstallationClient] creates a client using a GitHub App installation
+// transport. [NewInstallationClientFactory] resolves and caches installations
+// of a GitHub App by owner or repository, for apps installed across many
+// organizations.
+//
+// [WithDryRun] makes a client record its writes instead of sending them, while
+// reads still go through, to roll out a bot by reviewing what it would do.
+// [GitHubClient.DryRunIntents] returns the recorded writes.
+//
+// [WithAuditor] makes a client emit a [AuditEventType] CloudEvent for each of
+// its writes, naming the bot, the event it was handling, and the pull request
+// or repository changed. An [Auditor] sends the events in batches in the
+// background, to be recorded to BigQuery by cloudevent-recorder.
+//
+// [GitHubClient.SubmitReview] submits a pull request review with many inline
+// comments at once. It maps each comment onto the diff of the pull request's
+// head commit, parsed with [GitHubClient.GetPullRequestDiff], moves comments
+// on lines outside the diff into the review body, and reviews that commit.
+//
+// [Paginate] turns any go-github List method into an iterator over all of its
+// pages, fetched only as the consumer proceeds. Methods such as
+// [GitHubClient.AllIssueComments] and [GitHubClient.AllPullRequests] wrap the
+// common list endpoints.
+//
+// [GitHubClient.WalkTree] iterates over a repository's files with the Git
+// Trees API, optionally filtered with [WithTreeGlob], and
+// [GitHubClient.OpenBlob] streams their content, so bots can scan a
+// repository without cloning it.
+//
+// [GitHubClient.DispatchWorkflow] triggers a workflow and finds the run it
+// started through a correlation token passed as an input.
+// [GitHubClient.WaitForWorkflowRun] waits for a run, or one re-run with
+// [GitHubClient.RerunFailedJobs], to complete, after which its logs and
+// artifacts can be fetched.
+//
+// [GitHubClient.EnableAutoMerge] and [GitHubClient.DisableAutoMerge] manage
+// auto-merge through GraphQL. [GitHubClient.Approve] and
+// [GitHubClient.DismissApprovals] manage the bot's approvals,
+// [GitHubClient.UpdateBranch] updates a pull request that is behind its base,
+// and [GitHubClient.GetMergeability] waits for GitHub to compute whether a pull
+// request can be merged. GitHub's refusals because of branch protection are
+// returned as a [BranchProtectionError].
+//
+// # Configuration
+//
+// [NewConfigLoader] loads a typed per-repository configuration file, falling
+// back to the organization's .github repository. Files are cached by blob
+// SHA and invalidated by push events to the default branch.
+//
+// # Testing
+//
+// The githubtest package provides an in-memory fake of the GitHub API. Pass
+// its client to [WithClient] to test bots without network access.
 package sdk
@@ -0,0 +1,318 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/chainguard-dev/clog"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	gitHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/google/go-github/v88/github"
+)
+
+// DryRunIntent is a write that a client created with WithDryRun recorded
+// instead of sending.
+type DryRunIntent struct {
+	// Method is the HTTP method of an API request, or "PUSH" for a git push.
+	Method string
+	// URL is the URL of an API request, or the remote URL of a push.
+	URL string
+	// Body is the body of an API request, or the reference updates of a
+	// push, one per line.
+	Body string
+}
+
+func (i DryRunIntent) String() string {
+	return i.Method + " " + i.URL
+}
+
+// dryRunRecorder collects the intents of a dry-run client.
+type dryRunRecorder struct {
+	mu      sync.Mutex
+	intents []DryRunIntent
+}
+
+func (r *dryRunRecorder) record(ctx context.Context, intent DryRunIntent) {
+	clog.FromContext(ctx).With("body", intent.Body).Infof("dry run: skipping %s", intent)
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.intents = append(r.intents, intent)
+}
+
+// WithDryRun makes the client record its writes instead of sending them, to
+// see what a bot would do before letting it act. Reads are still sent, so
+// handlers behave as they would for real.
+//
+// Every API request other than a GET, HEAD or OPTIONS, or a GraphQL mutation,
+// is recorded, whether made by GitHubClient's methods or through Client, and
+// answered with an empty successful response. Pushes to repositories cloned
+// with CloneRepo, authenticated with GitAuth, are recorded too. Retrieve the
+// recorded writes with DryRunIntents.
+//
+// As recorded writes don't happen, neither do their effects: DispatchWorkflow
+// returns a nil run, which WaitForWorkflowRun returns at once, and
+// RerunFailedJobs returns the run it was given.
+func WithDryRun() GitHubClientOption {
+	return func(c *GitHubClient) {
+		c.dryRun = &dryRunRecorder{}
+	}
+}
+
+// DryRunIntents returns the writes recorded by a client created with
+// WithDryRun, in the order they were made.
+func (c GitHubClient) DryRunIntents() []DryRunIntent {
+	if c.dryRun == nil {
+		return nil
+	}
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+	return append([]DryRunIntent(nil), c.dryRun.intents...)
+}
+
+// wrapTransport installs the transports of WithAuditor and WithDryRun around
+// the client's transport. Dry run is outermost, so writes it records aren't
+// audited.
+func (c *GitHubClient) wrapTransport() {
+	if c.auditor == nil && c.dryRun == nil {
+		return
+	}
+	hc := c.inner.Client()
+	rt := hc.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if c.auditor != nil {
+		rt = &auditTransport{base: rt, auditor: c.auditor}
+	}
+	if c.dryRun != nil {
+		rt = &dryRunTransport{base: rt, rec: c.dryRun}
+	}
+	hc.Transport = rt
+	client, err := github.NewClient(
+		github.WithHTTPClient(hc),
+		github.WithURLs(github.Ptr(c.inner.BaseURL()), github.Ptr(c.inner.UploadURL())),
+		github.WithUserAgent(c.inner.UserAgent()),
+	)
+	if err != nil {
+		// The URLs come from a working client, so they parse.
+		panic(fmt.Sprintf("sdk: wrapping client transport: %v", err))
+	}
+	c.inner = client
+}
+
+// dryRunTransport sends reads to base and records writes.
+type dryRunTransport struct {
+	base http.RoundTripper
+	rec  *dryRunRecorder
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.base.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	graphql := strings.HasSuffix(req.URL.Path, "/graphql")
+	if graphql && !isMutation(body) {
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		return t.base.RoundTrip(req)
+	}
+
+	t.rec.record(req.Context(), DryRunIntent{Method: req.Method, URL: req.URL.String(), Body: string(body)})
+	status, respBody := dryRunStatus(req.Method, req.URL.Path), ""
+	if graphql {
+		respBody = `{"data":{}}`
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// isMutation reports whether body is a GraphQL mutation rather than a query.
+func isMutation(body []byte) bool {
+	var q struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &q); err != nil {
+		// Err on the side of not sending what we can't read.
+		return true
+	}
+	return strings.HasPrefix(strings.TrimSpace(q.Query), "mutation")
+}
+
+// dryRunStatus returns the status GitHub answers a successful write with.
+// Callers check it, and most writes create something, but adding labels to
+// an issue, removing one and submitting a review answer 200, and dispatching
+// a workflow answers 204.
+func dryRunStatus(method, path string) int {
+	issueLabels := strings.Contains(path, "/issues/") && strings.Contains(path, "/labels")
+	switch {
+	case method == http.MethodPost && strings.HasSuffix(path, "/dispatches"):
+		return http.StatusNoContent
+	case method == http.MethodPost && (issueLabels || strings.HasSuffix(path, "/reviews") || strings.HasSuffix(path, "/graphql")):
+		return http.StatusOK
+	case method == http.MethodPost:
+		return http.StatusCreated
+	case method == http.MethodDelete && issueLabels:
+		return http.StatusOK
+	case method == http.MethodDelete:
+		return http.StatusNoContent
+	default:
+		return http.StatusOK
+	}
+}
+
+// dryRunScheme prefixes the scheme of the remotes of repositories cloned by a
+// dry-run client, so that pushes to them go through dryRunGitTransport.
+const dryRunScheme = "dryrun+"
+
+func init() {
+	for _, scheme := range []string{"https", "http", "file"} {
+		client.InstallProtocol(dryRunScheme+scheme, dryRunGitTransport{})
+	}
+}
+
+// dryRunAuth is the GitAuth of a dry-run client, which carries its recorder
+// to dryRunGitTransport.
+type dryRunAuth struct {
+	gitHttp.AuthMethod
+	rec *dryRunRecorder
+}
+
+// setDryRunRemotes points the remotes of r at dryRunGitTransport.
+func setDryRunRemotes(r *git.Repository) error {
+	cfg, err := r.Config()
+	if err != nil {
+		return err
+	}
+	for _, remote := range cfg.Remotes {
+		for i, u := range remote.URLs {
+			if !strings.HasPrefix(u, dryRunScheme) {
+				remote.URLs[i] = dryRunScheme + u
+			}
+		}
+	}
+	return r.SetConfig(cfg)
+}
+
+// dryRunGitTransport fetches from the remote and records pushes.
+type dryRunGitTransport struct{}
+
+// remote returns the transport and endpoint of the remote ep stands for.
+func (dryRunGitTransport) remote(ep *transport.Endpoint) (transport.Transport, *transport.Endpoint, error) {
+	remote := *ep
+	remote.Protocol = strings.TrimPrefix(ep.Protocol, dryRunScheme)
+	t, err := client.NewClient(&remote)
+	return t, &remote, err
+}
+
+func (dryRunGitTransport) unwrap(auth transport.AuthMethod) (transport.AuthMethod, *dryRunRecorder) {
+	if a, ok := auth.(*dryRunAuth); ok {
+		return a.AuthMethod, a.rec
+	}
+	return auth, nil
+}
+
+func (d dryRunGitTransport) NewUploadPackSession(ep *transport.Endpoint, auth transport.AuthMethod) (transport.UploadPackSession, error) {
+	t, remote, err := d.remote(ep)
+	if err != nil {
+		return nil, err
+	}
+	auth, _ = d.unwrap(auth)
+	return t.NewUploadPackSession(remote, auth)
+}
+
+func (d dryRunGitTransport) NewReceivePackSession(ep *transport.Endpoint, auth transport.AuthMethod) (transport.ReceivePackSession, error) {
+	t, remote, err := d.remote(ep)
+	if err != nil {
+		return nil, err
+	}
+	auth, rec := d.unwrap(auth)
+	// Advertise the remote's references with an upload-pack session, which
+	// needs no write access.
+	s, err := t.NewUploadPackSession(remote, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &dryRunReceivePack{UploadPackSession: s, url: remote.String(), rec: rec}, nil
+}
+
+type dryRunReceivePack struct {
+	transport.UploadPackSession
+	url string
+	rec *dryRunRecorder
+}
+
+func (s *dryRunReceivePack) AdvertisedReferences() (*packp.AdvRefs, error) {
+	return s.AdvertisedReferencesContext(context.Background())
+}
+
+func (s *dryRunReceivePack) AdvertisedReferencesContext(ctx context.Context) (*packp.AdvRefs, error) {
+	ar, err := s.UploadPackSession.AdvertisedReferencesContext(ctx)
+	switch {
+	case errors.Is(err, transport.ErrEmptyRemoteRepository):
+		// Unlike receive-pack, upload-pack refuses empty repositories.
+		ar = packp.NewAdvRefs()
+	case err != nil:
+		return nil, err
+	}
+	// Let pushes delete references, as GitHub does.
+	if err := ar.Capabilities.Set(capability.DeleteRefs); err != nil {
+		return nil, err
+	}
+	return ar, nil
+}
+
+func (s *dryRunReceivePack) ReceivePack(ctx context.Context, req *packp.ReferenceUpdateRequest) (*packp.ReportStatus, error) {
+	if req.Packfile != nil {
+		// Consume the pack, which go-git encodes as we read it.
+		if _, err := io.Copy(io.Discard, req.Packfile); err != nil {
+			return nil, err
+		}
+		req.Packfile.Close()
+	}
+	var updates strings.Builder
+	for _, cmd := range req.Commands {
+		fmt.Fprintf(&updates, "%s %s %s..%s\n", cmd.Action(), cmd.Name, cmd.Old, cmd.New)
+	}
+	s.rec.record(ctx, DryRunIntent{Method: "PUSH", URL: s.url, Body: updates.String()})
+	return nil, nil
+}
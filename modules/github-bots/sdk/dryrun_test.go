@@ -0,0 +1,206 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	git "github.com/go-git/go-git/v5"
+	gitConfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	gitHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/google/go-github/v88/github"
+)
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.SetFile("acme", "widgets", "main", "README.md", "# widgets")
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{
+		Labels: []*github.Label{{Name: github.Ptr("stale")}},
+	})
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithDryRun(), WithClient(srv.Client()))
+
+	if err := gh.AddLabel(ctx, pr, "lgtm"); err != nil {
+		t.Errorf("AddLabel: %v", err)
+	}
+	if err := gh.RemoveLabel(ctx, pr, "stale"); err != nil {
+		t.Errorf("RemoveLabel: %v", err)
+	}
+	if err := gh.SetComment(ctx, pr, "test", "hello"); err != nil {
+		t.Errorf("SetComment: %v", err)
+	}
+	if _, _, err := gh.Client().Checks.CreateCheckRun(ctx, "acme", "widgets", github.CreateCheckRunOptions{Name: "test", HeadSHA: "abc"}); err != nil {
+		t.Errorf("CreateCheckRun: %v", err)
+	}
+	// Reads go through.
+	if got, err := gh.GetFileContent(ctx, "acme", "widgets", "README.md", "main"); err != nil || got != "# widgets" {
+		t.Errorf("GetFileContent = %q, %v", got, err)
+	}
+
+	srv.AssertLabels("acme", "widgets", pr.GetNumber(), "stale")
+	if got := srv.Comments("acme", "widgets", pr.GetNumber()); len(got) != 0 {
+		t.Errorf("got %d comments, want none", len(got))
+	}
+	if got := srv.CheckRuns("acme", "widgets"); len(got) != 0 {
+		t.Errorf("got %d check runs, want none", len(got))
+	}
+	for _, r := range srv.Requests() {
+		if r.Method != http.MethodGet {
+			t.Errorf("sent %s %s", r.Method, r.Path)
+		}
+	}
+
+	var got []string
+	for _, i := range gh.DryRunIntents() {
+		got = append(got, i.Method+" /"+strings.TrimPrefix(i.URL, gh.Client().BaseURL()))
+	}
+	want := []string{
+		"POST /repos/acme/widgets/issues/1/labels",
+		"DELETE /repos/acme/widgets/issues/1/labels/stale",
+		"POST /repos/acme/widgets/issues/1/comments",
+		"POST /repos/acme/widgets/check-runs",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("DryRunIntents:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if body := gh.DryRunIntents()[2].Body; !strings.Contains(body, "hello") {
+		t.Errorf("comment intent body = %q, want it to contain the comment", body)
+	}
+}
+
+func TestDryRun_Workflows(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	completed := srv.AddWorkflowRun("acme", "widgets", &github.WorkflowRun{
+		Status:     github.Ptr("completed"),
+		Conclusion: github.Ptr("failure"),
+		RunAttempt: github.Ptr(1),
+	})
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithDryRun(), WithClient(srv.Client()))
+
+	// No run starts, so there's none to look for; the default lookup would
+	// time out after minutes.
+	run, err := gh.DispatchWorkflow(ctx, "acme", "widgets", "ci.yaml", "main", nil)
+	if err != nil || run != nil {
+		t.Fatalf("DispatchWorkflow = %v, %v, want a nil run", run, err)
+	}
+	if done, err := gh.WaitForWorkflowRun(ctx, run); err != nil || done != nil {
+		t.Errorf("WaitForWorkflowRun = %v, %v, want a nil run", done, err)
+	}
+
+	// No new attempt starts either, so waiting for the rerun returns the
+	// completed attempt.
+	rerun, err := gh.RerunFailedJobs(ctx, completed)
+	if err != nil {
+		t.Fatalf("RerunFailedJobs: %v", err)
+	}
+	done, err := gh.WaitForWorkflowRun(ctx, rerun, WithPollInterval(time.Millisecond, time.Millisecond))
+	if err != nil || done.GetRunAttempt() != 1 {
+		t.Errorf("WaitForWorkflowRun = %v, %v, want the completed attempt", done, err)
+	}
+
+	var got []string
+	for _, i := range gh.DryRunIntents() {
+		got = append(got, i.Method+" /"+strings.TrimPrefix(i.URL, gh.Client().BaseURL()))
+	}
+	want := []string{
+		"POST /repos/acme/widgets/actions/workflows/ci.yaml/dispatches",
+		fmt.Sprintf("POST /repos/acme/widgets/actions/runs/%d/rerun-failed-jobs", completed.GetID()),
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("DryRunIntents:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDryRun_GraphQL(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithDryRun(), WithClient(srv.Client()))
+
+	for _, query := range []string{"query { viewer { login } }", "mutation { enablePullRequestAutoMerge }"} {
+		req, err := gh.Client().NewRequest(ctx, http.MethodPost, "graphql", map[string]string{"query": query})
+		if err != nil {
+			t.Fatalf("NewRequest: %v", err)
+		}
+		// Only whether the request reached the server matters, not how it
+		// was answered.
+		if resp, _ := gh.Client().BareDo(req); resp != nil {
+			resp.Body.Close()
+		}
+	}
+
+	srv.AssertRequestCount(http.MethodPost, "/graphql", 1)
+	if got := gh.DryRunIntents(); len(got) != 1 || !strings.Contains(got[0].Body, "mutation") {
+		t.Errorf("DryRunIntents = %v, want the mutation", got)
+	}
+}
+
+func TestDryRun_Push(t *testing.T) {
+	ctx := context.Background()
+	remoteDir, workDir := t.TempDir(), t.TempDir()
+	remote, err := git.PlainInit(remoteDir, true)
+	if err != nil {
+		t.Fatalf("PlainInit: %v", err)
+	}
+	work, err := git.PlainInit(workDir, false)
+	if err != nil {
+		t.Fatalf("PlainInit: %v", err)
+	}
+	if _, err := work.CreateRemote(&gitConfig.RemoteConfig{Name: "origin", URLs: []string{"file://" + remoteDir}}); err != nil {
+		t.Fatalf("CreateRemote: %v", err)
+	}
+	commit := func() plumbing.Hash {
+		wt, err := work.Worktree()
+		if err != nil {
+			t.Fatalf("Worktree: %v", err)
+		}
+		h, err := wt.Commit("commit", &git.CommitOptions{
+			AllowEmptyCommits: true,
+			Author:            &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		return h
+	}
+	refSpec := gitConfig.RefSpec("refs/heads/master:refs/heads/master")
+
+	first := commit()
+	if err := work.PushContext(ctx, &git.PushOptions{RefSpecs: []gitConfig.RefSpec{refSpec}}); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	rec := &dryRunRecorder{}
+	if err := setDryRunRemotes(work); err != nil {
+		t.Fatalf("setDryRunRemotes: %v", err)
+	}
+	second := commit()
+	if err := work.PushContext(ctx, &git.PushOptions{
+		RefSpecs: []gitConfig.RefSpec{refSpec},
+		Auth:     &dryRunAuth{AuthMethod: &gitHttp.BasicAuth{}, rec: rec},
+	}); err != nil {
+		t.Fatalf("dry-run Push: %v", err)
+	}
+
+	ref, err := remote.Reference("refs/heads/master", false)
+	if err != nil {
+		t.Fatalf("Reference: %v", err)
+	}
+	if ref.Hash() != first {
+		t.Errorf("remote master = %s, want it left at %s", ref.Hash(), first)
+	}
+	if len(rec.intents) != 1 || rec.intents[0].Method != "PUSH" || !strings.Contains(rec.intents[0].Body, "update refs/heads/master "+first.String()+".."+second.String()) {
+		t.Errorf("intents = %+v, want the update of master", rec.intents)
+	}
+}
@@ -7,9 +7,12 @@ package sdk_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk"
+	mce "github.com/chainguard-dev/terraform-infra-common/pkg/httpmetrics/cloudevents"
 	"github.com/google/go-github/v88/github"
 )
 
@@ -37,9 +40,125 @@ func ExampleBot_RegisterHandler() {
 	// Output: 1
 }
 
+func ExampleBotWithFailureReporting() {
+	var apps *sdk.InstallationClientFactory // from sdk.NewInstallationClientFactory
+	bot := sdk.NewBot("my-bot",
+		sdk.BotWithHandler(
+			sdk.PullRequestHandler(func(ctx context.Context, pre github.PullRequestEvent) error {
+				if pre.GetPullRequest().GetBody() == "" {
+					// Retrying won't fill in the description, so tell the
+					// author rather than the logs.
+					return sdk.Permanent(errors.New("pull request has no description"))
+				}
+				return nil
+			}),
+		),
+		sdk.BotWithFailureReporting(func(ctx context.Context, owner, repo string) (sdk.GitHubClient, error) {
+			return apps.ForRepo(ctx, owner, repo)
+		}),
+	)
+	sdk.Serve(bot)
+}
+
 func ExampleAttributeFromContext() {
 	ctx := context.Background()
 	val := sdk.AttributeFromContext(ctx, "missing-key")
 	fmt.Println(val)
 	// Output: <nil>
 }
+
+func ExampleNewConfigLoader() {
+	type config struct {
+		AutoLabel bool `yaml:"autoLabel"`
+	}
+	loader := sdk.NewConfigLoader[config]("my-bot")
+
+	bot := sdk.NewBot("my-bot",
+		// Drop cached configuration when it changes on the default branch.
+		sdk.BotWithHandler(loader.PushHandler()),
+	)
+	fmt.Println(loader.Path(), len(bot.Handlers))
+	// Output: .github/chainguard/my-bot.yaml 1
+}
+
+func ExampleParsePatch() {
+	hunks, err := sdk.ParsePatch("@@ -1,2 +1,2 @@\n package main\n-var x = 1\n+var x = 2\n")
+	if err != nil {
+		panic(err)
+	}
+	diff := sdk.FileDiff{Path: "main.go", Hunks: hunks}
+	fmt.Println(diff.Commentable(2, sdk.DiffSideRight))
+	fmt.Println(diff.Commentable(3, sdk.DiffSideRight))
+	// Output:
+	// true
+	// false
+}
+
+func ExampleGitHubClient_DispatchWorkflow() {
+	ctx := context.Background()
+	gh := sdk.NewGitHubClient(ctx, "my-org", "my-repo", "my-bot")
+
+	run, err := gh.DispatchWorkflow(ctx, "my-org", "my-repo", "build.yaml", "main", map[string]any{"arch": "arm64"})
+	if err != nil {
+		panic(err)
+	}
+	run, err = gh.WaitForWorkflowRun(ctx, run)
+	if err != nil {
+		panic(err)
+	}
+	if run.GetConclusion() != "success" {
+		if run, err = gh.RerunFailedJobs(ctx, run); err != nil {
+			panic(err)
+		}
+		if run, err = gh.WaitForWorkflowRun(ctx, run); err != nil {
+			panic(err)
+		}
+	}
+	results, err := gh.FetchWorkflowRunArtifact(ctx, run, "results")
+	if err != nil {
+		panic(err)
+	}
+	for _, f := range results.File {
+		fmt.Println(f.Name)
+	}
+}
+
+func ExampleGitHubClient_EnableAutoMerge() {
+	ctx := context.Background()
+	gh := sdk.NewGitHubClient(ctx, "my-org", "my-repo", "my-bot")
+	var pr *github.PullRequest // from a pull_request event
+
+	m, err := gh.GetMergeability(ctx, pr)
+	if err != nil {
+		panic(err)
+	}
+	if m.State == sdk.MergeableStateBehind {
+		if err := gh.UpdateBranch(ctx, pr); err != nil {
+			panic(err)
+		}
+	}
+	if _, err := gh.Approve(ctx, pr, "my-bot", "Dependency update"); err != nil {
+		panic(err)
+	}
+	if err := gh.EnableAutoMerge(ctx, pr, sdk.MergeMethodSquash); err != nil {
+		if _, ok := errors.AsType[*sdk.BranchProtectionError](err); !ok {
+			panic(err)
+		}
+		// The pull request can't use auto-merge, for example because it
+		// can be merged right away.
+	}
+}
+
+func ExampleNewAuditor() {
+	ctx := context.Background()
+	// The URI of the cloudevent-broker ingress, passed in the bot's env.
+	ceclient, err := mce.NewClientHTTP("my-bot", mce.WithTarget(ctx, os.Getenv("EVENT_INGRESS_URI"))...)
+	if err != nil {
+		panic(err)
+	}
+	auditor := sdk.NewAuditor("my-bot", ceclient)
+	defer auditor.Close(ctx)
+
+	gh := sdk.NewGitHubClient(ctx, "my-org", "my-repo", "my-bot", sdk.WithAuditor(auditor))
+	_ = gh // every write made with gh emits an audit event
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/check"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-github/v88/github"
+)
+
+// maxFailureMessage is the length to which reported error messages are
+// truncated.
+const maxFailureMessage = 2000
+
+// PermanentError is returned by handlers, with Permanent, for failures that
+// handling the event again won't fix, such as invalid configuration in the
+// repository.
+type PermanentError struct {
+	Err error
+}
+
+// Permanent marks err as permanent, so that it is reported on the pull
+// request when the bot uses BotWithFailureReporting. It returns nil if err is
+// nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// failureReporter reports the failures of a bot's handlers on the pull
+// requests their events relate to.
+type failureReporter struct {
+	name     string
+	client   ClientFunc
+	comments bool
+}
+
+// FailureReportingOption configures BotWithFailureReporting.
+type FailureReportingOption func(*failureReporter)
+
+// WithFailureComments reports failures in a sticky comment, which is deleted
+// once the bot succeeds, rather than in a check run. Only GitHub Apps can
+// create check runs, so bots authenticating with other tokens, such as those
+// from octo-sts, need it.
+func WithFailureComments() FailureReportingOption {
+	return func(r *failureReporter) {
+		r.comments = true
+	}
+}
+
+// BotWithFailureReporting makes the failures of the bot's handlers visible to
+// pull request authors, rather than only in the bot's logs. When a handler of
+// an event related to a pull request panics or returns a PermanentError, a
+// failing check run named "<bot>/failure" is created on the pull request's
+// head, carrying the delivery ID and the error message with credentials
+// redacted. The next time a handler succeeds for that pull request, the check
+// run is marked successful.
+//
+// Events relate to pull requests through the pullrequesturl extension set by
+// github-events. client returns the client used to report failures on the
+// pull request's repository. Each handled event then costs a few more API
+// calls, to find failures to clear.
+//
+// Events whose handler failed permanently are acknowledged once the failure
+// is reported, and the error is logged, as retrying them can't succeed. Other
+// errors are likely transient, so are retried by the broker as usual without
+// being reported.
+func BotWithFailureReporting(client ClientFunc, opts ...FailureReportingOption) BotOptions {
+	return func(b *Bot) {
+		r := &failureReporter{name: b.Name + "/failure", client: client}
+		for _, opt := range opts {
+			opt(r)
+		}
+		b.failures = r
+	}
+}
+
+// report reports failure, the permanent failure of the handler of event, or
+// clears earlier failures if it is nil.
+func (r *failureReporter) report(ctx context.Context, event cloudevents.Event, failure error) {
+	log := clog.FromContext(ctx)
+
+	owner, repo, number, ok := parsePullRequestURL(fmt.Sprint(event.Extensions()["pullrequesturl"]))
+	if !ok {
+		return
+	}
+	gh, err := r.client(ctx, owner, repo)
+	if err != nil {
+		log.Errorf("failed to get client to report failures on %s/%s#%d: %v", owner, repo, number, err)
+		return
+	}
+	pr, resp, err := gh.Client().PullRequests.Get(ctx, owner, repo, number)
+	if err := validateResponse(ctx, err, resp, "get pull request"); err != nil {
+		log.Errorf("failed to report failures on %s/%s#%d: %v", owner, repo, number, err)
+		return
+	}
+
+	if r.comments {
+		err = r.comment(ctx, gh, pr, event.ID(), failure)
+	} else {
+		err = r.checkRun(ctx, gh, pr, event.ID(), failure)
+	}
+	if err != nil {
+		log.Errorf("failed to report failures on %s/%s#%d: %v", owner, repo, number, err)
+	}
+}
+
+func (r *failureReporter) checkRun(ctx context.Context, gh GitHubClient, pr *github.PullRequest, deliveryID string, failure error) error {
+	owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+	sha := pr.GetHead().GetSHA()
+
+	runs, resp, err := gh.Client().Checks.ListCheckRunsForRef(ctx, owner, repo, sha, &github.ListCheckRunsOptions{
+		CheckName: github.Ptr(r.name),
+	})
+	if err := validateResponse(ctx, err, resp, "list check runs"); err != nil {
+		return err
+	}
+	var existing *github.CheckRun
+	if len(runs.CheckRuns) > 0 {
+		existing = runs.CheckRuns[0]
+	}
+
+	b := check.NewBuilder(r.name, sha)
+	if failure == nil {
+		if existing == nil || existing.GetConclusion() != string(check.ConclusionFailure) {
+			return nil
+		}
+		b.Conclusion = check.ConclusionSuccess
+		b.Summary = fmt.Sprintf("Handled delivery %s", deliveryID)
+	} else {
+		b.Conclusion = check.ConclusionFailure
+		b.Summary = fmt.Sprintf("Failed to handle delivery %s", deliveryID)
+		b.Writef("```\n%s\n```", sanitizeError(failure))
+	}
+
+	if existing != nil {
+		_, resp, err := gh.Client().Checks.UpdateCheckRun(ctx, owner, repo, existing.GetID(), *b.CheckRunUpdate())
+		return validateResponse(ctx, err, resp, "update check run")
+	}
+	if _, resp, err := gh.Client().Checks.CreateCheckRun(ctx, owner, repo, *b.CheckRunCreate()); err != nil || resp.StatusCode != http.StatusCreated {
+		return validateResponse(ctx, err, resp, "create check run")
+	}
+	return nil
+}
+
+func (r *failureReporter) comment(ctx context.Context, gh GitHubClient, pr *github.PullRequest, deliveryID string, failure error) error {
+	if failure != nil {
+		return gh.SetComment(ctx, pr, r.name, fmt.Sprintf("Failed to handle delivery %s:\n\n```\n%s\n```", deliveryID, sanitizeError(failure)))
+	}
+
+	owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+	marker := fmt.Sprintf("<!-- bot:%s -->", r.name)
+	for com, err := range gh.AllIssueComments(ctx, owner, repo, pr.GetNumber()) {
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(com.GetBody(), marker) {
+			continue
+		}
+		if resp, err := gh.Client().Issues.DeleteComment(ctx, owner, repo, com.GetID()); err != nil || resp.StatusCode != http.StatusNoContent {
+			return validateResponse(ctx, err, resp, "delete comment")
+		}
+	}
+	return nil
+}
+
+// parsePullRequestURL parses a pull request URL such as
+// https://github.com/owner/repo/pull/1.
+func parsePullRequestURL(s string) (owner, repo string, number int, ok bool) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", "", 0, false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 4 || parts[2] != "pull" {
+		return "", "", 0, false
+	}
+	number, err = strconv.Atoi(parts[3])
+	if err != nil {
+		return "", "", 0, false
+	}
+	return parts[0], parts[1], number, true
+}
+
+var (
+	// secretPatterns match credentials that errors may carry: GitHub tokens
+	// and JWTs.
+	secretPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`),
+		regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	}
+	// authCredentials matches the credentials of Authorization headers.
+	authCredentials = regexp.MustCompile(`(?i)\b(bearer|token|basic)(\s+)[A-Za-z0-9._~+/=-]{20,}`)
+	// urlUserinfo matches the credentials in URLs.
+	urlUserinfo = regexp.MustCompile(`(://)[^/\s@]+@`)
+)
+
+// sanitizeError returns the message of err with credentials redacted,
+// truncated, and safe to put in a Markdown code block.
+func sanitizeError(err error) string {
+	msg := err.Error()
+	msg = urlUserinfo.ReplaceAllString(msg, "${1}REDACTED@")
+	msg = authCredentials.ReplaceAllString(msg, "${1}${2}REDACTED")
+	for _, p := range secretPatterns {
+		msg = p.ReplaceAllString(msg, "REDACTED")
+	}
+	msg = strings.ReplaceAll(msg, "```", "'''")
+	if r := []rune(msg); len(r) > maxFailureMessage {
+		msg = string(r[:maxFailureMessage]) + "…"
+	}
+	return msg
+}
@@ -16,6 +16,7 @@ import (
 	"os"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	bufra "github.com/avvmoto/buf-readerat"
@@ -27,29 +28,31 @@ import (
 	gitHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/snabb/httpreaderat"
 
-	"chainguard.dev/sdk/octosts"
 	"github.com/chainguard-dev/clog"
 	"github.com/chainguard-dev/terraform-infra-common/pkg/gitexec/gogit"
 	"github.com/google/go-github/v88/github"
 	"golang.org/x/oauth2"
 )
 
-// NewGitHubClient creates a new GitHub client, using a new token from OctoSTS,
-// for the given org, repo and policy name.
+// NewGitHubClient creates a new GitHub client, using tokens from OctoSTS, for
+// the given org, repo and policy name.
 //
-// A new token is created for each client, and is not refreshed. It can be
-// revoked with Close.
+// A token is minted on first use and refreshed before it expires, revoking
+// the previous one. The current token can be revoked with Close. Use a
+// ClientPool to share clients across events.
 func NewGitHubClient(ctx context.Context, org, repo, policyName string, opts ...GitHubClientOption) GitHubClient {
-	ts := oauth2.ReuseTokenSource(nil, &tokenSource{
+	octo := &tokenSource{
 		org:        org,
 		repo:       repo,
 		policyName: policyName,
-	})
+	}
+	ts := oauth2.ReuseTokenSource(nil, octo)
 
 	httpClient := oauth2.NewClient(ctx, ts)
 	client := GitHubClient{
 		inner:   NewClient(httpClient.Transport),
 		ts:      ts,
+		octo:    octo,
 		bufSize: 1024 * 1024, // 1MB buffer for requests
 		org:     org,
 		repo:    repo,
@@ -58,6 +61,7 @@ func NewGitHubClient(ctx context.Context, org, repo, policyName string, opts ...
 	for _, opt := range opts {
 		opt(&client)
 	}
+	client.wrapTransport()
 
 	return client
 }
@@ -98,12 +102,21 @@ func NewInstallationClient(ctx context.Context, org, repo string, tr *ghinstalla
 	for _, opt := range opts {
 		opt(&client)
 	}
+	client.wrapTransport()
 
 	return client
 }
 
 type tokenSource struct {
-	org, repo, policyName, tok string
+	org, repo, policyName string
+
+	// pooled sources are shared by clients of several events, any of which
+	// may still be using the previous token when another refreshes it, so
+	// they let replaced tokens expire instead of revoking them.
+	pooled bool
+
+	mu  sync.Mutex
+	tok string
 }
 
 func (ts *tokenSource) Token() (*oauth2.Token, error) {
@@ -111,23 +124,27 @@ func (ts *tokenSource) Token() (*oauth2.Token, error) {
 	defer cancel()
 
 	clog.DebugContextf(ctx, "getting octosts token for %s/%s - %s", ts.org, ts.repo, ts.policyName)
-	tok, err := OctoTokenFunc(ctx, ts.policyName, ts.org, ts.repo)
+	tok, err := mintToken(ctx, ts.policyName, ts.org, ts.repo)
 	if err != nil {
 		return nil, err
 	}
 
+	ts.mu.Lock()
+	prev := ts.tok
+	ts.tok = tok
+	ts.mu.Unlock()
+
 	// If there's a previous token, attempt to revoke it.
-	if ts.tok != "" {
+	if prev != "" && !ts.pooled {
 		ctx, cancel := context.WithTimeoutCause(context.Background(), 1*time.Minute, errors.New("revoke previous token timeout"))
 		defer cancel()
 
-		if err := octosts.Revoke(ctx, ts.tok); err != nil {
+		if err := revokeToken(ctx, prev); err != nil {
 			// This isn't an error, but we should log it.
 			clog.WarnContextf(ctx, "failed to revoke token: %v", err)
 		}
 	}
 
-	ts.tok = tok
 	return &oauth2.Token{
 		AccessToken: tok,
 		// We don't actually know when it will expire, but it's probably in 1
@@ -139,6 +156,11 @@ func (ts *tokenSource) Token() (*oauth2.Token, error) {
 // GitHubClientOption configures the client, these are ran after the default setup.
 type GitHubClientOption func(*GitHubClient)
 
+// ClientFunc returns a client for owner/repo, for example from a ClientPool or
+// an InstallationClientFactory. NewResyncer and BotWithFailureReporting take
+// one to get clients for the repositories they act on.
+type ClientFunc func(ctx context.Context, owner, repo string) (GitHubClient, error)
+
 // WithSecondaryRateLimitWaiter is intended to change the underlying transport to respect GitHub's rate-limiting requests.
 // As of today, it is a no-op.
 // Using this option will not change the behavior of `GitHubClient`.
@@ -169,19 +191,35 @@ func WithClient(client *github.Client) GitHubClientOption {
 type GitHubClient struct {
 	inner     *github.Client
 	ts        oauth2.TokenSource
+	octo      *tokenSource
+	limiter   *ConcurrencyLimiter
 	org, repo string
 	bufSize   int
+	dryRun    *dryRunRecorder
+	auditor   *Auditor
 }
 
 func (c GitHubClient) Client() *github.Client { return c.inner }
 
 func (c GitHubClient) Close(ctx context.Context) error {
-	// TODO: We shouldn't get a token here if it's the first time, just to revoke it.
-	tok, err := c.ts.Token()
-	if err != nil {
-		// Callers might just `defer c.Close()` so we log the error here too
-		clog.WarnContextf(ctx, "failed to get token for revocation: %v", err)
-		return fmt.Errorf("getting token for revocation: %w", err)
+	var accessToken string
+	if c.octo != nil {
+		// Only revoke a token we actually minted, rather than minting one
+		// just to revoke it.
+		c.octo.mu.Lock()
+		accessToken, c.octo.tok = c.octo.tok, ""
+		c.octo.mu.Unlock()
+		if accessToken == "" {
+			return nil
+		}
+	} else {
+		tok, err := c.ts.Token()
+		if err != nil {
+			// Callers might just `defer c.Close()` so we log the error here too
+			clog.WarnContextf(ctx, "failed to get token for revocation: %v", err)
+			return fmt.Errorf("getting token for revocation: %w", err)
+		}
+		accessToken = tok.AccessToken
 	}
 
 	// We don't want to cancel the context, as we want to revoke the token even if the context is done.
@@ -190,7 +228,7 @@ func (c GitHubClient) Close(ctx context.Context) error {
 	ctx, cancel = context.WithTimeoutCause(context.WithoutCancel(ctx), 1*time.Minute, errors.New("revoking token timeout"))
 	defer cancel()
 
-	if err := octosts.Revoke(ctx, tok.AccessToken); err != nil {
+	if err := revokeToken(ctx, accessToken); err != nil {
 		// Callers might just `defer c.Close()` so we log the error here too
 		clog.ErrorContextf(ctx, "failed to revoke token: %v", err)
 		return fmt.Errorf("revoking token: %w", err)
@@ -202,21 +240,47 @@ func (c GitHubClient) Close(ctx context.Context) error {
 // GitAuth returns a go-git transport.AuthMethod using the GitHubClient's
 // credentials. This is useful for authentication in go-git operations like
 // cloning and fetching repositories.
+//
+// The method reads the client's token source on every request, so long
+// clones and pushes pick up refreshed tokens.
 func (c GitHubClient) GitAuth() (transport.AuthMethod, error) {
-	tok, err := c.ts.Token()
-	if err != nil {
+	// Fail early if the client can't get a token at all.
+	if _, err := c.ts.Token(); err != nil {
 		return nil, fmt.Errorf("getting token from client's token source: %w", err)
 	}
 
-	auth := &gitHttp.BasicAuth{
-		// https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/authenticating-as-a-github-app-installation#about-authentication-as-a-github-app-installation
-		Username: "x-access-token",
-		Password: tok.AccessToken,
+	auth := &tokenAuth{ts: c.ts}
+	if c.dryRun != nil {
+		return &dryRunAuth{AuthMethod: auth, rec: c.dryRun}, nil
 	}
 
 	return auth, nil
 }
 
+// tokenAuth is a go-git HTTP AuthMethod that authenticates each request with
+// the current token of a token source.
+type tokenAuth struct {
+	ts oauth2.TokenSource
+}
+
+var _ gitHttp.AuthMethod = (*tokenAuth)(nil)
+
+func (a *tokenAuth) Name() string { return "http-basic-auth" }
+
+func (a *tokenAuth) String() string { return "http-basic-auth - x-access-token:*******" }
+
+func (a *tokenAuth) SetAuth(r *http.Request) {
+	tok, err := a.ts.Token()
+	if err != nil {
+		// The request goes out unauthenticated and fails with the server's
+		// error.
+		clog.WarnContextf(r.Context(), "failed to get token for git request: %v", err)
+		return
+	}
+	// https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/authenticating-as-a-github-app-installation#about-authentication-as-a-github-app-installation
+	r.SetBasicAuth("x-access-token", tok.AccessToken)
+}
+
 // RepoURL returns the HTTPS git URL of the GitHubClient's configured
 // repository.
 func (c GitHubClient) RepoURL() (string, error) {
@@ -262,6 +326,12 @@ func (c GitHubClient) AddLabel(ctx context.Context, pr *github.PullRequest, labe
 		return nil
 	}
 
+	ctx, release, err := c.acquire(ctx, *pr.Base.Repo.Owner.Login, *pr.Base.Repo.Name, *pr.Number, WriteOperation)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	log.Infof("Adding label %q to PR %d", label, *pr.Number)
 	_, resp, err := c.inner.Issues.AddLabelsToIssue(ctx, *pr.Base.Repo.Owner.Login, *pr.Base.Repo.Name, *pr.Number, []string{label})
 	if err := validateResponse(ctx, err, resp, "add label to pull request"); err != nil {
@@ -279,6 +349,12 @@ func (c GitHubClient) RemoveLabel(ctx context.Context, pr *github.PullRequest, l
 		return nil
 	}
 
+	ctx, release, err := c.acquire(ctx, *pr.Base.Repo.Owner.Login, *pr.Base.Repo.Name, *pr.Number, WriteOperation)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	log.Infof("Removing label %q from PR %d", label, *pr.Number)
 	resp, err := c.inner.Issues.RemoveLabelForIssue(ctx, *pr.Base.Repo.Owner.Login, *pr.Base.Repo.Name, *pr.Number, label)
 	if err := validateResponse(ctx, err, resp, "remove label from pull request"); err != nil {
@@ -289,6 +365,14 @@ func (c GitHubClient) RemoveLabel(ctx context.Context, pr *github.PullRequest, l
 
 // SetComment adds or replaces a bot comment on the given pull request.
 func (c GitHubClient) SetComment(ctx context.Context, pr *github.PullRequest, botName, content string) error {
+	// Hold the permit across listing and writing, so that concurrent calls
+	// don't both create a comment.
+	ctx, release, err := c.acquire(ctx, *pr.Base.Repo.Owner.Login, *pr.Base.Repo.Name, *pr.Number, WriteOperation)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	cs, _, err := c.inner.Issues.ListComments(ctx, *pr.Base.Repo.Owner.Login, *pr.Base.Repo.Name, *pr.Number, nil)
 	if err != nil {
 		return fmt.Errorf("listing comments: %w", err)
@@ -315,6 +399,12 @@ func (c GitHubClient) SetComment(ctx context.Context, pr *github.PullRequest, bo
 
 // AddComment adds a new comment to the given pull request.
 func (c GitHubClient) AddComment(ctx context.Context, pr *github.PullRequest, botName, content string) error {
+	ctx, release, err := c.acquire(ctx, *pr.Base.Repo.Owner.Login, *pr.Base.Repo.Name, *pr.Number, WriteOperation)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	content = fmt.Sprintf("<!-- bot:%s -->\n\n%s", botName, content)
 	if _, resp, err := c.inner.Issues.CreateComment(ctx, *pr.Base.Repo.Owner.Login, *pr.Base.Repo.Name, *pr.Number, &github.IssueComment{
 		Body: &content,
@@ -525,7 +615,8 @@ func (c GitHubClient) FetchWorkflowRunArtifact(ctx context.Context, wr *github.W
 // ListArtifactsFunc executes a paginated list of all artifacts for a given
 // workflow run and executes the provided function on each of the artifacts.
 // The provided function should return a boolean to indicate whether the list
-// operation can stop making API calls.
+// operation can stop making API calls. AllWorkflowRunArtifacts iterates over
+// the same artifacts.
 func (c GitHubClient) ListArtifactsFunc(ctx context.Context, wr *github.WorkflowRun, opt *github.ListOptions, f func(artifact *github.Artifact) (bool, error)) error {
 	if opt == nil {
 		opt = &github.ListOptions{}
@@ -593,7 +684,9 @@ type CloneOpts struct {
 // destDir is the directory to clone the repository into. It will be created if it doesn't exist.
 // if opts is nil, a full clone will be performed.
 //
-// It returns the git.Repository object for the cloned repository.
+// It returns the git.Repository object for the cloned repository. The remotes
+// of repositories cloned by a client created with WithDryRun record pushes
+// instead of sending them.
 func (c GitHubClient) CloneRepo(ctx context.Context, ref, destDir string, opts *CloneOpts) (*git.Repository, error) {
 	log := clog.FromContext(ctx)
 
@@ -652,11 +745,22 @@ func (c GitHubClient) CloneRepo(ctx context.Context, ref, destDir string, opts *
 		log.With("status", status).Error("failed checkout")
 		return nil, fmt.Errorf("failed to checkout ref %s: %w", ref, err)
 	}
+	if c.dryRun != nil {
+		if err := setDryRunRemotes(r.Repository); err != nil {
+			return nil, fmt.Errorf("failed to configure dry-run remotes: %w", err)
+		}
+	}
 	return r.Repository, nil
 }
 
 // GetRelease fetches the release by tag
 func (c GitHubClient) GetRelease(ctx context.Context, owner, repo, tag string) (*github.RepositoryRelease, error) {
+	ctx, done, err := c.acquire(ctx, owner, repo, 0, ReadOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
 	release, resp, err := c.inner.Repositories.GetReleaseByTag(ctx, owner, repo, tag)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get release by tag %s: %w", tag, err)
@@ -667,8 +771,15 @@ func (c GitHubClient) GetRelease(ctx context.Context, owner, repo, tag string) (
 	return release, nil
 }
 
-// GetFileContent fetches the content of a file at a given ref
+// GetFileContent fetches the content of a file at a given ref. Files over
+// 1 MB are fetched from the blobs API.
 func (c GitHubClient) GetFileContent(ctx context.Context, owner, repo, path, ref string) (string, error) {
+	ctx, release, err := c.acquire(ctx, owner, repo, 0, ReadOperation)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
 	opts := &github.RepositoryContentGetOptions{
 		Ref: ref,
 	}
@@ -684,6 +795,15 @@ func (c GitHubClient) GetFileContent(ctx context.Context, owner, repo, path, ref
 	if err := validateResponse(ctx, err, resp, fmt.Sprintf("get file contents for %s at ref %s", path, ref)); err != nil {
 		return "", err
 	}
+	// The contents API omits the content of files over 1 MB, which the blobs
+	// API serves up to 100 MB.
+	if fileContent.GetEncoding() == "none" {
+		blob, resp, err := c.inner.Git.GetBlobRaw(ctx, owner, repo, fileContent.GetSHA())
+		if err := validateResponse(ctx, err, resp, fmt.Sprintf("get blob %s for %s at ref %s", fileContent.GetSHA(), path, ref)); err != nil {
+			return "", err
+		}
+		return string(blob), nil
+	}
 	content, err := fileContent.GetContent()
 	if err != nil {
 		return "", fmt.Errorf("failed to decode content: %w", err)
@@ -736,6 +856,12 @@ func (c GitHubClient) SearchFilenameInRepository(ctx context.Context, owner, rep
 
 // ListFiles lists the files in a directory at a given ref
 func (c GitHubClient) ListFiles(ctx context.Context, owner, repo, path, ref string) ([]*github.RepositoryContent, error) {
+	ctx, release, err := c.acquire(ctx, owner, repo, 0, ReadOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	opts := &github.RepositoryContentGetOptions{Ref: ref}
 
 	_, dirContents, resp, err := c.inner.Repositories.GetContents(
@@ -750,6 +876,12 @@ func (c GitHubClient) ListFiles(ctx context.Context, owner, repo, path, ref stri
 
 // CompareCommits fetches the differences between two commits
 func (c GitHubClient) CompareCommits(ctx context.Context, owner, repo, base, head string, opts *github.ListOptions) (*github.CommitsComparison, error) {
+	ctx, release, err := c.acquire(ctx, owner, repo, 0, ReadOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	comparison, resp, err := c.inner.Repositories.CompareCommits(
 		ctx, owner, repo, base, head, opts,
 	)
@@ -762,6 +894,12 @@ func (c GitHubClient) CompareCommits(ctx context.Context, owner, repo, base, hea
 
 // GetCommitDetails fetches the details of a single commit
 func (c GitHubClient) GetCommitDetails(ctx context.Context, owner, repo, sha string, opts *github.ListOptions) (*github.RepositoryCommit, error) {
+	ctx, release, err := c.acquire(ctx, owner, repo, 0, ReadOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	cDetails, resp, err := c.inner.Repositories.GetCommit(
 		ctx, owner, repo, sha, opts,
 	)
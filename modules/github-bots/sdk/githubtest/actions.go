@@ -0,0 +1,177 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package githubtest
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v88/github"
+)
+
+func (s *Server) registerActions(mux *http.ServeMux) {
+	mux.HandleFunc("GET /repos/{owner}/{repo}/actions/runs", s.listWorkflowRuns)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/actions/workflows/{workflow}/runs", s.listWorkflowRuns)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/actions/workflows/{workflow}/dispatches", s.dispatchWorkflow)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/actions/runs/{id}", s.getWorkflowRun)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/actions/runs/{id}/rerun-failed-jobs", s.rerunFailedJobs)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/actions/runs/{id}/logs", s.getWorkflowRunLogs)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/actions/runs/{id}/artifacts", s.listWorkflowRunArtifacts)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/actions/artifacts/{id}/zip", s.downloadArtifact)
+}
+
+func (s *Server) listWorkflowRuns(w http.ResponseWriter, r *http.Request) {
+	rs := s.repoFor(r)
+	q := r.URL.Query()
+
+	ids := make([]int64, 0, len(rs.runs))
+	for id := range rs.runs {
+		ids = append(ids, id)
+	}
+	// Newest first, as GitHub lists them.
+	slices.Sort(ids)
+	slices.Reverse(ids)
+
+	runs := []*github.WorkflowRun{}
+	for _, id := range ids {
+		run := rs.runs[id]
+		if wf := r.PathValue("workflow"); wf != "" && wf != path.Base(run.GetPath()) && wf != strconv.FormatInt(run.GetWorkflowID(), 10) {
+			continue
+		}
+		if v := q.Get("head_sha"); v != "" && v != run.GetHeadSHA() {
+			continue
+		}
+		if v := q.Get("branch"); v != "" && v != run.GetHeadBranch() {
+			continue
+		}
+		if v := q.Get("event"); v != "" && v != run.GetEvent() {
+			continue
+		}
+		if v := q.Get("status"); v != "" && v != run.GetStatus() && v != run.GetConclusion() {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	start, end := s.paginate(w, r, len(runs))
+	writeJSON(w, http.StatusOK, &github.WorkflowRuns{
+		TotalCount:   github.Ptr(len(runs)),
+		WorkflowRuns: runs[start:end],
+	})
+}
+
+// dispatchWorkflow starts a queued run of the workflow. Its title lists the
+// values of the inputs, ordered by name, as if the workflow's run-name
+// included all of them.
+func (s *Server) dispatchWorkflow(w http.ResponseWriter, r *http.Request) {
+	var req github.CreateWorkflowDispatchEventRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Ref == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid request: ref is required")
+		return
+	}
+	rs := s.repoFor(r)
+	var values []string
+	for _, name := range slices.Sorted(maps.Keys(req.Inputs)) {
+		values = append(values, fmt.Sprint(req.Inputs[name]))
+	}
+	wf := r.PathValue("workflow")
+	run := &github.WorkflowRun{
+		ID:           github.Ptr(s.id()),
+		Name:         github.Ptr(wf),
+		DisplayTitle: github.Ptr(strings.Join(values, " ")),
+		Path:         github.Ptr(".github/workflows/" + wf),
+		Event:        github.Ptr("workflow_dispatch"),
+		HeadBranch:   github.Ptr(strings.TrimPrefix(req.Ref, "refs/heads/")),
+		Status:       github.Ptr("queued"),
+		RunAttempt:   github.Ptr(1),
+		Repository:   rs.githubRepo(),
+	}
+	rs.runs[run.GetID()] = run
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getWorkflowRun(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	run, ok := s.repoFor(r).runs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+func (s *Server) rerunFailedJobs(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	run, ok := s.repoFor(r).runs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if run.GetStatus() != "completed" {
+		writeError(w, http.StatusForbidden, "This workflow is already running")
+		return
+	}
+	run.RunAttempt = github.Ptr(run.GetRunAttempt() + 1)
+	run.Status = github.Ptr("queued")
+	run.Conclusion = nil
+	writeJSON(w, http.StatusCreated, struct{}{})
+}
+
+// getWorkflowRunLogs redirects to the zip archive of the run's logs, like
+// GitHub redirects to a short-lived blob storage URL.
+func (s *Server) getWorkflowRunLogs(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	key, ok := s.repoFor(r).runLogs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("%s/_blobs/%s", s.srv.URL, key))
+	w.WriteHeader(http.StatusFound)
+}
+
+func (s *Server) listWorkflowRunArtifacts(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	artifacts := append([]*github.Artifact{}, s.repoFor(r).artifacts[id]...)
+	start, end := s.paginate(w, r, len(artifacts))
+	writeJSON(w, http.StatusOK, &github.ArtifactList{
+		TotalCount: github.Ptr(int64(len(artifacts))),
+		Artifacts:  artifacts[start:end],
+	})
+}
+
+func (s *Server) downloadArtifact(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	key := fmt.Sprintf("artifact-%d", id)
+	if _, ok := s.blobs[key]; !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("%s/_blobs/%s", s.srv.URL, key))
+	w.WriteHeader(http.StatusFound)
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package githubtest
+
+import (
+	"cmp"
+	"net/http"
+
+	"github.com/google/go-github/v88/github"
+)
+
+func (s *Server) registerChecks(mux *http.ServeMux) {
+	mux.HandleFunc("POST /repos/{owner}/{repo}/check-runs", s.createCheckRun)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/check-runs/{id}", s.getCheckRun)
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}/check-runs/{id}", s.updateCheckRun)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/commits/{ref}/check-runs", s.listCheckRunsForRef)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/statuses/{sha}", s.createStatus)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/commits/{ref}/statuses", s.listStatuses)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/commits/{ref}/status", s.getCombinedStatus)
+}
+
+func (s *Server) createCheckRun(w http.ResponseWriter, r *http.Request) {
+	var opts github.CreateCheckRunOptions
+	if !decode(w, r, &opts) {
+		return
+	}
+	cr := &github.CheckRun{
+		ID:          github.Ptr(s.id()),
+		Name:        github.Ptr(opts.Name),
+		HeadSHA:     github.Ptr(opts.HeadSHA),
+		DetailsURL:  opts.DetailsURL,
+		ExternalID:  opts.ExternalID,
+		Status:      opts.Status,
+		Conclusion:  opts.Conclusion,
+		StartedAt:   opts.StartedAt,
+		CompletedAt: opts.CompletedAt,
+		Output:      opts.Output,
+	}
+	if cr.Status == nil {
+		cr.Status = github.Ptr("queued")
+	}
+	if cr.Conclusion != nil {
+		cr.Status = github.Ptr("completed")
+	}
+	rs := s.repoFor(r)
+	rs.checkRuns = append(rs.checkRuns, cr)
+	writeJSON(w, http.StatusCreated, cr)
+}
+
+func (rs *repoState) checkRun(id int64) (*github.CheckRun, bool) {
+	for _, cr := range rs.checkRuns {
+		if cr.GetID() == id {
+			return cr, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) getCheckRun(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	cr, ok := s.repoFor(r).checkRun(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, cr)
+}
+
+func (s *Server) updateCheckRun(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	cr, ok := s.repoFor(r).checkRun(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var opts github.UpdateCheckRunOptions
+	if !decode(w, r, &opts) {
+		return
+	}
+	if opts.Name != "" {
+		cr.Name = github.Ptr(opts.Name)
+	}
+	if opts.DetailsURL != nil {
+		cr.DetailsURL = opts.DetailsURL
+	}
+	if opts.ExternalID != nil {
+		cr.ExternalID = opts.ExternalID
+	}
+	if opts.Status != nil {
+		cr.Status = opts.Status
+	}
+	if opts.Conclusion != nil {
+		cr.Conclusion = opts.Conclusion
+		cr.Status = github.Ptr("completed")
+	}
+	if opts.CompletedAt != nil {
+		cr.CompletedAt = opts.CompletedAt
+	}
+	if opts.Output != nil {
+		cr.Output = opts.Output
+	}
+	writeJSON(w, http.StatusOK, cr)
+}
+
+func (s *Server) listCheckRunsForRef(w http.ResponseWriter, r *http.Request) {
+	ref := r.PathValue("ref")
+	name := r.URL.Query().Get("check_name")
+
+	runs := []*github.CheckRun{}
+	for _, cr := range s.repoFor(r).checkRuns {
+		if cr.GetHeadSHA() != ref || (name != "" && cr.GetName() != name) {
+			continue
+		}
+		runs = append(runs, cr)
+	}
+	start, end := s.paginate(w, r, len(runs))
+	writeJSON(w, http.StatusOK, &github.ListCheckRunsResults{
+		Total:     github.Ptr(len(runs)),
+		CheckRuns: runs[start:end],
+	})
+}
+
+func (s *Server) createStatus(w http.ResponseWriter, r *http.Request) {
+	var req github.RepoStatus
+	if !decode(w, r, &req) {
+		return
+	}
+	switch req.GetState() {
+	case "error", "failure", "pending", "success":
+	default:
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	if len(req.GetDescription()) > 140 {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed: description is too long (maximum is 140 characters)")
+		return
+	}
+	st := &github.RepoStatus{
+		ID:          github.Ptr(s.id()),
+		State:       req.State,
+		TargetURL:   req.TargetURL,
+		Description: req.Description,
+		Context:     github.Ptr(cmp.Or(req.GetContext(), "default")),
+	}
+	rs := s.repoFor(r)
+	sha := r.PathValue("sha")
+	rs.statuses[sha] = append(rs.statuses[sha], st)
+	writeJSON(w, http.StatusCreated, st)
+}
+
+// latestStatuses returns the latest status of each context on sha. Callers
+// must hold s.mu.
+func (rs *repoState) latestStatuses(sha string) []*github.RepoStatus {
+	var latest []*github.RepoStatus
+	seen := map[string]bool{}
+	all := rs.statuses[sha]
+	for i := len(all) - 1; i >= 0; i-- {
+		if !seen[all[i].GetContext()] {
+			seen[all[i].GetContext()] = true
+			latest = append(latest, all[i])
+		}
+	}
+	return latest
+}
+
+func (s *Server) listStatuses(w http.ResponseWriter, r *http.Request) {
+	all := s.repoFor(r).statuses[r.PathValue("ref")]
+	// Newest first, as GitHub lists them.
+	statuses := make([]*github.RepoStatus, 0, len(all))
+	for i := len(all) - 1; i >= 0; i-- {
+		statuses = append(statuses, all[i])
+	}
+	start, end := s.paginate(w, r, len(statuses))
+	writeJSON(w, http.StatusOK, statuses[start:end])
+}
+
+func (s *Server) getCombinedStatus(w http.ResponseWriter, r *http.Request) {
+	ref := r.PathValue("ref")
+	latest := s.repoFor(r).latestStatuses(ref)
+	state := "success"
+	if len(latest) == 0 {
+		state = "pending"
+	}
+	for _, st := range latest {
+		switch st.GetState() {
+		case "error", "failure":
+			state = "failure"
+		case "pending":
+			if state == "success" {
+				state = "pending"
+			}
+		}
+	}
+	start, end := s.paginate(w, r, len(latest))
+	writeJSON(w, http.StatusOK, &github.CombinedStatus{
+		State:      github.Ptr(state),
+		SHA:        github.Ptr(ref),
+		TotalCount: github.Ptr(len(latest)),
+		Statuses:   latest[start:end],
+	})
+}
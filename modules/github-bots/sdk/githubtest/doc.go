@@ -0,0 +1,38 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package githubtest provides an in-memory fake of the GitHub REST API for
+// testing bots built with the sdk package.
+//
+// [NewServer] starts a server backed by per-repository state that tests seed
+// with issues, pull requests and their changed files, labels, comments, files,
+// commits, releases and workflow runs. Point a client at it with
+// sdk.WithClient and [Server.Client]:
+//
+//	srv := githubtest.NewServer(t)
+//	pr := srv.AddPullRequest("org", "repo", &github.PullRequest{})
+//	gh := sdk.NewGitHubClient(ctx, "org", "repo", "policy", sdk.WithClient(srv.Client()))
+//
+// The server keeps the writes it receives, so tests assert on resulting state
+// with [Server.AssertLabels], [Server.AssertComment], [Server.AssertCheckRun]
+// and [Server.AssertStatus], on submitted reviews with [Server.Reviews], or on
+// the raw requests with [Server.Requests].
+// [Server.RateLimitNext] injects secondary rate limit responses, and
+// [Server.SetRateLimit] sets the quota reported by the rate limit API.
+//
+// Files set with [Server.SetFile] are served by the contents, trees and blobs
+// APIs. [Server.SetTreeLimit] truncates recursive tree listings like GitHub
+// does for very large repositories.
+//
+// Workflow run logs and artifacts are served as zip archives behind the same
+// redirect GitHub uses, so streaming readers such as
+// sdk.GitHubClient.FetchWorkflowRunLogs work against it unchanged. Workflow
+// dispatches start queued runs, which tests complete with
+// [Server.SetWorkflowRunStatus].
+//
+// The GraphQL endpoint supports the auto-merge mutations only. Tests set the
+// mergeability of pull requests with [Server.SetMergeability], which decides
+// whether auto-merge can be enabled and branches updated.
+package githubtest
@@ -0,0 +1,34 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package githubtest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk"
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	"github.com/google/go-github/v88/github"
+)
+
+func ExampleNewServer() {
+	// In a test, pass the *testing.T instead.
+	t := &testing.T{}
+	ctx := context.Background()
+
+	srv := githubtest.NewServer(t)
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{})
+
+	gh := sdk.NewGitHubClient(ctx, "acme", "widgets", "my-bot", sdk.WithClient(srv.Client()))
+	if err := gh.AddLabel(ctx, pr, "needs-review"); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(srv.Labels("acme", "widgets", pr.GetNumber()))
+	// Output: [needs-review]
+}
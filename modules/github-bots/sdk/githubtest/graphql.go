@@ -0,0 +1,78 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package githubtest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v88/github"
+)
+
+// graphQLRequest is the body of a GraphQL request.
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// graphql serves the GraphQL mutations used by the SDK. Like GitHub, it
+// reports failures as errors in a 200 OK response.
+func (s *Server) graphql(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	id, _ := req.Variables["id"].(string)
+
+	switch {
+	case strings.Contains(req.Query, "enablePullRequestAutoMerge"):
+		pr := s.pullByNodeID(id)
+		if pr == nil {
+			writeGraphQLError(w, "NOT_FOUND", fmt.Sprintf("Could not resolve to a node with the global id of '%s'", id))
+			return
+		}
+		if pr.GetMergeableState() == "clean" {
+			writeGraphQLError(w, "UNPROCESSABLE", "Pull request is in clean status")
+			return
+		}
+		method, _ := req.Variables["method"].(string)
+		pr.AutoMerge = &github.PullRequestAutoMerge{MergeMethod: github.Ptr(strings.ToLower(method))}
+		writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"enablePullRequestAutoMerge": map[string]any{}}})
+
+	case strings.Contains(req.Query, "disablePullRequestAutoMerge"):
+		pr := s.pullByNodeID(id)
+		if pr == nil {
+			writeGraphQLError(w, "NOT_FOUND", fmt.Sprintf("Could not resolve to a node with the global id of '%s'", id))
+			return
+		}
+		pr.AutoMerge = nil
+		writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"disablePullRequestAutoMerge": map[string]any{}}})
+
+	default:
+		writeGraphQLError(w, "UNSUPPORTED", "githubtest does not support this query")
+	}
+}
+
+// pullByNodeID returns the pull request with the given node ID, or nil.
+// Callers must hold s.mu.
+func (s *Server) pullByNodeID(id string) *github.PullRequest {
+	for _, rs := range s.repos {
+		for _, pr := range rs.pulls {
+			if pr.GetNodeID() == id {
+				return pr
+			}
+		}
+	}
+	return nil
+}
+
+func writeGraphQLError(w http.ResponseWriter, typ, message string) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data":   nil,
+		"errors": []map[string]any{{"type": typ, "message": message}},
+	})
+}
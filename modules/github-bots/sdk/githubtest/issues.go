@@ -0,0 +1,382 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package githubtest
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v88/github"
+)
+
+func (s *Server) registerIssues(mux *http.ServeMux) {
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues", s.listIssues)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues", s.createIssue)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}", s.getIssue)
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}/issues/{number}", s.editIssue)
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}/comments", s.listComments)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/comments", s.createComment)
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}/issues/comments/{id}", s.editComment)
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/issues/comments/{id}", s.deleteComment)
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}/labels", s.listIssueLabels)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/labels", s.addIssueLabels)
+	mux.HandleFunc("PUT /repos/{owner}/{repo}/issues/{number}/labels", s.replaceIssueLabels)
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/issues/{number}/labels/{name...}", s.removeIssueLabel)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/labels", s.listLabels)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/labels", s.createLabel)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/labels/{name...}", s.getLabel)
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls", s.listPulls)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}", s.getPull)
+}
+
+// issue returns the issue with the given number, synthesizing one from a
+// pull request if needed, as GitHub serves pull requests from the issues API.
+func (rs *repoState) issue(number int) (*github.Issue, bool) {
+	if issue, ok := rs.issues[number]; ok {
+		issue.Labels = rs.labelsOf(number)
+		return issue, true
+	}
+	if pr, ok := rs.pulls[number]; ok {
+		return &github.Issue{
+			Number:           pr.Number,
+			Title:            pr.Title,
+			Body:             pr.Body,
+			State:            pr.State,
+			User:             pr.User,
+			Labels:           rs.labelsOf(number),
+			PullRequestLinks: &github.PullRequestLinks{URL: pr.URL},
+		}, true
+	}
+	return nil, false
+}
+
+func (s *Server) listIssues(w http.ResponseWriter, r *http.Request) {
+	rs := s.repoFor(r)
+	state := cmp.Or(r.URL.Query().Get("state"), "open")
+
+	numbers := make([]int, 0, len(rs.issues)+len(rs.pulls))
+	for n := range rs.issues {
+		numbers = append(numbers, n)
+	}
+	for n := range rs.pulls {
+		numbers = append(numbers, n)
+	}
+	slices.Sort(numbers)
+
+	issues := []*github.Issue{}
+	for _, n := range numbers {
+		issue, _ := rs.issue(n)
+		if state == "all" || issue.GetState() == state {
+			issues = append(issues, issue)
+		}
+	}
+	start, end := s.paginate(w, r, len(issues))
+	writeJSON(w, http.StatusOK, issues[start:end])
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request) {
+	rs := s.repoFor(r)
+	var req github.IssueRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	issue := &github.Issue{
+		Number: github.Ptr(rs.nextNumber()),
+		Title:  req.Title,
+		Body:   req.Body,
+		State:  github.Ptr("open"),
+	}
+	rs.issues[issue.GetNumber()] = issue
+	if req.Labels != nil {
+		rs.issueLabels[issue.GetNumber()] = slices.Clone(*req.Labels)
+	}
+	issue.Labels = rs.labelsOf(issue.GetNumber())
+	writeJSON(w, http.StatusCreated, issue)
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	issue, ok := s.repoFor(r).issue(int(number))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, issue)
+}
+
+func (s *Server) editIssue(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	rs := s.repoFor(r)
+	issue, ok := rs.issues[int(number)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var req github.IssueRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Title != nil {
+		issue.Title = req.Title
+	}
+	if req.Body != nil {
+		issue.Body = req.Body
+	}
+	if req.State != nil {
+		issue.State = req.State
+	}
+	if req.Labels != nil {
+		rs.issueLabels[int(number)] = slices.Clone(*req.Labels)
+	}
+	issue.Labels = rs.labelsOf(int(number))
+	writeJSON(w, http.StatusOK, issue)
+}
+
+func (s *Server) listComments(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	comments := append([]*github.IssueComment{}, s.repoFor(r).comments[int(number)]...)
+	start, end := s.paginate(w, r, len(comments))
+	writeJSON(w, http.StatusOK, comments[start:end])
+}
+
+func (s *Server) createComment(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	var req github.IssueComment
+	if !decode(w, r, &req) {
+		return
+	}
+	rs := s.repoFor(r)
+	comment := &github.IssueComment{
+		ID:   github.Ptr(s.id()),
+		Body: req.Body,
+	}
+	rs.comments[int(number)] = append(rs.comments[int(number)], comment)
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+// findComment returns the issue number and index of the comment with the
+// given ID.
+func (rs *repoState) findComment(id int64) (int, int, bool) {
+	for number, comments := range rs.comments {
+		for i, c := range comments {
+			if c.GetID() == id {
+				return number, i, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func (s *Server) editComment(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	rs := s.repoFor(r)
+	number, i, ok := rs.findComment(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var req github.IssueComment
+	if !decode(w, r, &req) {
+		return
+	}
+	comment := rs.comments[number][i]
+	comment.Body = req.Body
+	writeJSON(w, http.StatusOK, comment)
+}
+
+func (s *Server) deleteComment(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	rs := s.repoFor(r)
+	number, i, ok := rs.findComment(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	rs.comments[number] = slices.Delete(rs.comments[number], i, i+1)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listIssueLabels(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	labels := s.repoFor(r).labelsOf(int(number))
+	start, end := s.paginate(w, r, len(labels))
+	writeJSON(w, http.StatusOK, labels[start:end])
+}
+
+func (s *Server) addIssueLabels(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	var names []string
+	if !decode(w, r, &names) {
+		return
+	}
+	rs := s.repoFor(r)
+	for _, name := range names {
+		if !slices.Contains(rs.issueLabels[int(number)], name) {
+			rs.issueLabels[int(number)] = append(rs.issueLabels[int(number)], name)
+		}
+		rs.ensureLabel(name)
+	}
+	writeJSON(w, http.StatusOK, rs.labelsOf(int(number)))
+}
+
+func (s *Server) replaceIssueLabels(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	var names []string
+	if !decode(w, r, &names) {
+		return
+	}
+	rs := s.repoFor(r)
+	rs.issueLabels[int(number)] = slices.Compact(slices.Clone(names))
+	for _, name := range names {
+		rs.ensureLabel(name)
+	}
+	writeJSON(w, http.StatusOK, rs.labelsOf(int(number)))
+}
+
+func (s *Server) removeIssueLabel(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	rs := s.repoFor(r)
+	name := r.PathValue("name")
+	i := slices.Index(rs.issueLabels[int(number)], name)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Label does not exist")
+		return
+	}
+	rs.issueLabels[int(number)] = slices.Delete(rs.issueLabels[int(number)], i, i+1)
+	writeJSON(w, http.StatusOK, rs.labelsOf(int(number)))
+}
+
+// ensureLabel defines a repository label if it does not exist, as GitHub
+// does when a new label is added to an issue.
+func (rs *repoState) ensureLabel(name string) {
+	if _, ok := rs.labels[name]; !ok {
+		rs.labels[name] = &github.Label{Name: github.Ptr(name), Color: github.Ptr("ededed")}
+	}
+}
+
+func (s *Server) listLabels(w http.ResponseWriter, r *http.Request) {
+	rs := s.repoFor(r)
+	labels := make([]*github.Label, 0, len(rs.labels))
+	for _, l := range rs.labels {
+		labels = append(labels, l)
+	}
+	slices.SortFunc(labels, func(a, b *github.Label) int { return strings.Compare(a.GetName(), b.GetName()) })
+	start, end := s.paginate(w, r, len(labels))
+	writeJSON(w, http.StatusOK, labels[start:end])
+}
+
+func (s *Server) createLabel(w http.ResponseWriter, r *http.Request) {
+	var label github.Label
+	if !decode(w, r, &label) {
+		return
+	}
+	rs := s.repoFor(r)
+	if _, ok := rs.labels[label.GetName()]; ok {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{
+			"message": "Validation Failed",
+			"errors": []map[string]string{{
+				"resource": "Label",
+				"code":     "already_exists",
+				"field":    "name",
+			}},
+		})
+		return
+	}
+	if label.Color == nil {
+		label.Color = github.Ptr("ededed")
+	}
+	rs.labels[label.GetName()] = &label
+	writeJSON(w, http.StatusCreated, &label)
+}
+
+func (s *Server) getLabel(w http.ResponseWriter, r *http.Request) {
+	label, ok := s.repoFor(r).labels[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, label)
+}
+
+func (s *Server) listPulls(w http.ResponseWriter, r *http.Request) {
+	rs := s.repoFor(r)
+	q := r.URL.Query()
+	state := cmp.Or(q.Get("state"), "open")
+
+	numbers := make([]int, 0, len(rs.pulls))
+	for n := range rs.pulls {
+		numbers = append(numbers, n)
+	}
+	slices.Sort(numbers)
+
+	pulls := []*github.PullRequest{}
+	for _, n := range numbers {
+		pr := rs.pulls[n]
+		if state != "all" && pr.GetState() != state {
+			continue
+		}
+		if head := q.Get("head"); head != "" && !strings.HasSuffix(head, ":"+pr.GetHead().GetRef()) {
+			continue
+		}
+		if base := q.Get("base"); base != "" && base != pr.GetBase().GetRef() {
+			continue
+		}
+		pr.Labels = rs.labelsOf(n)
+		pulls = append(pulls, pr)
+	}
+	start, end := s.paginate(w, r, len(pulls))
+	writeJSON(w, http.StatusOK, pulls[start:end])
+}
+
+func (s *Server) getPull(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	rs := s.repoFor(r)
+	pr, ok := rs.pulls[int(number)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	pr.Labels = rs.labelsOf(int(number))
+	writeJSON(w, http.StatusOK, pr)
+}
@@ -0,0 +1,24 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package githubtest
+
+import (
+	"net/http"
+)
+
+func (s *Server) registerOrgs(mux *http.ServeMux) {
+	mux.HandleFunc("GET /orgs/{org}/teams/{team}/members", s.listTeamMembers)
+}
+
+func (s *Server) listTeamMembers(w http.ResponseWriter, r *http.Request) {
+	members, ok := s.teams[r.PathValue("org")+"/"+r.PathValue("team")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	start, end := s.paginate(w, r, len(members))
+	writeJSON(w, http.StatusOK, members[start:end])
+}
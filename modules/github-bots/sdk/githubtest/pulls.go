@@ -0,0 +1,175 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package githubtest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v88/github"
+)
+
+func (s *Server) registerPulls(mux *http.ServeMux) {
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}/files", s.listPullFiles)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}/reviews", s.listReviews)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/pulls/{number}/reviews", s.createReview)
+	mux.HandleFunc("PUT /repos/{owner}/{repo}/pulls/{number}/reviews/{id}/dismissals", s.dismissReview)
+	mux.HandleFunc("PUT /repos/{owner}/{repo}/pulls/{number}/update-branch", s.updateBranch)
+}
+
+func (s *Server) listPullFiles(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	rs := s.repoFor(r)
+	if _, ok := rs.pulls[int(number)]; !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	files := rs.pullFiles[int(number)]
+	if files == nil {
+		files = []*github.CommitFile{}
+	}
+	start, end := s.paginate(w, r, len(files))
+	writeJSON(w, http.StatusOK, files[start:end])
+}
+
+func (s *Server) listReviews(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	reviews := s.repoFor(r).reviews[int(number)]
+	if reviews == nil {
+		reviews = []*github.PullRequestReview{}
+	}
+	start, end := s.paginate(w, r, len(reviews))
+	writeJSON(w, http.StatusOK, reviews[start:end])
+}
+
+func (s *Server) createReview(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	var req github.PullRequestReviewRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	rs := s.repoFor(r)
+	pr, ok := rs.pulls[int(number)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	state := "COMMENTED"
+	switch req.GetEvent() {
+	case "APPROVE":
+		state = "APPROVED"
+	case "REQUEST_CHANGES":
+		state = "CHANGES_REQUESTED"
+	case "", "COMMENT":
+	default:
+		writeError(w, http.StatusUnprocessableEntity, "Unprocessable Entity")
+		return
+	}
+	commitID := req.CommitID
+	if commitID == nil && pr.Head != nil {
+		commitID = pr.Head.SHA
+	}
+	review := &github.PullRequestReview{
+		ID:       github.Ptr(s.id()),
+		Body:     req.Body,
+		State:    github.Ptr(state),
+		CommitID: commitID,
+	}
+	for _, c := range req.Comments {
+		rs.reviewComms[int(number)] = append(rs.reviewComms[int(number)], &github.PullRequestComment{
+			ID:                  github.Ptr(s.id()),
+			PullRequestReviewID: review.ID,
+			CommitID:            commitID,
+			Path:                c.Path,
+			Position:            c.Position,
+			Body:                c.Body,
+			Line:                c.Line,
+			Side:                c.Side,
+			StartLine:           c.StartLine,
+			StartSide:           c.StartSide,
+		})
+	}
+	rs.reviews[int(number)] = append(rs.reviews[int(number)], review)
+	writeJSON(w, http.StatusOK, review)
+}
+
+func (s *Server) dismissReview(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	var req github.PullRequestReviewDismissalRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	for _, review := range s.repoFor(r).reviews[int(number)] {
+		if review.GetID() != id {
+			continue
+		}
+		switch review.GetState() {
+		case "APPROVED", "CHANGES_REQUESTED":
+		default:
+			writeError(w, http.StatusUnprocessableEntity, "Can not dismiss a "+strings.ToLower(review.GetState())+" pull request review")
+			return
+		}
+		review.State = github.Ptr("DISMISSED")
+		writeJSON(w, http.StatusOK, review)
+		return
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// updateBranch moves the head of the pull request to a new merge commit.
+// Pull requests whose mergeable state is "dirty" conflict with their base and
+// can't be updated; those that were "behind" become "clean".
+func (s *Server) updateBranch(w http.ResponseWriter, r *http.Request) {
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return
+	}
+	var req github.PullRequestBranchUpdateOptions
+	if !decode(w, r, &req) {
+		return
+	}
+	pr, ok := s.repoFor(r).pulls[int(number)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if req.ExpectedHeadSHA != nil && req.GetExpectedHeadSHA() != pr.GetHead().GetSHA() {
+		writeError(w, http.StatusUnprocessableEntity, "expected head sha didn't match current head ref.")
+		return
+	}
+	switch pr.GetMergeableState() {
+	case "dirty":
+		writeError(w, http.StatusUnprocessableEntity, "merge conflict between base and head")
+		return
+	case "behind":
+		pr.MergeableState = github.Ptr("clean")
+	}
+	if pr.Head == nil {
+		pr.Head = &github.PullRequestBranch{}
+	}
+	pr.Head.SHA = github.Ptr(fmt.Sprintf("%040x", s.id()))
+	writeJSON(w, http.StatusAccepted, &github.PullRequestBranchUpdateResponse{
+		Message: github.Ptr("Updating pull request branch."),
+	})
+}
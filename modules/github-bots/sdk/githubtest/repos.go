@@ -0,0 +1,261 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package githubtest
+
+import (
+	"cmp"
+	"crypto/sha1" //nolint:gosec // git object IDs are SHA-1
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v88/github"
+)
+
+func (s *Server) registerRepos(mux *http.ServeMux) {
+	mux.HandleFunc("GET /repos/{owner}/{repo}", s.getRepo)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/contents/{path...}", s.getContents)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/git/trees/{sha}", s.getTree)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/git/blobs/{sha}", s.getBlob)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/compare/{basehead...}", s.compareCommits)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/commits/{sha}", s.getCommit)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/releases", s.listReleases)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/releases/{id}", s.getRelease)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/releases/tags/{tag}", s.getReleaseByTag)
+}
+
+func (s *Server) getRepo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.repoFor(r).githubRepo())
+}
+
+func (s *Server) getContents(w http.ResponseWriter, r *http.Request) {
+	rs := s.repoFor(r)
+	ref := cmp.Or(r.URL.Query().Get("ref"), rs.defaultBranch)
+	files := rs.files[ref]
+	p := strings.Trim(r.PathValue("path"), "/")
+
+	if content, ok := files[p]; ok {
+		rc := &github.RepositoryContent{
+			Type:     github.Ptr("file"),
+			Name:     github.Ptr(path.Base(p)),
+			Path:     github.Ptr(p),
+			SHA:      github.Ptr(blobSHA(content)),
+			Size:     github.Ptr(len(content)),
+			Encoding: github.Ptr("base64"),
+			Content:  github.Ptr(base64.StdEncoding.EncodeToString([]byte(content))),
+		}
+		if len(content) > maxContentSize {
+			// Like GitHub, leave the content of large files to the blobs API.
+			rc.Encoding, rc.Content = github.Ptr("none"), github.Ptr("")
+		}
+		writeJSON(w, http.StatusOK, rc)
+		return
+	}
+
+	// Otherwise list the directory, if any file is beneath it.
+	prefix := p + "/"
+	if p == "" {
+		prefix = ""
+	}
+	entries := map[string]*github.RepositoryContent{}
+	for fp, content := range files {
+		rest, ok := strings.CutPrefix(fp, prefix)
+		if !ok {
+			continue
+		}
+		name, _, isDir := strings.Cut(rest, "/")
+		if isDir {
+			entries[name] = &github.RepositoryContent{
+				Type: github.Ptr("dir"),
+				Name: github.Ptr(name),
+				Path: github.Ptr(prefix + name),
+			}
+		} else {
+			entries[name] = &github.RepositoryContent{
+				Type: github.Ptr("file"),
+				Name: github.Ptr(name),
+				Path: github.Ptr(fp),
+				SHA:  github.Ptr(blobSHA(content)),
+				Size: github.Ptr(len(content)),
+			}
+		}
+	}
+	if len(entries) == 0 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	dir := make([]*github.RepositoryContent, 0, len(names))
+	for _, name := range names {
+		dir = append(dir, entries[name])
+	}
+	writeJSON(w, http.StatusOK, dir)
+}
+
+// maxContentSize is the size above which the contents API omits the content
+// of files.
+const maxContentSize = 1 << 20
+
+// treeKey identifies the directory dir of the files at ref.
+type treeKey struct {
+	ref, dir string
+}
+
+// treeSHA returns a stable SHA for the directory dir at ref, registering it so
+// it can be fetched. Callers must hold s.mu.
+func (rs *repoState) treeSHA(ref, dir string) string {
+	h := sha1.New() //nolint:gosec // git object IDs are SHA-1
+	fmt.Fprintf(h, "tree %s\x00%s", ref, dir)
+	sha := hex.EncodeToString(h.Sum(nil))
+	rs.trees[sha] = treeKey{ref: ref, dir: dir}
+	return sha
+}
+
+func (s *Server) getTree(w http.ResponseWriter, r *http.Request) {
+	rs := s.repoFor(r)
+	key, ok := rs.trees[r.PathValue("sha")]
+	if !ok {
+		// Otherwise the root tree of a ref.
+		if _, ok := rs.files[r.PathValue("sha")]; !ok {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		key = treeKey{ref: r.PathValue("sha")}
+	}
+	recursive := r.URL.Query().Get("recursive") != ""
+
+	prefix := key.dir + "/"
+	if key.dir == "" {
+		prefix = ""
+	}
+	entries := map[string]*github.TreeEntry{}
+	for fp, content := range rs.files[key.ref] {
+		rest, ok := strings.CutPrefix(fp, prefix)
+		if !ok {
+			continue
+		}
+		parts := strings.Split(rest, "/")
+		if !recursive && len(parts) > 1 {
+			parts = parts[:1]
+		}
+		for i := range parts {
+			rel := strings.Join(parts[:i+1], "/")
+			if i < len(parts)-1 || rel != rest {
+				entries[rel] = &github.TreeEntry{
+					Path: github.Ptr(rel),
+					Mode: github.Ptr("040000"),
+					Type: github.Ptr("tree"),
+					SHA:  github.Ptr(rs.treeSHA(key.ref, prefix+rel)),
+				}
+				continue
+			}
+			entries[rel] = &github.TreeEntry{
+				Path: github.Ptr(rel),
+				Mode: github.Ptr("100644"),
+				Type: github.Ptr("blob"),
+				SHA:  github.Ptr(blobSHA(content)),
+				Size: github.Ptr(len(content)),
+			}
+		}
+	}
+	paths := make([]string, 0, len(entries))
+	for p := range entries {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	tree := &github.Tree{
+		SHA:       github.Ptr(rs.treeSHA(key.ref, key.dir)),
+		Truncated: github.Ptr(false),
+	}
+	if recursive && rs.treeLimit > 0 && len(paths) > rs.treeLimit {
+		paths, tree.Truncated = paths[:rs.treeLimit], github.Ptr(true)
+	}
+	for _, p := range paths {
+		tree.Entries = append(tree.Entries, entries[p])
+	}
+	writeJSON(w, http.StatusOK, tree)
+}
+
+func (s *Server) getBlob(w http.ResponseWriter, r *http.Request) {
+	sha := r.PathValue("sha")
+	for _, files := range s.repoFor(r).files {
+		for _, content := range files {
+			if blobSHA(content) != sha {
+				continue
+			}
+			if strings.Contains(r.Header.Get("Accept"), "raw") {
+				w.Header().Set("Content-Type", "application/octet-stream")
+				_, _ = io.WriteString(w, content)
+				return
+			}
+			writeJSON(w, http.StatusOK, &github.Blob{
+				SHA:      github.Ptr(sha),
+				Size:     github.Ptr(len(content)),
+				Encoding: github.Ptr("base64"),
+				Content:  github.Ptr(base64.StdEncoding.EncodeToString([]byte(content))),
+			})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) compareCommits(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.repoFor(r).comparisons[r.PathValue("basehead")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (s *Server) getCommit(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.repoFor(r).commits[r.PathValue("sha")]
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "No commit found for SHA: "+r.PathValue("sha"))
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (s *Server) listReleases(w http.ResponseWriter, r *http.Request) {
+	releases := append([]*github.RepositoryRelease{}, s.repoFor(r).releases...)
+	start, end := s.paginate(w, r, len(releases))
+	writeJSON(w, http.StatusOK, releases[start:end])
+}
+
+func (s *Server) getRelease(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	for _, rel := range s.repoFor(r).releases {
+		if rel.GetID() == id {
+			writeJSON(w, http.StatusOK, rel)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) getReleaseByTag(w http.ResponseWriter, r *http.Request) {
+	for _, rel := range s.repoFor(r).releases {
+		if rel.GetTagName() == r.PathValue("tag") {
+			writeJSON(w, http.StatusOK, rel)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
@@ -0,0 +1,814 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package githubtest
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"crypto/sha1" //nolint:gosec // git object IDs are SHA-1
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v88/github"
+)
+
+// Request is a request received by the Server.
+type Request struct {
+	Method string
+	// Path is relative to the API root, for example "/repos/o/r/issues/1".
+	Path  string
+	Query url.Values
+	Body  []byte
+}
+
+// RateLimit describes a secondary rate limit response returned by the
+// Server, as handled by sdk.SecondaryRateLimitWaiter.
+type RateLimit struct {
+	// StatusCode is http.StatusForbidden or http.StatusTooManyRequests. It
+	// defaults to http.StatusForbidden.
+	StatusCode int
+	// RetryAfter sets the Retry-After header when non-zero.
+	RetryAfter time.Duration
+	// Reset sets X-Ratelimit-Remaining to 0 and X-Ratelimit-Reset to this
+	// time when non-zero.
+	Reset time.Time
+}
+
+// Server is an in-memory fake of the GitHub REST API endpoints used by the
+// SDK. It is safe for concurrent use.
+type Server struct {
+	tb  testing.TB
+	srv *httptest.Server
+
+	mu         sync.Mutex
+	nextID     int64
+	repos      map[string]*repoState
+	teams      map[string][]*github.User // org/slug -> members
+	blobs      map[string][]byte
+	requests   []Request
+	rateLimits []RateLimit
+
+	// remaining and reset are reported by the rate limit API.
+	remaining int
+	reset     time.Time
+}
+
+type repoState struct {
+	owner, name   string
+	defaultBranch string
+
+	issues      map[int]*github.Issue
+	pulls       map[int]*github.PullRequest
+	issueLabels map[int][]string
+	labels      map[string]*github.Label
+	comments    map[int][]*github.IssueComment
+	pullFiles   map[int][]*github.CommitFile
+	reviews     map[int][]*github.PullRequestReview
+	reviewComms map[int][]*github.PullRequestComment
+	checkRuns   []*github.CheckRun
+	statuses    map[string][]*github.RepoStatus // SHA -> statuses, oldest first
+	files       map[string]map[string]string    // ref -> path -> content
+	trees       map[string]treeKey              // tree SHA -> directory
+	treeLimit   int
+	commits     map[string]*github.RepositoryCommit
+	comparisons map[string]*github.CommitsComparison
+	releases    []*github.RepositoryRelease
+	runs        map[int64]*github.WorkflowRun
+	runLogs     map[int64]string // run ID -> blob key
+	artifacts   map[int64][]*github.Artifact
+}
+
+// NewServer starts a fake GitHub API server that is closed when the test
+// finishes.
+func NewServer(tb testing.TB) *Server {
+	tb.Helper()
+
+	s := &Server{
+		tb:     tb,
+		nextID: 1000,
+		repos:  make(map[string]*repoState),
+		teams:  make(map[string][]*github.User),
+		blobs:  make(map[string][]byte),
+
+		remaining: 5000,
+	}
+
+	api := http.NewServeMux()
+	s.registerIssues(api)
+	s.registerPulls(api)
+	s.registerChecks(api)
+	s.registerRepos(api)
+	s.registerActions(api)
+	s.registerOrgs(api)
+	api.HandleFunc("GET /rate_limit", s.getRateLimit)
+	api.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusNotFound, "Not Found")
+	})
+
+	root := http.NewServeMux()
+	root.Handle("/api/v3/", http.StripPrefix("/api/v3", s.intercept(api)))
+	// Like GitHub Enterprise Server, GraphQL is served outside the REST API
+	// root.
+	root.Handle("POST /api/graphql", http.StripPrefix("/api", s.intercept(http.HandlerFunc(s.graphql))))
+	root.HandleFunc("GET /_blobs/{key}", s.serveBlob)
+
+	s.srv = httptest.NewServer(root)
+	tb.Cleanup(s.srv.Close)
+	return s
+}
+
+// URL returns the base URL of the server, suitable for
+// github.WithEnterpriseURLs.
+func (s *Server) URL() string { return s.srv.URL }
+
+// Client returns a go-github client that talks to the server. Pass it to
+// sdk.WithClient to point a GitHubClient at the server. Options such as
+// github.WithTransport are applied before the server URLs.
+func (s *Server) Client(opts ...github.ClientOptionsFunc) *github.Client {
+	s.tb.Helper()
+	opts = append(opts, github.WithEnterpriseURLs(s.srv.URL, s.srv.URL))
+	c, err := github.NewClient(opts...)
+	if err != nil {
+		s.tb.Fatalf("creating client: %v", err)
+	}
+	return c
+}
+
+// RateLimitNext makes the next n requests fail with the given secondary
+// rate limit response.
+func (s *Server) RateLimitNext(n int, rl RateLimit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for range n {
+		s.rateLimits = append(s.rateLimits, rl)
+	}
+}
+
+// SetRateLimit sets the core rate limit reported by the rate limit API. It
+// defaults to 5000 remaining requests, resetting an hour after each call.
+func (s *Server) SetRateLimit(remaining int, reset time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remaining = remaining
+	s.reset = reset
+}
+
+func (s *Server) getRateLimit(w http.ResponseWriter, _ *http.Request) {
+	reset := s.reset
+	if reset.IsZero() {
+		reset = time.Now().Add(time.Hour)
+	}
+	core := &github.Rate{
+		Limit:     5000,
+		Remaining: s.remaining,
+		Reset:     github.Timestamp{Time: reset},
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Resources *github.RateLimits `json:"resources"`
+		Rate      *github.Rate       `json:"rate"`
+	}{&github.RateLimits{Core: core}, core})
+}
+
+// intercept records each request and applies any pending rate limits before
+// dispatching to the API handlers.
+func (s *Server) intercept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Body:   body,
+		})
+		var rl *RateLimit
+		if len(s.rateLimits) > 0 {
+			rl = &s.rateLimits[0]
+			s.rateLimits = s.rateLimits[1:]
+		}
+		s.mu.Unlock()
+
+		if rl != nil {
+			if rl.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(rl.RetryAfter.Seconds())))
+			}
+			if !rl.Reset.IsZero() {
+				w.Header().Set("X-Ratelimit-Remaining", "0")
+				w.Header().Set("X-Ratelimit-Reset", strconv.FormatInt(rl.Reset.Unix(), 10))
+			}
+			writeError(w, cmp.Or(rl.StatusCode, http.StatusForbidden), "You have exceeded a secondary rate limit. Please wait a few minutes before you try again.")
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) serveBlob(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data, ok := s.blobs[r.PathValue("key")]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+}
+
+// repo returns the state of owner/repo, creating it on first use. Callers
+// must hold s.mu.
+func (s *Server) repo(owner, name string) *repoState {
+	key := owner + "/" + name
+	if rs, ok := s.repos[key]; ok {
+		return rs
+	}
+	rs := &repoState{
+		owner:         owner,
+		name:          name,
+		defaultBranch: "main",
+		issues:        make(map[int]*github.Issue),
+		pulls:         make(map[int]*github.PullRequest),
+		issueLabels:   make(map[int][]string),
+		labels:        make(map[string]*github.Label),
+		comments:      make(map[int][]*github.IssueComment),
+		pullFiles:     make(map[int][]*github.CommitFile),
+		reviews:       make(map[int][]*github.PullRequestReview),
+		reviewComms:   make(map[int][]*github.PullRequestComment),
+		statuses:      make(map[string][]*github.RepoStatus),
+		files:         make(map[string]map[string]string),
+		trees:         make(map[string]treeKey),
+		commits:       make(map[string]*github.RepositoryCommit),
+		comparisons:   make(map[string]*github.CommitsComparison),
+		runs:          make(map[int64]*github.WorkflowRun),
+		runLogs:       make(map[int64]string),
+		artifacts:     make(map[int64][]*github.Artifact),
+	}
+	s.repos[key] = rs
+	return rs
+}
+
+func (s *Server) repoFor(r *http.Request) *repoState {
+	return s.repo(r.PathValue("owner"), r.PathValue("repo"))
+}
+
+// id returns a new unique ID. Callers must hold s.mu.
+func (s *Server) id() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+func (rs *repoState) githubRepo() *github.Repository {
+	return &github.Repository{
+		Name:          github.Ptr(rs.name),
+		FullName:      github.Ptr(rs.owner + "/" + rs.name),
+		Owner:         &github.User{Login: github.Ptr(rs.owner)},
+		DefaultBranch: github.Ptr(rs.defaultBranch),
+	}
+}
+
+func (rs *repoState) nextNumber() int {
+	n := 0
+	for k := range rs.issues {
+		n = max(n, k)
+	}
+	for k := range rs.pulls {
+		n = max(n, k)
+	}
+	return n + 1
+}
+
+// labelsOf returns the labels of the given issue or pull request, using the
+// repository label definitions where they exist.
+func (rs *repoState) labelsOf(number int) []*github.Label {
+	ls := []*github.Label{}
+	for _, name := range rs.issueLabels[number] {
+		if l, ok := rs.labels[name]; ok {
+			ls = append(ls, l)
+		} else {
+			ls = append(ls, &github.Label{Name: github.Ptr(name)})
+		}
+	}
+	return ls
+}
+
+// Seeding
+
+// SetDefaultBranch sets the default branch of owner/repo, which defaults to
+// "main".
+func (s *Server) SetDefaultBranch(owner, repo, branch string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, repo).defaultBranch = branch
+}
+
+// AddIssue adds an issue to owner/repo, assigning it the next number if it
+// has none, and returns it.
+func (s *Server) AddIssue(owner, repo string, issue *github.Issue) *github.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := s.repo(owner, repo)
+	if issue.Number == nil {
+		issue.Number = github.Ptr(rs.nextNumber())
+	}
+	if issue.State == nil {
+		issue.State = github.Ptr("open")
+	}
+	rs.issues[issue.GetNumber()] = issue
+	rs.issueLabels[issue.GetNumber()] = labelNames(issue.Labels)
+	return issue
+}
+
+// AddPullRequest adds a pull request to owner/repo, assigning it the next
+// number and a node ID if it has none, and returns it. Its base repository is
+// filled in if unset, so it can be passed directly to GitHubClient methods.
+func (s *Server) AddPullRequest(owner, repo string, pr *github.PullRequest) *github.PullRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := s.repo(owner, repo)
+	if pr.Number == nil {
+		pr.Number = github.Ptr(rs.nextNumber())
+	}
+	if pr.NodeID == nil {
+		pr.NodeID = github.Ptr(fmt.Sprintf("PR_%s_%s_%d", owner, repo, pr.GetNumber()))
+	}
+	if pr.State == nil {
+		pr.State = github.Ptr("open")
+	}
+	if pr.Base == nil {
+		pr.Base = &github.PullRequestBranch{Ref: github.Ptr(rs.defaultBranch)}
+	}
+	if pr.Base.Repo == nil {
+		pr.Base.Repo = rs.githubRepo()
+	}
+	rs.pulls[pr.GetNumber()] = pr
+	rs.issueLabels[pr.GetNumber()] = labelNames(pr.Labels)
+	return pr
+}
+
+// SetMergeability sets whether the pull request with the given number is
+// mergeable, and its mergeable state, such as "clean", "blocked" or "behind".
+// A nil mergeable means GitHub is still computing it.
+func (s *Server) SetMergeability(owner, repo string, number int, mergeable *bool, state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pr, ok := s.repo(owner, repo).pulls[number]; ok {
+		pr.Mergeable = mergeable
+		pr.MergeableState = github.Ptr(state)
+	}
+}
+
+// AddLabel defines a repository label in owner/repo.
+func (s *Server) AddLabel(owner, repo string, label *github.Label) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, repo).labels[label.GetName()] = label
+}
+
+// AddComment adds a comment to the issue or pull request with the given
+// number, and returns it with its ID set.
+func (s *Server) AddComment(owner, repo string, number int, comment *github.IssueComment) *github.IssueComment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := s.repo(owner, repo)
+	if comment.ID == nil {
+		comment.ID = github.Ptr(s.id())
+	}
+	rs.comments[number] = append(rs.comments[number], comment)
+	return comment
+}
+
+// SetPullRequestFiles sets the files changed by the pull request with the
+// given number, including their patches.
+func (s *Server) SetPullRequestFiles(owner, repo string, number int, files []*github.CommitFile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, repo).pullFiles[number] = files
+}
+
+// SetPullRequestHead moves the head of the pull request with the given number
+// to the commit sha, as a push to its branch does. The head is replaced rather
+// than modified, so copies of the pull request keep their head.
+func (s *Server) SetPullRequestHead(owner, repo string, number int, sha string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pr, ok := s.repo(owner, repo).pulls[number]
+	if !ok {
+		return
+	}
+	head := &github.PullRequestBranch{}
+	if pr.Head != nil {
+		*head = *pr.Head
+	}
+	head.SHA = github.Ptr(sha)
+	pr.Head = head
+}
+
+// SetTeamMembers sets the members of the team with the given slug in org.
+func (s *Server) SetTeamMembers(org, slug string, logins ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	members := make([]*github.User, 0, len(logins))
+	for _, login := range logins {
+		members = append(members, &github.User{Login: github.Ptr(login), ID: github.Ptr(s.id())})
+	}
+	s.teams[org+"/"+slug] = members
+}
+
+// SetFile sets the content of path at ref in owner/repo. Use the default
+// branch name as ref for reads that don't specify one.
+func (s *Server) SetFile(owner, repo, ref, path, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := s.repo(owner, repo)
+	if rs.files[ref] == nil {
+		rs.files[ref] = make(map[string]string)
+	}
+	rs.files[ref][strings.Trim(path, "/")] = content
+}
+
+// SetTreeLimit makes recursive tree listings of owner/repo with more than n
+// entries truncated, as GitHub truncates those of very large repositories.
+func (s *Server) SetTreeLimit(owner, repo string, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, repo).treeLimit = n
+}
+
+// AddCommit adds a commit to owner/repo, keyed by its SHA.
+func (s *Server) AddCommit(owner, repo string, commit *github.RepositoryCommit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, repo).commits[commit.GetSHA()] = commit
+}
+
+// SetComparison sets the result of comparing base...head in owner/repo.
+func (s *Server) SetComparison(owner, repo, base, head string, comparison *github.CommitsComparison) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(owner, repo).comparisons[base+"..."+head] = comparison
+}
+
+// AddRelease adds a release to owner/repo, and returns it with its ID set.
+func (s *Server) AddRelease(owner, repo string, release *github.RepositoryRelease) *github.RepositoryRelease {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if release.ID == nil {
+		release.ID = github.Ptr(s.id())
+	}
+	rs := s.repo(owner, repo)
+	rs.releases = append(rs.releases, release)
+	return release
+}
+
+// AddWorkflowRun adds a workflow run to owner/repo, and returns it with its
+// ID and repository set.
+func (s *Server) AddWorkflowRun(owner, repo string, run *github.WorkflowRun) *github.WorkflowRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := s.repo(owner, repo)
+	if run.ID == nil {
+		run.ID = github.Ptr(s.id())
+	}
+	if run.Repository == nil {
+		run.Repository = rs.githubRepo()
+	}
+	rs.runs[run.GetID()] = run
+	return run
+}
+
+// SetWorkflowRunStatus sets the status of a workflow run, and its conclusion
+// once the status is "completed".
+func (s *Server) SetWorkflowRunStatus(owner, repo string, runID int64, status, conclusion string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.repo(owner, repo).runs[runID]
+	if !ok {
+		s.tb.Fatalf("githubtest: no workflow run %d in %s/%s", runID, owner, repo)
+	}
+	run.Status = github.Ptr(status)
+	run.Conclusion = nil
+	if conclusion != "" {
+		run.Conclusion = github.Ptr(conclusion)
+	}
+}
+
+// WorkflowRuns returns the workflow runs of owner/repo, oldest first,
+// including those started by workflow dispatches.
+func (s *Server) WorkflowRuns(owner, repo string) []*github.WorkflowRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := s.repo(owner, repo)
+	runs := make([]*github.WorkflowRun, 0, len(rs.runs))
+	for _, id := range slices.Sorted(maps.Keys(rs.runs)) {
+		runs = append(runs, rs.runs[id])
+	}
+	return runs
+}
+
+// SetWorkflowRunLogs sets the logs of a workflow run, served as a zip
+// archive containing the given files.
+func (s *Server) SetWorkflowRunLogs(owner, repo string, runID int64, files map[string]string) {
+	data := s.zip(files)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := fmt.Sprintf("logs-%d", runID)
+	s.blobs[key] = data
+	s.repo(owner, repo).runLogs[runID] = key
+}
+
+// AddArtifact adds an artifact to a workflow run, served as a zip archive
+// containing the given files, and returns it.
+func (s *Server) AddArtifact(owner, repo string, runID int64, name string, files map[string]string) *github.Artifact {
+	data := s.zip(files)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := &github.Artifact{
+		ID:          github.Ptr(s.id()),
+		Name:        github.Ptr(name),
+		SizeInBytes: github.Ptr(int64(len(data))),
+		WorkflowRun: &github.ArtifactWorkflowRun{ID: github.Ptr(runID)},
+	}
+	s.blobs[fmt.Sprintf("artifact-%d", a.GetID())] = data
+	rs := s.repo(owner, repo)
+	rs.artifacts[runID] = append(rs.artifacts[runID], a)
+	return a
+}
+
+func (s *Server) zip(files map[string]string) []byte {
+	s.tb.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		if err != nil {
+			s.tb.Fatalf("creating zip entry %s: %v", name, err)
+		}
+		if _, err := io.WriteString(w, files[name]); err != nil {
+			s.tb.Fatalf("writing zip entry %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		s.tb.Fatalf("closing zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// Inspection
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// Labels returns the label names of the issue or pull request with the given
+// number.
+func (s *Server) Labels(owner, repo string, number int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.repo(owner, repo).issueLabels[number])
+}
+
+// Issue returns the issue with the given number, or nil if there is none.
+func (s *Server) Issue(owner, repo string, number int) *github.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := s.repo(owner, repo)
+	issue, ok := rs.issues[number]
+	if !ok {
+		return nil
+	}
+	issue.Labels = rs.labelsOf(number)
+	return issue
+}
+
+// PullRequest returns the pull request with the given number, or nil if there
+// is none.
+func (s *Server) PullRequest(owner, repo string, number int) *github.PullRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := s.repo(owner, repo)
+	pr, ok := rs.pulls[number]
+	if !ok {
+		return nil
+	}
+	pr.Labels = rs.labelsOf(number)
+	return pr
+}
+
+// Comments returns the comments on the issue or pull request with the given
+// number.
+func (s *Server) Comments(owner, repo string, number int) []*github.IssueComment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.repo(owner, repo).comments[number])
+}
+
+// Reviews returns the reviews submitted on the pull request with the given
+// number.
+func (s *Server) Reviews(owner, repo string, number int) []*github.PullRequestReview {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.repo(owner, repo).reviews[number])
+}
+
+// ReviewComments returns the inline comments of the reviews submitted on the
+// pull request with the given number.
+func (s *Server) ReviewComments(owner, repo string, number int) []*github.PullRequestComment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.repo(owner, repo).reviewComms[number])
+}
+
+// CheckRuns returns the check runs created in owner/repo.
+func (s *Server) CheckRuns(owner, repo string) []*github.CheckRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.repo(owner, repo).checkRuns)
+}
+
+// Statuses returns the commit statuses created on sha in owner/repo, oldest
+// first.
+func (s *Server) Statuses(owner, repo, sha string) []*github.RepoStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.repo(owner, repo).statuses[sha])
+}
+
+// Assertions
+
+// AssertLabels reports a test error unless the issue or pull request with
+// the given number has exactly the wanted labels, in any order.
+func (s *Server) AssertLabels(owner, repo string, number int, want ...string) {
+	s.tb.Helper()
+	got := s.Labels(owner, repo, number)
+	slices.Sort(got)
+	want = slices.Sorted(slices.Values(want))
+	if !slices.Equal(got, want) {
+		s.tb.Errorf("labels of %s/%s#%d = %v, want %v", owner, repo, number, got, want)
+	}
+}
+
+// AssertComment reports a test error unless a comment on the issue or pull
+// request with the given number contains substr.
+func (s *Server) AssertComment(owner, repo string, number int, substr string) {
+	s.tb.Helper()
+	for _, c := range s.Comments(owner, repo, number) {
+		if strings.Contains(c.GetBody(), substr) {
+			return
+		}
+	}
+	s.tb.Errorf("no comment on %s/%s#%d contains %q", owner, repo, number, substr)
+}
+
+// AssertCheckRun reports a test error unless the latest check run with the
+// given name in owner/repo has the wanted conclusion, and returns it.
+func (s *Server) AssertCheckRun(owner, repo, name, conclusion string) *github.CheckRun {
+	s.tb.Helper()
+	var latest *github.CheckRun
+	for _, cr := range s.CheckRuns(owner, repo) {
+		if cr.GetName() == name {
+			latest = cr
+		}
+	}
+	switch {
+	case latest == nil:
+		s.tb.Errorf("no check run named %q in %s/%s", name, owner, repo)
+	case latest.GetConclusion() != conclusion:
+		s.tb.Errorf("check run %q conclusion = %q, want %q", name, latest.GetConclusion(), conclusion)
+	}
+	return latest
+}
+
+// AssertStatus reports a test error unless the latest commit status of
+// context on sha in owner/repo has the wanted state, and returns it.
+func (s *Server) AssertStatus(owner, repo, sha, context, state string) *github.RepoStatus {
+	s.tb.Helper()
+	var latest *github.RepoStatus
+	for _, st := range s.Statuses(owner, repo, sha) {
+		if st.GetContext() == context {
+			latest = st
+		}
+	}
+	switch {
+	case latest == nil:
+		s.tb.Errorf("no status with context %q on %s in %s/%s", context, sha, owner, repo)
+	case latest.GetState() != state:
+		s.tb.Errorf("status %q state = %q, want %q", context, latest.GetState(), state)
+	}
+	return latest
+}
+
+// AssertRequestCount reports a test error unless the server received want
+// requests with the given method and path.
+func (s *Server) AssertRequestCount(method, path string, want int) {
+	s.tb.Helper()
+	got := 0
+	for _, r := range s.Requests() {
+		if r.Method == method && r.Path == path {
+			got++
+		}
+	}
+	if got != want {
+		s.tb.Errorf("received %d %s %s requests, want %d", got, method, path, want)
+	}
+}
+
+// Helpers
+
+func labelNames(labels []*github.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.GetName())
+	}
+	return names
+}
+
+// blobSHA returns the git blob object ID of content.
+func blobSHA(content string) string {
+	h := sha1.New() //nolint:gosec // git object IDs are SHA-1
+	fmt.Fprintf(h, "blob %d\x00%s", len(content), content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{
+		"message":           message,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Problems parsing JSON: %v", err))
+		return false
+	}
+	return true
+}
+
+func pathInt(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	v, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return 0, false
+	}
+	return v, true
+}
+
+// paginate returns the requested page of items, setting a Link header with
+// the next page like GitHub does.
+func (s *Server) paginate(w http.ResponseWriter, r *http.Request, n int) (int, int) {
+	q := r.URL.Query()
+	perPage, err := strconv.Atoi(q.Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = 30
+	}
+	page, err := strconv.Atoi(q.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	start := min((page-1)*perPage, n)
+	end := min(start+perPage, n)
+	if end < n {
+		q.Set("page", strconv.Itoa(page+1))
+		next := fmt.Sprintf("%s/api/v3%s?%s", s.srv.URL, r.URL.Path, q.Encode())
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", next))
+	}
+	return start, end
+}
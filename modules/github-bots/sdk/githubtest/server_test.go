@@ -0,0 +1,189 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package githubtest_test
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk"
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	"github.com/google/go-github/v88/github"
+)
+
+func newClient(t *testing.T, srv *githubtest.Server, opts ...github.ClientOptionsFunc) sdk.GitHubClient {
+	t.Helper()
+	return sdk.NewGitHubClient(context.Background(), "acme", "widgets", "test", sdk.WithClient(srv.Client(opts...)))
+}
+
+func TestServer_Labels(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{
+		Labels: []*github.Label{{Name: github.Ptr("size/S")}, {Name: github.Ptr("keep")}},
+	})
+	c := newClient(t, srv)
+
+	if err := c.AddLabel(ctx, pr, "lgtm"); err != nil {
+		t.Fatalf("AddLabel: %v", err)
+	}
+	srv.AssertLabels("acme", "widgets", pr.GetNumber(), "size/S", "keep", "lgtm")
+
+	if err := c.RemoveLabel(ctx, pr, "keep"); err != nil {
+		t.Fatalf("RemoveLabel: %v", err)
+	}
+	srv.AssertLabels("acme", "widgets", pr.GetNumber(), "size/S", "lgtm")
+
+	// pr.Labels is now stale, so have ReconcileLabels list them.
+	ref := sdk.IssueRefFromPullRequest(pr)
+	ref.Labels = nil
+	changes, err := c.ReconcileLabels(ctx, ref, []string{"size/L"}, []string{"size/"},
+		sdk.WithLabelDefinitions(sdk.LabelDefinition{Name: "size/L", Color: "ff0000"}))
+	if err != nil {
+		t.Fatalf("ReconcileLabels: %v", err)
+	}
+	if !changes.Changed() {
+		t.Error("ReconcileLabels reported no changes")
+	}
+	srv.AssertLabels("acme", "widgets", pr.GetNumber(), "size/L", "lgtm")
+}
+
+func TestServer_Comments(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{})
+	c := newClient(t, srv)
+
+	for _, body := range []string{"first", "second"} {
+		if err := c.SetComment(ctx, pr, "my-bot", body); err != nil {
+			t.Fatalf("SetComment: %v", err)
+		}
+	}
+	if got := len(srv.Comments("acme", "widgets", pr.GetNumber())); got != 1 {
+		t.Errorf("got %d comments, want 1", got)
+	}
+	srv.AssertComment("acme", "widgets", pr.GetNumber(), "second")
+}
+
+func TestServer_CheckRuns(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	gh := srv.Client()
+
+	cr, _, err := gh.Checks.CreateCheckRun(ctx, "acme", "widgets", github.CreateCheckRunOptions{
+		Name:    "lint",
+		HeadSHA: "abc123",
+	})
+	if err != nil {
+		t.Fatalf("CreateCheckRun: %v", err)
+	}
+	if _, _, err := gh.Checks.UpdateCheckRun(ctx, "acme", "widgets", cr.GetID(), github.UpdateCheckRunOptions{
+		Conclusion: github.Ptr("success"),
+	}); err != nil {
+		t.Fatalf("UpdateCheckRun: %v", err)
+	}
+	got := srv.AssertCheckRun("acme", "widgets", "lint", "success")
+	if got.GetStatus() != "completed" {
+		t.Errorf("status = %q, want completed", got.GetStatus())
+	}
+
+	res, _, err := gh.Checks.ListCheckRunsForRef(ctx, "acme", "widgets", "abc123", nil)
+	if err != nil {
+		t.Fatalf("ListCheckRunsForRef: %v", err)
+	}
+	if res.GetTotal() != 1 {
+		t.Errorf("total = %d, want 1", res.GetTotal())
+	}
+}
+
+func TestServer_Contents(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.SetFile("acme", "widgets", "main", "config/bot.yaml", "enabled: true\n")
+	srv.SetFile("acme", "widgets", "v1", "config/bot.yaml", "enabled: false\n")
+	c := newClient(t, srv)
+
+	for ref, want := range map[string]string{"": "enabled: true\n", "v1": "enabled: false\n"} {
+		got, err := c.GetFileContent(ctx, "acme", "widgets", "config/bot.yaml", ref)
+		if err != nil {
+			t.Fatalf("GetFileContent(%q): %v", ref, err)
+		}
+		if got != want {
+			t.Errorf("GetFileContent(%q) = %q, want %q", ref, got, want)
+		}
+	}
+
+	files, err := c.ListFiles(ctx, "acme", "widgets", "config", "main")
+	if err != nil {
+		t.Fatalf("ListFiles: %v", err)
+	}
+	if len(files) != 1 || files[0].GetPath() != "config/bot.yaml" {
+		t.Errorf("ListFiles = %v, want [config/bot.yaml]", files)
+	}
+
+	if _, err := c.GetFileContent(ctx, "acme", "widgets", "missing.yaml", "main"); err == nil {
+		t.Error("GetFileContent of a missing file succeeded")
+	}
+}
+
+func TestServer_WorkflowRuns(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	run := srv.AddWorkflowRun("acme", "widgets", &github.WorkflowRun{Name: github.Ptr("ci")})
+	srv.SetWorkflowRunLogs("acme", "widgets", run.GetID(), map[string]string{"build/1_setup.txt": "hello"})
+	srv.AddArtifact("acme", "widgets", run.GetID(), "results", map[string]string{"results.json": "{}"})
+	c := newClient(t, srv)
+
+	logs, err := c.FetchWorkflowRunLogs(ctx, run, nil)
+	if err != nil {
+		t.Fatalf("FetchWorkflowRunLogs: %v", err)
+	}
+	if got := readZipFile(t, logs.File[0]); got != "hello" {
+		t.Errorf("log = %q, want hello", got)
+	}
+
+	artifact, err := c.FetchWorkflowRunArtifact(ctx, run, "results")
+	if err != nil {
+		t.Fatalf("FetchWorkflowRunArtifact: %v", err)
+	}
+	if got := artifact.File[0].Name; got != "results.json" {
+		t.Errorf("artifact file = %q, want results.json", got)
+	}
+}
+
+func TestServer_RateLimitNext(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.SetFile("acme", "widgets", "main", "README.md", "hi")
+	srv.RateLimitNext(1, githubtest.RateLimit{
+		StatusCode: http.StatusTooManyRequests,
+		RetryAfter: time.Second,
+	})
+	c := newClient(t, srv, github.WithHTTPClient(sdk.NewSecondaryRateLimitWaiterClient(nil)))
+
+	if _, err := c.GetFileContent(ctx, "acme", "widgets", "README.md", "main"); err != nil {
+		t.Fatalf("GetFileContent: %v", err)
+	}
+	srv.AssertRequestCount(http.MethodGet, "/repos/acme/widgets/contents/README.md", 2)
+}
+
+func readZipFile(t *testing.T, f *zip.File) string {
+	t.Helper()
+	rc, err := f.Open()
+	if err != nil {
+		t.Fatalf("opening zip entry: %v", err)
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("reading zip entry: %v", err)
+	}
+	return string(b)
+}
@@ -70,6 +70,97 @@ func (r CheckSuiteHandler) EventType() EventType {
 	return CheckSuiteEvent
 }
 
+// MergeGroupHandler handles merge_group events, which merge queues send to
+// request checks on a merge group's head commit.
+type MergeGroupHandler func(ctx context.Context, mge github.MergeGroupEvent) error
+
+func (r MergeGroupHandler) EventType() EventType {
+	return MergeGroupEvent
+}
+
+// WorkflowJobHandler handles workflow_job events, sent as each job of a
+// workflow run is queued, starts and completes.
+type WorkflowJobHandler func(ctx context.Context, wje github.WorkflowJobEvent) error
+
+func (r WorkflowJobHandler) EventType() EventType {
+	return WorkflowJobEvent
+}
+
+type PullRequestReviewHandler func(ctx context.Context, pre github.PullRequestReviewEvent) error
+
+func (r PullRequestReviewHandler) EventType() EventType {
+	return PullRequestReviewEvent
+}
+
+type PullRequestReviewCommentHandler func(ctx context.Context, pre github.PullRequestReviewCommentEvent) error
+
+func (r PullRequestReviewCommentHandler) EventType() EventType {
+	return PullRequestReviewCommentEvent
+}
+
+type ReleaseHandler func(ctx context.Context, re github.ReleaseEvent) error
+
+func (r ReleaseHandler) EventType() EventType {
+	return ReleaseEvent
+}
+
+// CreateHandler handles create events, sent when a branch or tag is created.
+type CreateHandler func(ctx context.Context, ce github.CreateEvent) error
+
+func (r CreateHandler) EventType() EventType {
+	return CreateEvent
+}
+
+// DeleteHandler handles delete events, sent when a branch or tag is deleted.
+type DeleteHandler func(ctx context.Context, de github.DeleteEvent) error
+
+func (r DeleteHandler) EventType() EventType {
+	return DeleteEvent
+}
+
+// StatusHandler handles status events, sent when the commit status of a
+// commit changes.
+type StatusHandler func(ctx context.Context, se github.StatusEvent) error
+
+func (r StatusHandler) EventType() EventType {
+	return StatusEvent
+}
+
+type DeploymentHandler func(ctx context.Context, de github.DeploymentEvent) error
+
+func (r DeploymentHandler) EventType() EventType {
+	return DeploymentEvent
+}
+
+type DeploymentStatusHandler func(ctx context.Context, dse github.DeploymentStatusEvent) error
+
+func (r DeploymentStatusHandler) EventType() EventType {
+	return DeploymentStatusEvent
+}
+
+// InstallationHandler handles installation events, sent when a GitHub App is
+// installed, uninstalled, suspended or has its permissions changed.
+type InstallationHandler func(ctx context.Context, ie github.InstallationEvent) error
+
+func (r InstallationHandler) EventType() EventType {
+	return InstallationEvent
+}
+
+// InstallationRepositoriesHandler handles installation_repositories events,
+// sent when repositories are added to or removed from a GitHub App
+// installation.
+type InstallationRepositoriesHandler func(ctx context.Context, ire github.InstallationRepositoriesEvent) error
+
+func (r InstallationRepositoriesHandler) EventType() EventType {
+	return InstallationRepositoriesEvent
+}
+
+type RepositoryHandler func(ctx context.Context, re github.RepositoryEvent) error
+
+func (r RepositoryHandler) EventType() EventType {
+	return RepositoryEvent
+}
+
 type ProjectsV2ItemHandler func(ctx context.Context, pie ProjectsV2ItemEvent) error
 
 func (r ProjectsV2ItemHandler) EventType() EventType {
@@ -104,14 +195,27 @@ type EventType string
 
 const (
 	// GitHub events (https://github.com/chainguard-dev/terraform-infra-common/tree/main/modules/github-events)
-	PullRequestEvent        EventType = "dev.chainguard.github.pull_request"
-	WorkflowRunEvent        EventType = "dev.chainguard.github.workflow_run"
-	IssuesEvent             EventType = "dev.chainguard.github.issues"
-	IssueCommentEvent       EventType = "dev.chainguard.github.issue_comment"
-	PushEvent               EventType = "dev.chainguard.github.push"
-	CheckRunEvent           EventType = "dev.chainguard.github.check_run"
-	CheckSuiteEvent         EventType = "dev.chainguard.github.check_suite"
-	ProjectsV2ItemEventType EventType = "dev.chainguard.github.projects_v2_item"
+	PullRequestEvent              EventType = "dev.chainguard.github.pull_request"
+	WorkflowRunEvent              EventType = "dev.chainguard.github.workflow_run"
+	IssuesEvent                   EventType = "dev.chainguard.github.issues"
+	IssueCommentEvent             EventType = "dev.chainguard.github.issue_comment"
+	PushEvent                     EventType = "dev.chainguard.github.push"
+	CheckRunEvent                 EventType = "dev.chainguard.github.check_run"
+	CheckSuiteEvent               EventType = "dev.chainguard.github.check_suite"
+	ProjectsV2ItemEventType       EventType = "dev.chainguard.github.projects_v2_item"
+	MergeGroupEvent               EventType = "dev.chainguard.github.merge_group"
+	WorkflowJobEvent              EventType = "dev.chainguard.github.workflow_job"
+	PullRequestReviewEvent        EventType = "dev.chainguard.github.pull_request_review"
+	PullRequestReviewCommentEvent EventType = "dev.chainguard.github.pull_request_review_comment"
+	ReleaseEvent                  EventType = "dev.chainguard.github.release"
+	CreateEvent                   EventType = "dev.chainguard.github.create"
+	DeleteEvent                   EventType = "dev.chainguard.github.delete"
+	StatusEvent                   EventType = "dev.chainguard.github.status"
+	DeploymentEvent               EventType = "dev.chainguard.github.deployment"
+	DeploymentStatusEvent         EventType = "dev.chainguard.github.deployment_status"
+	InstallationEvent             EventType = "dev.chainguard.github.installation"
+	InstallationRepositoriesEvent EventType = "dev.chainguard.github.installation_repositories"
+	RepositoryEvent               EventType = "dev.chainguard.github.repository"
 
 	// LoFo events
 	WorkflowRunArtifactEvent EventType = "dev.chainguard.lofo.workflow_run_artifacts"
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-github/v88/github"
+)
+
+func TestHandlers(t *testing.T) {
+	var got string
+	record := func(s string) error {
+		got = s
+		return nil
+	}
+	bot := NewBot("test",
+		BotWithHandler(WorkflowJobHandler(func(_ context.Context, e github.WorkflowJobEvent) error {
+			return record(e.GetWorkflowJob().GetName())
+		})),
+		BotWithHandler(PullRequestReviewHandler(func(_ context.Context, e github.PullRequestReviewEvent) error {
+			return record(e.GetReview().GetState())
+		})),
+		BotWithHandler(PullRequestReviewCommentHandler(func(_ context.Context, e github.PullRequestReviewCommentEvent) error {
+			return record(e.GetComment().GetBody())
+		})),
+		BotWithHandler(ReleaseHandler(func(_ context.Context, e github.ReleaseEvent) error {
+			return record(e.GetRelease().GetTagName())
+		})),
+		BotWithHandler(CreateHandler(func(_ context.Context, e github.CreateEvent) error {
+			return record(e.GetRef())
+		})),
+		BotWithHandler(DeleteHandler(func(_ context.Context, e github.DeleteEvent) error {
+			return record(e.GetRef())
+		})),
+		BotWithHandler(StatusHandler(func(_ context.Context, e github.StatusEvent) error {
+			return record(e.GetState())
+		})),
+		BotWithHandler(DeploymentHandler(func(_ context.Context, e github.DeploymentEvent) error {
+			return record(e.GetDeployment().GetEnvironment())
+		})),
+		BotWithHandler(DeploymentStatusHandler(func(_ context.Context, e github.DeploymentStatusEvent) error {
+			return record(e.GetDeploymentStatus().GetState())
+		})),
+		BotWithHandler(InstallationHandler(func(_ context.Context, e github.InstallationEvent) error {
+			return record(e.GetInstallation().GetAccount().GetLogin())
+		})),
+		BotWithHandler(InstallationRepositoriesHandler(func(_ context.Context, e github.InstallationRepositoriesEvent) error {
+			return record(e.RepositoriesAdded[0].GetFullName())
+		})),
+		BotWithHandler(RepositoryHandler(func(_ context.Context, e github.RepositoryEvent) error {
+			return record(e.GetAction())
+		})),
+	)
+
+	for _, tt := range []struct {
+		etype EventType
+		body  string
+		want  string
+	}{
+		{WorkflowJobEvent, `{"workflow_job": {"name": "build"}}`, "build"},
+		{PullRequestReviewEvent, `{"review": {"state": "approved"}}`, "approved"},
+		{PullRequestReviewCommentEvent, `{"comment": {"body": "nit"}}`, "nit"},
+		{ReleaseEvent, `{"release": {"tag_name": "v1.0.0"}}`, "v1.0.0"},
+		{CreateEvent, `{"ref": "feature", "ref_type": "branch"}`, "feature"},
+		{DeleteEvent, `{"ref": "v0.1.0", "ref_type": "tag"}`, "v0.1.0"},
+		{StatusEvent, `{"state": "success"}`, "success"},
+		{DeploymentEvent, `{"deployment": {"environment": "prod"}}`, "prod"},
+		{DeploymentStatusEvent, `{"deployment_status": {"state": "in_progress"}}`, "in_progress"},
+		{InstallationEvent, `{"installation": {"account": {"login": "acme"}}}`, "acme"},
+		{InstallationRepositoriesEvent, `{"repositories_added": [{"full_name": "acme/widgets"}]}`, "acme/widgets"},
+		{RepositoryEvent, `{"action": "archived"}`, "archived"},
+	} {
+		t.Run(string(tt.etype), func(t *testing.T) {
+			got = ""
+			event := cloudevents.NewEvent()
+			event.SetID("delivery")
+			event.SetType(string(tt.etype))
+			event.SetSource("github.com")
+			if err := event.SetData(cloudevents.ApplicationJSON, map[string]json.RawMessage{"Body": json.RawMessage(tt.body)}); err != nil {
+				t.Fatalf("SetData: %v", err)
+			}
+			if err := bot.handleEvent(context.Background(), event); err != nil {
+				t.Fatalf("handleEvent: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("handler got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/chainguard-dev/clog"
+	"github.com/chainguard-dev/terraform-infra-common/pkg/httpmetrics"
+	"github.com/google/go-github/v88/github"
+)
+
+// InstallationClientFactory hands out GitHubClients for any installation of a
+// single GitHub App. Installations are resolved through the Apps API by owner
+// or repository, and one *ghinstallation.Transport is cached per installation
+// so installation tokens are shared and refreshed across clients.
+//
+// Every request made through the factory, including the Apps API lookups and
+// installation token refreshes, carries httpmetrics.WithGitHubAppID and
+// httpmetrics.WithGitHubInstallationID on its context so rate limit metrics
+// are labelled without caller effort.
+type InstallationClientFactory struct {
+	appID      int64
+	apps       *ghinstallation.AppsTransport
+	appsClient *github.Client
+	clientOpts []github.ClientOptionsFunc
+
+	mu         sync.Mutex
+	owners     map[string]int64
+	repos      map[string]int64
+	transports map[int64]*ghinstallation.Transport
+}
+
+// InstallationFactoryOption configures an InstallationClientFactory.
+type InstallationFactoryOption func(*installationFactoryConfig)
+
+type installationFactoryConfig struct {
+	base    http.RoundTripper
+	baseURL string
+}
+
+// WithFactoryTransport sets the base transport used for all requests made by
+// the factory and its clients. It defaults to http.DefaultTransport.
+func WithFactoryTransport(base http.RoundTripper) InstallationFactoryOption {
+	return func(c *installationFactoryConfig) {
+		c.base = base
+	}
+}
+
+// WithFactoryBaseURL points the factory and its clients at a GitHub
+// Enterprise Server instance (or a test server) instead of api.github.com.
+// The URL is interpreted as by github.WithEnterpriseURLs.
+func WithFactoryBaseURL(baseURL string) InstallationFactoryOption {
+	return func(c *installationFactoryConfig) {
+		c.baseURL = baseURL
+	}
+}
+
+// NewInstallationClientFactory returns a factory for the GitHub App with the
+// given ID, authenticating to the Apps API with the PEM-encoded privateKey.
+func NewInstallationClientFactory(appID int64, privateKey []byte, opts ...InstallationFactoryOption) (*InstallationClientFactory, error) {
+	cfg := &installationFactoryConfig{base: http.DefaultTransport}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	apps, err := ghinstallation.NewAppsTransport(cfg.base, appID, privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("creating apps transport: %w", err)
+	}
+
+	var clientOpts []github.ClientOptionsFunc
+	if cfg.baseURL != "" {
+		clientOpts = append(clientOpts, github.WithEnterpriseURLs(cfg.baseURL, cfg.baseURL))
+	}
+	appsClient, err := newAppClient(apps, appID, 0, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	// ghinstallation expects the API root without a trailing slash.
+	apps.BaseURL = strings.TrimSuffix(appsClient.BaseURL(), "/")
+
+	return &InstallationClientFactory{
+		appID:      appID,
+		apps:       apps,
+		appsClient: appsClient,
+		clientOpts: clientOpts,
+		owners:     make(map[string]int64),
+		repos:      make(map[string]int64),
+		transports: make(map[int64]*ghinstallation.Transport),
+	}, nil
+}
+
+// ForOwner returns a GitHubClient for the installation of the app on the
+// given organization or user account. The client is not scoped to a
+// repository, so repository helpers such as CloneRepo are unavailable.
+//
+// Clients share cached installation tokens, so they must not be closed.
+func (f *InstallationClientFactory) ForOwner(ctx context.Context, owner string, opts ...GitHubClientOption) (GitHubClient, error) {
+	return f.client(ctx, owner, "", opts...)
+}
+
+// ForRepo returns a GitHubClient for the installation of the app that has
+// access to owner/repo.
+//
+// Clients share cached installation tokens, so they must not be closed.
+func (f *InstallationClientFactory) ForRepo(ctx context.Context, owner, repo string, opts ...GitHubClientOption) (GitHubClient, error) {
+	return f.client(ctx, owner, repo, opts...)
+}
+
+func (f *InstallationClientFactory) client(ctx context.Context, owner, repo string, opts ...GitHubClientOption) (GitHubClient, error) {
+	tr, err := f.Transport(ctx, owner, repo)
+	if err != nil {
+		return GitHubClient{}, err
+	}
+
+	inner, err := newAppClient(tr, f.appID, tr.InstallationID(), f.clientOpts...)
+	if err != nil {
+		return GitHubClient{}, err
+	}
+
+	return NewInstallationClient(ctx, owner, repo, tr, append([]GitHubClientOption{WithClient(inner)}, opts...)...), nil
+}
+
+// Transport returns the cached installation transport for owner, or for
+// owner/repo when repo is non-empty, creating it on first use. The transport
+// refreshes its installation token as it nears expiry.
+func (f *InstallationClientFactory) Transport(ctx context.Context, owner, repo string) (*ghinstallation.Transport, error) {
+	id, err := f.InstallationID(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if tr, ok := f.transports[id]; ok {
+		return tr, nil
+	}
+
+	// Token refreshes happen inline in RoundTrip with the request's context,
+	// so they inherit the labels attached by the client's transport.
+	tr := ghinstallation.NewFromAppsTransport(f.apps, id)
+	f.transports[id] = tr
+	return tr, nil
+}
+
+// InstallationID resolves the ID of the app's installation for owner, or for
+// owner/repo when repo is non-empty. Results are cached for the lifetime of
+// the factory.
+func (f *InstallationClientFactory) InstallationID(ctx context.Context, owner, repo string) (int64, error) {
+	if owner == "" {
+		return 0, errors.New("owner is required to resolve an installation")
+	}
+
+	cache, key := f.owners, owner
+	if repo != "" {
+		cache, key = f.repos, owner+"/"+repo
+	}
+
+	f.mu.Lock()
+	id, ok := cache[key]
+	f.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	clog.DebugContextf(ctx, "resolving installation of app %d for %s", f.appID, key)
+	inst, err := f.findInstallation(ctx, owner, repo)
+	if err != nil {
+		return 0, err
+	}
+
+	f.mu.Lock()
+	cache[key] = inst.GetID()
+	f.mu.Unlock()
+	return inst.GetID(), nil
+}
+
+func (f *InstallationClientFactory) findInstallation(ctx context.Context, owner, repo string) (*github.Installation, error) {
+	if repo != "" {
+		inst, resp, err := f.appsClient.Apps.GetRepositoryInstallation(ctx, owner, repo)
+		if err := validateResponse(ctx, err, resp, fmt.Sprintf("get installation for %s/%s", owner, repo)); err != nil {
+			return nil, err
+		}
+		return inst, nil
+	}
+
+	// Owners may be organizations or users, and the Apps API has a separate
+	// endpoint for each.
+	inst, resp, err := f.appsClient.Apps.GetOrganizationInstallation(ctx, owner)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		inst, resp, err = f.appsClient.Apps.GetUserInstallation(ctx, owner)
+	}
+	if err := validateResponse(ctx, err, resp, fmt.Sprintf("get installation for %s", owner)); err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// newAppClient is NewClientWithOptions with the app and installation IDs
+// attached to each request's context. The IDs are attached outside of the
+// httpmetrics transport, which reads them. Setting the Transport of
+// Client() after the fact has no effect, as it returns a copy.
+func newAppClient(base http.RoundTripper, appID, installationID int64, opts ...github.ClientOptionsFunc) (*github.Client, error) {
+	tr := &appContextTransport{
+		base:           httpmetrics.WrapTransport(base),
+		appID:          appID,
+		installationID: installationID,
+	}
+	client, err := github.NewClient(append([]github.ClientOptionsFunc{github.WithTransport(tr)}, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("creating github client: %w", err)
+	}
+	return client, nil
+}
+
+// appContextTransport attaches the GitHub App and installation IDs to each
+// request's context so httpmetrics can label rate limit metrics with them.
+type appContextTransport struct {
+	base           http.RoundTripper
+	appID          int64
+	installationID int64
+}
+
+func (t *appContextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := httpmetrics.WithGitHubAppID(req.Context(), t.appID)
+	if t.installationID != 0 {
+		ctx = httpmetrics.WithGitHubInstallationID(ctx, t.installationID)
+	}
+	return t.base.RoundTrip(req.WithContext(ctx))
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chainguard-dev/terraform-infra-common/pkg/httpmetrics"
+)
+
+func testAppKey(t *testing.T) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+}
+
+func TestInstallationClientFactory(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls[r.Method+" "+r.URL.Path]++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/orgs/acme/installation":
+			w.Write([]byte(`{"id": 42}`))
+		case "/api/v3/orgs/octocat/installation":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		case "/api/v3/users/octocat/installation":
+			w.Write([]byte(`{"id": 7}`))
+		case "/api/v3/repos/acme/widgets/installation":
+			w.Write([]byte(`{"id": 42}`))
+		case "/api/v3/app/installations/42/access_tokens":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"token": "tok-42", "expires_at": "` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}`))
+		case "/api/v3/repos/acme/widgets":
+			if got := r.Header.Get("Authorization"); got != "token tok-42" {
+				t.Errorf("Authorization = %q, want installation token", got)
+			}
+			w.Write([]byte(`{"name": "widgets"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	f, err := NewInstallationClientFactory(1234, testAppKey(t), WithFactoryBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewInstallationClientFactory: %v", err)
+	}
+	ctx := context.Background()
+
+	for range 3 {
+		c, err := f.ForRepo(ctx, "acme", "widgets")
+		if err != nil {
+			t.Fatalf("ForRepo: %v", err)
+		}
+		if _, _, err := c.Client().Repositories.Get(ctx, "acme", "widgets"); err != nil {
+			t.Fatalf("Repositories.Get: %v", err)
+		}
+	}
+
+	orgID, err := f.InstallationID(ctx, "acme", "")
+	if err != nil {
+		t.Fatalf("InstallationID(acme): %v", err)
+	}
+	if orgID != 42 {
+		t.Errorf("InstallationID(acme) = %d, want 42", orgID)
+	}
+	userID, err := f.InstallationID(ctx, "octocat", "")
+	if err != nil {
+		t.Fatalf("InstallationID(octocat): %v", err)
+	}
+	if userID != 7 {
+		t.Errorf("InstallationID(octocat) = %d, want 7", userID)
+	}
+
+	repoTr, err := f.Transport(ctx, "acme", "widgets")
+	if err != nil {
+		t.Fatalf("Transport(acme/widgets): %v", err)
+	}
+	orgTr, err := f.Transport(ctx, "acme", "")
+	if err != nil {
+		t.Fatalf("Transport(acme): %v", err)
+	}
+	if repoTr != orgTr {
+		t.Error("expected repo and org lookups for the same installation to share a transport")
+	}
+
+	for path, want := range map[string]int{
+		"GET /api/v3/repos/acme/widgets/installation":     1,
+		"POST /api/v3/app/installations/42/access_tokens": 1,
+		"GET /api/v3/repos/acme/widgets":                  3,
+	} {
+		if got := calls[path]; got != want {
+			t.Errorf("calls[%q] = %d, want %d", path, got, want)
+		}
+	}
+}
+
+func TestInstallationClientFactory_NotInstalled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Not Found"}`))
+	}))
+	defer srv.Close()
+
+	f, err := NewInstallationClientFactory(1234, testAppKey(t), WithFactoryBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewInstallationClientFactory: %v", err)
+	}
+	if _, err := f.ForRepo(context.Background(), "acme", "missing"); err == nil {
+		t.Error("ForRepo: expected error for repository without an installation")
+	}
+}
+
+// labelTransport records the app and installation IDs on the context of each
+// request it forwards, by method and path.
+type labelTransport struct {
+	mu     sync.Mutex
+	labels map[string][2]string
+}
+
+func (lt *labelTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	appID, _ := httpmetrics.GitHubAppIDFromContext(req.Context())
+	installationID, _ := httpmetrics.GitHubInstallationIDFromContext(req.Context())
+	lt.mu.Lock()
+	lt.labels[req.Method+" "+req.URL.Path] = [2]string{appID, installationID}
+	lt.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestInstallationClientFactory_Labels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/repos/acme/widgets/installation":
+			w.Write([]byte(`{"id": 42}`))
+		case "/api/v3/app/installations/42/access_tokens":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"token": "tok-42", "expires_at": "` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	lt := &labelTransport{labels: map[string][2]string{}}
+	f, err := NewInstallationClientFactory(1234, testAppKey(t), WithFactoryBaseURL(srv.URL), WithFactoryTransport(lt))
+	if err != nil {
+		t.Fatalf("NewInstallationClientFactory: %v", err)
+	}
+	ctx := context.Background()
+	c, err := f.ForRepo(ctx, "acme", "widgets")
+	if err != nil {
+		t.Fatalf("ForRepo: %v", err)
+	}
+	if _, _, err := c.Client().Repositories.Get(ctx, "acme", "widgets"); err != nil {
+		t.Fatalf("Repositories.Get: %v", err)
+	}
+
+	for path, want := range map[string][2]string{
+		"GET /api/v3/repos/acme/widgets/installation":     {"1234", ""},
+		"POST /api/v3/app/installations/42/access_tokens": {"1234", "42"},
+		"GET /api/v3/repos/acme/widgets":                  {"1234", "42"},
+	} {
+		got, ok := lt.labels[path]
+		if !ok {
+			t.Errorf("no request to %s", path)
+			continue
+		}
+		if got != want {
+			t.Errorf("labels of %s = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/google/go-github/v88/github"
+)
+
+// IssueRef identifies an issue or pull request. Pull requests share the
+// issues API for labels and comments, so either can be referenced.
+type IssueRef struct {
+	Owner, Repo string
+	Number      int
+
+	// Labels are the current labels of the issue, if already known from the
+	// event payload. When nil, the current labels are fetched from GitHub.
+	Labels []*github.Label
+}
+
+// IssueRefFromPullRequest returns an IssueRef for the given pull request,
+// including its current labels.
+func IssueRefFromPullRequest(pr *github.PullRequest) IssueRef {
+	return IssueRef{
+		Owner:  pr.GetBase().GetRepo().GetOwner().GetLogin(),
+		Repo:   pr.GetBase().GetRepo().GetName(),
+		Number: pr.GetNumber(),
+		Labels: pr.Labels,
+	}
+}
+
+// IssueRefFromIssue returns an IssueRef for the given issue in owner/repo,
+// including its current labels.
+func IssueRefFromIssue(owner, repo string, issue *github.Issue) IssueRef {
+	return IssueRef{
+		Owner:  owner,
+		Repo:   repo,
+		Number: issue.GetNumber(),
+		Labels: issue.Labels,
+	}
+}
+
+func (r IssueRef) String() string {
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.Number)
+}
+
+// LabelDefinition describes a repository label to create if it is missing.
+type LabelDefinition struct {
+	Name string
+	// Color is a hex color code without the leading "#".
+	Color       string
+	Description string
+}
+
+// LabelChanges reports what ReconcileLabels changed.
+type LabelChanges struct {
+	Added   []string
+	Removed []string
+	// Created lists repository labels that were created from a
+	// LabelDefinition before being added.
+	Created []string
+}
+
+// Changed reports whether any labels were added or removed.
+func (c LabelChanges) Changed() bool {
+	return len(c.Added) > 0 || len(c.Removed) > 0
+}
+
+// ReconcileLabelsOption configures ReconcileLabels.
+type ReconcileLabelsOption func(*reconcileLabelsConfig)
+
+type reconcileLabelsConfig struct {
+	definitions map[string]LabelDefinition
+}
+
+// WithLabelDefinitions creates any of the given labels that are about to be
+// added but do not yet exist in the repository, with their color and
+// description. Labels added without a definition are created by GitHub with a
+// default color.
+func WithLabelDefinitions(defs ...LabelDefinition) ReconcileLabelsOption {
+	return func(c *reconcileLabelsConfig) {
+		for _, d := range defs {
+			c.definitions[d.Name] = d
+		}
+	}
+}
+
+// ReconcileLabels makes the managed labels of the referenced issue or pull
+// request exactly the desired set. A label is managed if it starts with one
+// of managedPrefixes or is itself desired; unmanaged labels are left alone.
+//
+// For example, desired {"size/L"} with managed prefix "size/" removes any
+// other "size/*" label and adds "size/L" if it is missing.
+//
+// ReconcileLabels makes as few API calls as possible. When more than one
+// call would be needed to apply removals, it replaces the issue's labels in a
+// single call instead, which can race with concurrent label changes made
+// after the current labels were read.
+func (c GitHubClient) ReconcileLabels(ctx context.Context, ref IssueRef, desired, managedPrefixes []string, opts ...ReconcileLabelsOption) (LabelChanges, error) {
+	log := clog.FromContext(ctx)
+
+	ctx, release, err := c.acquire(ctx, ref.Owner, ref.Repo, ref.Number, WriteOperation)
+	if err != nil {
+		return LabelChanges{}, err
+	}
+	defer release()
+
+	cfg := &reconcileLabelsConfig{definitions: make(map[string]LabelDefinition)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	current := ref.Labels
+	if current == nil {
+		var err error
+		if current, err = c.listIssueLabels(ctx, ref); err != nil {
+			return LabelChanges{}, err
+		}
+	}
+	currentNames := make([]string, 0, len(current))
+	for _, l := range current {
+		currentNames = append(currentNames, l.GetName())
+	}
+
+	var changes LabelChanges
+	for _, name := range desired {
+		if !slices.Contains(currentNames, name) && !slices.Contains(changes.Added, name) {
+			changes.Added = append(changes.Added, name)
+		}
+	}
+	var keep []string
+	for _, name := range currentNames {
+		managed := slices.ContainsFunc(managedPrefixes, func(p string) bool { return strings.HasPrefix(name, p) })
+		if managed && !slices.Contains(desired, name) {
+			changes.Removed = append(changes.Removed, name)
+		} else {
+			keep = append(keep, name)
+		}
+	}
+
+	if !changes.Changed() {
+		log.Debugf("labels of %s are up to date", ref)
+		return changes, nil
+	}
+
+	for _, name := range changes.Added {
+		def, ok := cfg.definitions[name]
+		if !ok {
+			continue
+		}
+		created, err := c.ensureLabel(ctx, ref.Owner, ref.Repo, def)
+		if err != nil {
+			return LabelChanges{}, err
+		}
+		if created {
+			changes.Created = append(changes.Created, name)
+		}
+	}
+
+	log.Infof("Reconciling labels of %s: adding %v, removing %v", ref, changes.Added, changes.Removed)
+	switch {
+	case len(changes.Removed) == 0:
+		_, resp, err := c.inner.Issues.AddLabelsToIssue(ctx, ref.Owner, ref.Repo, ref.Number, changes.Added)
+		if err := validateResponse(ctx, err, resp, fmt.Sprintf("add labels to %s", ref)); err != nil {
+			return LabelChanges{}, err
+		}
+
+	case len(changes.Removed) == 1 && len(changes.Added) == 0:
+		resp, err := c.inner.Issues.RemoveLabelForIssue(ctx, ref.Owner, ref.Repo, ref.Number, changes.Removed[0])
+		// The label is already gone, which is what we wanted.
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			break
+		}
+		if err := validateResponse(ctx, err, resp, fmt.Sprintf("remove label from %s", ref)); err != nil {
+			return LabelChanges{}, err
+		}
+
+	default:
+		_, resp, err := c.inner.Issues.ReplaceLabelsForIssue(ctx, ref.Owner, ref.Repo, ref.Number, append(keep, changes.Added...))
+		if err := validateResponse(ctx, err, resp, fmt.Sprintf("replace labels of %s", ref)); err != nil {
+			return LabelChanges{}, err
+		}
+	}
+
+	return changes, nil
+}
+
+func (c GitHubClient) listIssueLabels(ctx context.Context, ref IssueRef) ([]*github.Label, error) {
+	var all []*github.Label
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		labels, resp, err := c.inner.Issues.ListLabelsByIssue(ctx, ref.Owner, ref.Repo, ref.Number, opt)
+		if err := validateResponse(ctx, err, resp, fmt.Sprintf("list labels of %s", ref)); err != nil {
+			return nil, err
+		}
+		all = append(all, labels...)
+		if resp.NextPage == 0 {
+			return all, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// ensureLabel creates the repository label described by def, and reports
+// whether it was created. A label that already exists is left unchanged.
+func (c GitHubClient) ensureLabel(ctx context.Context, owner, repo string, def LabelDefinition) (bool, error) {
+	label := &github.Label{Name: github.Ptr(def.Name)}
+	if def.Color != "" {
+		label.Color = github.Ptr(strings.TrimPrefix(def.Color, "#"))
+	}
+	if def.Description != "" {
+		label.Description = github.Ptr(def.Description)
+	}
+
+	_, resp, err := c.inner.Issues.CreateLabel(ctx, owner, repo, label)
+	var ghErr *github.ErrorResponse
+	if errors.As(err, &ghErr) && slices.ContainsFunc(ghErr.Errors, func(e github.Error) bool { return e.Code == "already_exists" }) {
+		return false, nil
+	}
+	if err != nil {
+		return false, validateResponse(ctx, err, resp, fmt.Sprintf("create label %q", def.Name))
+	}
+	return true, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/go-github/v88/github"
+)
+
+type recordedRequest struct {
+	Method, Path, Body string
+}
+
+func TestReconcileLabels(t *testing.T) {
+	labels := func(names ...string) []*github.Label {
+		ls := []*github.Label{}
+		for _, n := range names {
+			ls = append(ls, &github.Label{Name: github.Ptr(n)})
+		}
+		return ls
+	}
+
+	tests := []struct {
+		name     string
+		current  []*github.Label
+		desired  []string
+		prefixes []string
+		defs     []LabelDefinition
+		want     LabelChanges
+		wantReqs []recordedRequest
+	}{{
+		name:     "up to date",
+		current:  labels("size/L", "bug"),
+		desired:  []string{"size/L"},
+		prefixes: []string{"size/"},
+		want:     LabelChanges{},
+	}, {
+		name:     "add only",
+		current:  labels("bug"),
+		desired:  []string{"size/L"},
+		prefixes: []string{"size/"},
+		want:     LabelChanges{Added: []string{"size/L"}},
+		wantReqs: []recordedRequest{
+			{"POST", "/api/v3/repos/acme/widgets/issues/7/labels", `["size/L"]`},
+		},
+	}, {
+		name:     "remove only",
+		current:  labels("bug", "size/S"),
+		desired:  nil,
+		prefixes: []string{"size/"},
+		want:     LabelChanges{Removed: []string{"size/S"}},
+		wantReqs: []recordedRequest{
+			{"DELETE", "/api/v3/repos/acme/widgets/issues/7/labels/size/S", ""},
+		},
+	}, {
+		name:     "add and remove in one call",
+		current:  labels("bug", "size/S"),
+		desired:  []string{"size/L"},
+		prefixes: []string{"size/"},
+		want:     LabelChanges{Added: []string{"size/L"}, Removed: []string{"size/S"}},
+		wantReqs: []recordedRequest{
+			{"PUT", "/api/v3/repos/acme/widgets/issues/7/labels", `["bug","size/L"]`},
+		},
+	}, {
+		name:     "fetch current labels",
+		current:  nil,
+		desired:  []string{"size/L"},
+		prefixes: []string{"size/"},
+		want:     LabelChanges{Removed: []string{"size/M"}, Added: []string{"size/L"}},
+		wantReqs: []recordedRequest{
+			{"GET", "/api/v3/repos/acme/widgets/issues/7/labels", ""},
+			{"PUT", "/api/v3/repos/acme/widgets/issues/7/labels", `["size/L"]`},
+		},
+	}, {
+		name:     "create missing labels",
+		current:  labels(),
+		desired:  []string{"size/L", "size/XL"},
+		prefixes: []string{"size/"},
+		defs: []LabelDefinition{
+			{Name: "size/L", Color: "#ff0000", Description: "Large change"},
+			{Name: "size/XL", Color: "990000"},
+		},
+		want: LabelChanges{Added: []string{"size/L", "size/XL"}, Created: []string{"size/L"}},
+		wantReqs: []recordedRequest{
+			{"POST", "/api/v3/repos/acme/widgets/labels", `{"name":"size/L","color":"ff0000","description":"Large change"}`},
+			{"POST", "/api/v3/repos/acme/widgets/labels", `{"name":"size/XL","color":"990000"}`},
+			{"POST", "/api/v3/repos/acme/widgets/issues/7/labels", `["size/L","size/XL"]`},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []recordedRequest
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				got = append(got, recordedRequest{r.Method, r.URL.Path, strings.TrimSuffix(string(body), "\n")})
+
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodGet:
+					w.Write([]byte(`[{"name": "size/M"}]`))
+				case r.URL.Path == "/api/v3/repos/acme/widgets/labels" && r.Method == http.MethodPost:
+					var l github.Label
+					json.Unmarshal(body, &l)
+					if l.GetName() == "size/XL" {
+						w.WriteHeader(http.StatusUnprocessableEntity)
+						w.Write([]byte(`{"message": "Validation Failed", "errors": [{"resource": "Label", "code": "already_exists", "field": "name"}]}`))
+						return
+					}
+					w.WriteHeader(http.StatusCreated)
+					w.Write(body)
+				default:
+					w.Write([]byte(`[]`))
+				}
+			}))
+			defer srv.Close()
+
+			inner, err := github.NewClient(github.WithEnterpriseURLs(srv.URL, srv.URL))
+			if err != nil {
+				t.Fatalf("creating client: %v", err)
+			}
+			c := NewGitHubClient(context.Background(), "acme", "widgets", "test", WithClient(inner))
+
+			ref := IssueRef{Owner: "acme", Repo: "widgets", Number: 7, Labels: tt.current}
+			changes, err := c.ReconcileLabels(context.Background(), ref, tt.desired, tt.prefixes, WithLabelDefinitions(tt.defs...))
+			if err != nil {
+				t.Fatalf("ReconcileLabels: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, changes, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("ReconcileLabels() changes mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantReqs, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("ReconcileLabels() requests mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/google/go-github/v88/github"
+)
+
+// MergeMethod is how a pull request is merged.
+type MergeMethod string
+
+const (
+	MergeMethodMerge  MergeMethod = "MERGE"
+	MergeMethodSquash MergeMethod = "SQUASH"
+	MergeMethodRebase MergeMethod = "REBASE"
+)
+
+// MergeableState is GitHub's summary of whether a pull request can be merged.
+type MergeableState string
+
+const (
+	// MergeableStateClean can be merged.
+	MergeableStateClean MergeableState = "clean"
+	// MergeableStateUnstable can be merged, but has failing checks that are
+	// not required.
+	MergeableStateUnstable MergeableState = "unstable"
+	// MergeableStateHasHooks can be merged, and has passing commit status
+	// hooks.
+	MergeableStateHasHooks MergeableState = "has_hooks"
+	// MergeableStateBlocked is blocked by branch protection, such as missing
+	// approvals or required checks.
+	MergeableStateBlocked MergeableState = "blocked"
+	// MergeableStateBehind must be updated from its base branch before it can
+	// be merged. See GitHubClient.UpdateBranch.
+	MergeableStateBehind MergeableState = "behind"
+	// MergeableStateDirty has merge conflicts.
+	MergeableStateDirty MergeableState = "dirty"
+	// MergeableStateDraft is a draft.
+	MergeableStateDraft MergeableState = "draft"
+	// MergeableStateUnknown is still being computed.
+	MergeableStateUnknown MergeableState = "unknown"
+)
+
+// BranchProtectionError is returned by the merge and approval helpers when
+// GitHub refuses an operation because of the state of the pull request or the
+// protection rules of its base branch, for example enabling auto-merge on a
+// pull request that can already be merged, approving one's own pull request,
+// or updating a branch that conflicts with its base. Retrying doesn't help
+// until the pull request or the rules change.
+type BranchProtectionError struct {
+	// Action is the refused operation, such as "enable auto-merge of pull
+	// request 3".
+	Action string
+	// Message is GitHub's reason for refusing it.
+	Message string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *BranchProtectionError) Error() string {
+	return fmt.Sprintf("failed to %s: %s", e.Action, e.Message)
+}
+
+func (e *BranchProtectionError) Unwrap() error {
+	return e.Err
+}
+
+// asBranchProtectionError returns err as a BranchProtectionError if GitHub
+// refused the action with 405 Method Not Allowed, 409 Conflict or 422
+// Unprocessable Entity, which is how it reports protection rules and pull
+// request states that forbid it. Other errors are returned unchanged.
+func asBranchProtectionError(action string, err error) error {
+	var ghErr *github.ErrorResponse
+	if !errors.As(err, &ghErr) || ghErr.Response == nil {
+		return err
+	}
+	switch ghErr.Response.StatusCode {
+	case http.StatusMethodNotAllowed, http.StatusConflict, http.StatusUnprocessableEntity:
+		return &BranchProtectionError{Action: action, Message: ghErr.Message, Err: err}
+	}
+	return err
+}
+
+// graphQLError is an error of a GraphQL response.
+type graphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// graphQL sends the GraphQL query or mutation with the given variables, and
+// decodes the data of the response into data, if not nil. Errors GitHub
+// reports as UNPROCESSABLE are returned as a BranchProtectionError.
+func (c GitHubClient) graphQL(ctx context.Context, action, query string, variables map[string]any, data any) error {
+	// GitHub Enterprise Server serves GraphQL at /api/graphql rather than
+	// under the REST API root, /api/v3/.
+	endpoint := "graphql"
+	if base := c.inner.BaseURL(); strings.HasSuffix(base, "/api/v3/") {
+		endpoint = strings.TrimSuffix(base, "v3/") + "graphql"
+	}
+	req, err := c.inner.NewRequest(ctx, http.MethodPost, endpoint, map[string]any{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w", action, err)
+	}
+
+	var body struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	resp, err := c.inner.Do(req, &body)
+	if err := validateResponse(ctx, err, resp, action); err != nil {
+		return err
+	}
+	if len(body.Errors) > 0 {
+		msgs := make([]string, 0, len(body.Errors))
+		for _, e := range body.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		msg := strings.Join(msgs, "; ")
+		if body.Errors[0].Type == "UNPROCESSABLE" {
+			return &BranchProtectionError{Action: action, Message: msg}
+		}
+		return fmt.Errorf("failed to %s: %s", action, msg)
+	}
+	if data == nil || len(body.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body.Data, data); err != nil {
+		return fmt.Errorf("failed to %s: decoding response: %w", action, err)
+	}
+	return nil
+}
+
+const enableAutoMergeMutation = `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) {
+    clientMutationId
+  }
+}`
+
+const disableAutoMergeMutation = `mutation($id: ID!) {
+  disablePullRequestAutoMerge(input: {pullRequestId: $id}) {
+    clientMutationId
+  }
+}`
+
+// EnableAutoMerge enables auto-merge of the pull request with the given
+// method, so GitHub merges it once its branch protection requirements are
+// met. The repository must allow auto-merge, and GitHub refuses it with a
+// BranchProtectionError for pull requests that can already be merged.
+func (c GitHubClient) EnableAutoMerge(ctx context.Context, pr *github.PullRequest, method MergeMethod) error {
+	owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+	ctx, release, err := c.acquire(ctx, owner, repo, pr.GetNumber(), WriteOperation)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := c.graphQL(ctx, fmt.Sprintf("enable auto-merge of pull request %d", pr.GetNumber()), enableAutoMergeMutation, map[string]any{
+		"id":     pr.GetNodeID(),
+		"method": method,
+	}, nil); err != nil {
+		return err
+	}
+	clog.FromContext(ctx).Infof("enabled %s auto-merge of pull request %d", strings.ToLower(string(method)), pr.GetNumber())
+	return nil
+}
+
+// DisableAutoMerge disables auto-merge of the pull request.
+func (c GitHubClient) DisableAutoMerge(ctx context.Context, pr *github.PullRequest) error {
+	owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+	ctx, release, err := c.acquire(ctx, owner, repo, pr.GetNumber(), WriteOperation)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return c.graphQL(ctx, fmt.Sprintf("disable auto-merge of pull request %d", pr.GetNumber()), disableAutoMergeMutation, map[string]any{
+		"id": pr.GetNodeID(),
+	}, nil)
+}
+
+// Approve submits an approving review of the pull request, carrying the bot's
+// marker like SubmitReview's reviews. GitHub refuses approvals of one's own
+// pull requests with a BranchProtectionError.
+func (c GitHubClient) Approve(ctx context.Context, pr *github.PullRequest, botName, body string) (*github.PullRequestReview, error) {
+	r, err := c.SubmitReview(ctx, pr, botName, Review{Event: ReviewEventApprove, Body: body})
+	if err != nil {
+		return nil, asBranchProtectionError(fmt.Sprintf("approve pull request %d", pr.GetNumber()), err)
+	}
+	return r, nil
+}
+
+// DismissApprovals dismisses the bot's approvals of the pull request, as
+// submitted with Approve, with the given message, and returns how many it
+// dismissed. Other reviewers' approvals are left alone.
+func (c GitHubClient) DismissApprovals(ctx context.Context, pr *github.PullRequest, botName, message string) (int, error) {
+	owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+	marker := fmt.Sprintf("<!-- bot:%s -->", botName)
+
+	var approvals []*github.PullRequestReview
+	for r, err := range c.AllPullRequestReviews(ctx, pr) {
+		if err != nil {
+			return 0, err
+		}
+		if r.GetState() == "APPROVED" && strings.Contains(r.GetBody(), marker) {
+			approvals = append(approvals, r)
+		}
+	}
+	if len(approvals) == 0 {
+		return 0, nil
+	}
+
+	ctx, release, err := c.acquire(ctx, owner, repo, pr.GetNumber(), WriteOperation)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	for i, r := range approvals {
+		action := fmt.Sprintf("dismiss review %d of pull request %d", r.GetID(), pr.GetNumber())
+		_, resp, err := c.inner.PullRequests.DismissReview(ctx, owner, repo, pr.GetNumber(), r.GetID(), &github.PullRequestReviewDismissalRequest{
+			Message: github.Ptr(message),
+		})
+		if err := validateResponse(ctx, err, resp, action); err != nil {
+			return i, asBranchProtectionError(action, err)
+		}
+	}
+	clog.FromContext(ctx).Infof("dismissed %d approvals of pull request %d", len(approvals), pr.GetNumber())
+	return len(approvals), nil
+}
+
+// UpdateBranch merges the base branch of the pull request into its head
+// branch, as the "Update branch" button does, for pull requests whose
+// mergeable state is MergeableStateBehind. GitHub updates the branch in the
+// background. If the head branch has moved since pr was fetched, or conflicts
+// with the base, GitHub refuses with a BranchProtectionError.
+func (c GitHubClient) UpdateBranch(ctx context.Context, pr *github.PullRequest) error {
+	owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+	ctx, release, err := c.acquire(ctx, owner, repo, pr.GetNumber(), WriteOperation)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	action := fmt.Sprintf("update branch of pull request %d", pr.GetNumber())
+	opts := &github.PullRequestBranchUpdateOptions{}
+	if sha := pr.GetHead().GetSHA(); sha != "" {
+		opts.ExpectedHeadSHA = github.Ptr(sha)
+	}
+	_, resp, err := c.inner.PullRequests.UpdateBranch(ctx, owner, repo, pr.GetNumber(), opts)
+	var accepted *github.AcceptedError
+	if errors.As(err, &accepted) {
+		// GitHub answers 202 Accepted as it updates the branch in the
+		// background, which go-github reports as an error.
+		err = nil
+		resp.StatusCode = http.StatusOK
+	}
+	if err := validateResponse(ctx, err, resp, action); err != nil {
+		return asBranchProtectionError(action, err)
+	}
+	clog.FromContext(ctx).Infof("updating branch of pull request %d", pr.GetNumber())
+	return nil
+}
+
+// Mergeability is whether a pull request can be merged.
+type Mergeability struct {
+	// Mergeable reports whether the pull request merges with its base
+	// branch without conflicts.
+	Mergeable bool
+	// State summarizes whether the pull request can be merged, taking
+	// branch protection into account.
+	State MergeableState
+	// PullRequest is the pull request as last fetched.
+	PullRequest *github.PullRequest
+}
+
+// MergeabilityOption configures GetMergeability.
+type MergeabilityOption func(*mergeabilityConfig)
+
+type mergeabilityConfig struct {
+	poll    workflowConfig
+	timeout time.Duration
+}
+
+// WithMergeabilityPoll sets the interval between fetches of the pull request
+// while GitHub computes its mergeability, which doubles after every fetch
+// from initial up to maximum, and how long to wait in total. It defaults to
+// 1 second up to 8 seconds, for up to a minute.
+func WithMergeabilityPoll(initial, maximum, timeout time.Duration) MergeabilityOption {
+	return func(c *mergeabilityConfig) {
+		c.poll.minPoll, c.poll.maxPoll = initial, maximum
+		c.timeout = timeout
+	}
+}
+
+// GetMergeability fetches the pull request and reports whether it can be
+// merged. GitHub computes mergeability in the background after a pull
+// request or its base branch changes, and reports it as unknown meanwhile,
+// so GetMergeability fetches the pull request again until it is known.
+func (c GitHubClient) GetMergeability(ctx context.Context, pr *github.PullRequest, opts ...MergeabilityOption) (*Mergeability, error) {
+	cfg := mergeabilityConfig{
+		poll:    workflowConfig{minPoll: time.Second, maxPoll: 8 * time.Second},
+		timeout: time.Minute,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+
+	pollCtx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+	var latest *github.PullRequest
+	if err := cfg.poll.poll(pollCtx, func(ctx context.Context) (bool, error) {
+		p, err := c.getPullRequest(ctx, owner, repo, pr.GetNumber())
+		if err != nil {
+			return false, err
+		}
+		latest = p
+		return p.Mergeable != nil, nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed waiting for the mergeability of pull request %d: %w", pr.GetNumber(), err)
+	}
+	return &Mergeability{
+		Mergeable:   latest.GetMergeable(),
+		State:       MergeableState(latest.GetMergeableState()),
+		PullRequest: latest,
+	}, nil
+}
+
+func (c GitHubClient) getPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error) {
+	ctx, release, err := c.acquire(ctx, owner, repo, number, ReadOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	pr, resp, err := c.inner.PullRequests.Get(ctx, owner, repo, number)
+	if err := validateResponse(ctx, err, resp, fmt.Sprintf("get pull request %d", number)); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	"github.com/google/go-github/v88/github"
+)
+
+func TestAutoMerge(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{
+		Mergeable:      github.Ptr(true),
+		MergeableState: github.Ptr("blocked"),
+	})
+
+	if err := gh.EnableAutoMerge(ctx, pr, MergeMethodSquash); err != nil {
+		t.Fatalf("EnableAutoMerge: %v", err)
+	}
+	srv.AssertRequestCount(http.MethodPost, "/graphql", 1)
+	if got, want := srv.PullRequest("acme", "widgets", pr.GetNumber()).GetAutoMerge().GetMergeMethod(), "squash"; got != want {
+		t.Errorf("auto-merge method = %q, want %q", got, want)
+	}
+
+	if err := gh.DisableAutoMerge(ctx, pr); err != nil {
+		t.Fatalf("DisableAutoMerge: %v", err)
+	}
+	if got := srv.PullRequest("acme", "widgets", pr.GetNumber()).GetAutoMerge(); got != nil {
+		t.Errorf("auto-merge = %+v, want it disabled", got)
+	}
+
+	srv.SetMergeability("acme", "widgets", pr.GetNumber(), github.Ptr(true), "clean")
+	err := gh.EnableAutoMerge(ctx, pr, MergeMethodMerge)
+	if bpe, ok := errors.AsType[*BranchProtectionError](err); !ok {
+		t.Errorf("EnableAutoMerge of a clean pull request = %v, want a BranchProtectionError", err)
+	} else if bpe.Message == "" {
+		t.Error("BranchProtectionError has no message")
+	}
+
+	unknown := &github.PullRequest{NodeID: github.Ptr("PR_unknown"), Number: github.Ptr(99), Base: pr.Base}
+	if err := gh.EnableAutoMerge(ctx, unknown, MergeMethodMerge); err == nil {
+		t.Error("EnableAutoMerge of an unknown pull request succeeded")
+	} else if _, ok := errors.AsType[*BranchProtectionError](err); ok {
+		t.Errorf("EnableAutoMerge of an unknown pull request = %v, want another error", err)
+	}
+}
+
+func TestApprovals(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{})
+
+	if _, err := gh.Approve(ctx, pr, "deps", "Looks good"); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if _, err := gh.SubmitReview(ctx, pr, "other", Review{Event: ReviewEventApprove}); err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+
+	n, err := gh.DismissApprovals(ctx, pr, "deps", "New commits were pushed")
+	if err != nil {
+		t.Fatalf("DismissApprovals: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("dismissed %d approvals, want 1", n)
+	}
+	var states []string
+	for _, r := range srv.Reviews("acme", "widgets", pr.GetNumber()) {
+		states = append(states, r.GetState())
+	}
+	if len(states) != 2 || states[0] != "DISMISSED" || states[1] != "APPROVED" {
+		t.Errorf("review states = %v, want [DISMISSED APPROVED]", states)
+	}
+
+	// Dismissed approvals aren't dismissed again.
+	if n, err := gh.DismissApprovals(ctx, pr, "deps", "again"); err != nil || n != 0 {
+		t.Errorf("DismissApprovals = %d, %v, want 0, nil", n, err)
+	}
+}
+
+func TestUpdateBranch(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{
+		Head:           &github.PullRequestBranch{SHA: github.Ptr("abc123")},
+		Mergeable:      github.Ptr(true),
+		MergeableState: github.Ptr("behind"),
+	})
+
+	if err := gh.UpdateBranch(ctx, pr); err != nil {
+		t.Fatalf("UpdateBranch: %v", err)
+	}
+	stale := *pr
+	stale.Head = &github.PullRequestBranch{SHA: github.Ptr("abc123")}
+	if _, ok := errors.AsType[*BranchProtectionError](gh.UpdateBranch(ctx, &stale)); !ok {
+		t.Error("UpdateBranch of a moved head didn't return a BranchProtectionError")
+	}
+
+	srv.SetMergeability("acme", "widgets", pr.GetNumber(), github.Ptr(false), "dirty")
+	err := gh.UpdateBranch(ctx, srv.PullRequest("acme", "widgets", pr.GetNumber()))
+	if _, ok := errors.AsType[*BranchProtectionError](err); !ok {
+		t.Errorf("UpdateBranch of a conflicting branch = %v, want a BranchProtectionError", err)
+	}
+}
+
+func TestGetMergeability(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{MergeableState: github.Ptr("unknown")})
+	fast := WithMergeabilityPoll(time.Millisecond, 5*time.Millisecond, time.Second)
+
+	time.AfterFunc(20*time.Millisecond, func() {
+		srv.SetMergeability("acme", "widgets", pr.GetNumber(), github.Ptr(true), "behind")
+	})
+	m, err := gh.GetMergeability(ctx, pr, fast)
+	if err != nil {
+		t.Fatalf("GetMergeability: %v", err)
+	}
+	if !m.Mergeable || m.State != MergeableStateBehind {
+		t.Errorf("GetMergeability = %t, %q, want true, %q", m.Mergeable, m.State, MergeableStateBehind)
+	}
+
+	srv.SetMergeability("acme", "widgets", pr.GetNumber(), nil, "unknown")
+	if _, err := gh.GetMergeability(ctx, pr, WithMergeabilityPoll(time.Millisecond, 5*time.Millisecond, 20*time.Millisecond)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetMergeability = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
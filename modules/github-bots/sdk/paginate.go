@@ -0,0 +1,205 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/google/go-github/v88/github"
+)
+
+// PageFunc fetches the page of a list selected by opts, typically by calling
+// a go-github List method.
+type PageFunc[T any] func(ctx context.Context, opts *github.ListOptions) ([]T, *github.Response, error)
+
+// PaginateOption configures Paginate.
+type PaginateOption func(*paginateConfig)
+
+type paginateConfig struct {
+	perPage          int
+	maxItems         int
+	maxRateLimitWait time.Duration
+}
+
+// WithPerPage sets the number of items fetched per request. It defaults to
+// 100, the most GitHub allows.
+func WithPerPage(n int) PaginateOption {
+	return func(c *paginateConfig) {
+		c.perPage = n
+	}
+}
+
+// WithMaxItems stops the iteration after n items, to bound the number of
+// requests made on large lists. Zero means no limit.
+func WithMaxItems(n int) PaginateOption {
+	return func(c *paginateConfig) {
+		c.maxItems = n
+	}
+}
+
+// WithMaxRateLimitWait sets how long the iteration may wait in total for
+// GitHub's rate limits to reset before fetching the next page. It defaults to
+// 5 minutes; zero disables waiting.
+func WithMaxRateLimitWait(d time.Duration) PaginateOption {
+	return func(c *paginateConfig) {
+		c.maxRateLimitWait = d
+	}
+}
+
+// Paginate returns an iterator over the items of every page of a list,
+// fetched with page as the iteration proceeds. Pages are not fetched once
+// the consumer stops iterating. On failure the iterator yields the error,
+// described by action, and stops.
+//
+// When GitHub rejects a page because a primary or secondary rate limit is
+// exhausted, the page is fetched again once the limit resets, or after the
+// Retry-After delay GitHub asks for. If that would take the total wait past
+// WithMaxRateLimitWait, the iterator yields the rate limit error and stops.
+//
+// List methods taking their own options embed github.ListOptions, so adapt
+// them with a closure:
+//
+//	for c, err := range sdk.Paginate(ctx, "list comments", func(ctx context.Context, lo *github.ListOptions) ([]*github.IssueComment, *github.Response, error) {
+//		return gh.Issues.ListComments(ctx, owner, repo, number, &github.IssueListCommentsOptions{ListOptions: *lo})
+//	}) {
+//		...
+//	}
+func Paginate[T any](ctx context.Context, action string, page PageFunc[T], opts ...PaginateOption) iter.Seq2[T, error] {
+	cfg := paginateConfig{perPage: 100, maxRateLimitWait: 5 * time.Minute}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(yield func(T, error) bool) {
+		lo := &github.ListOptions{PerPage: cfg.perPage}
+		if cfg.maxItems > 0 {
+			// Don't fetch more than we'll yield.
+			lo.PerPage = min(lo.PerPage, cfg.maxItems)
+		}
+		n := 0
+		var waited time.Duration
+		for {
+			items, resp, err := page(ctx, lo)
+			if limited, delay := checkRateLimiting(ctx, err); limited && waited+delay <= cfg.maxRateLimitWait {
+				// Wait at least a second, as the reset time is rounded down.
+				delay = max(delay, time.Second)
+				waited += delay
+				clog.InfoContextf(ctx, "rate limited while trying to %s, retrying in %v", action, delay)
+				if err := sleep(ctx, delay); err != nil {
+					var zero T
+					yield(zero, fmt.Errorf("failed to %s: %w", action, err))
+					return
+				}
+				continue
+			}
+			if err := validateResponse(ctx, err, resp, action); err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+				n++
+				if cfg.maxItems > 0 && n >= cfg.maxItems {
+					return
+				}
+			}
+			if resp.NextPage == 0 {
+				return
+			}
+			lo.Page = resp.NextPage
+		}
+	}
+}
+
+// paginate is Paginate with a read permit held while each page is fetched.
+func paginate[T any](ctx context.Context, c GitHubClient, owner, repo, action string, page PageFunc[T], opts ...PaginateOption) iter.Seq2[T, error] {
+	return Paginate(ctx, action, func(ctx context.Context, lo *github.ListOptions) ([]T, *github.Response, error) {
+		ctx, release, err := c.acquire(ctx, owner, repo, 0, ReadOperation)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer release()
+		return page(ctx, lo)
+	}, opts...)
+}
+
+// AllIssueComments iterates over the comments of the issue or pull request
+// with the given number.
+func (c GitHubClient) AllIssueComments(ctx context.Context, owner, repo string, number int, opts ...PaginateOption) iter.Seq2[*github.IssueComment, error] {
+	return paginate(ctx, c, owner, repo, fmt.Sprintf("list comments of %s/%s#%d", owner, repo, number), func(ctx context.Context, lo *github.ListOptions) ([]*github.IssueComment, *github.Response, error) {
+		return c.inner.Issues.ListComments(ctx, owner, repo, number, &github.IssueListCommentsOptions{ListOptions: *lo})
+	}, opts...)
+}
+
+// AllIssues iterates over the issues of owner/repo in the given state: open,
+// closed or all. GitHub lists pull requests as issues too.
+func (c GitHubClient) AllIssues(ctx context.Context, owner, repo, state string, opts ...PaginateOption) iter.Seq2[*github.Issue, error] {
+	return paginate(ctx, c, owner, repo, fmt.Sprintf("list %s issues of %s/%s", state, owner, repo), func(ctx context.Context, lo *github.ListOptions) ([]*github.Issue, *github.Response, error) {
+		return c.inner.Issues.ListByRepo(ctx, owner, repo, &github.IssueListByRepoOptions{State: state, ListOptions: *lo})
+	}, opts...)
+}
+
+// AllPullRequests iterates over the pull requests of owner/repo in the given
+// state: open, closed or all.
+func (c GitHubClient) AllPullRequests(ctx context.Context, owner, repo, state string, opts ...PaginateOption) iter.Seq2[*github.PullRequest, error] {
+	return paginate(ctx, c, owner, repo, fmt.Sprintf("list %s pull requests of %s/%s", state, owner, repo), func(ctx context.Context, lo *github.ListOptions) ([]*github.PullRequest, *github.Response, error) {
+		return c.inner.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{State: state, ListOptions: *lo})
+	}, opts...)
+}
+
+// AllPullRequestFiles iterates over the files changed by the pull request.
+// GitHub lists at most 3000 files.
+func (c GitHubClient) AllPullRequestFiles(ctx context.Context, pr *github.PullRequest, opts ...PaginateOption) iter.Seq2[*github.CommitFile, error] {
+	owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+	return paginate(ctx, c, owner, repo, fmt.Sprintf("list files of pull request %d", pr.GetNumber()), func(ctx context.Context, lo *github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
+		return c.inner.PullRequests.ListFiles(ctx, owner, repo, pr.GetNumber(), lo)
+	}, opts...)
+}
+
+// AllPullRequestReviews iterates over the reviews of the pull request,
+// oldest first.
+func (c GitHubClient) AllPullRequestReviews(ctx context.Context, pr *github.PullRequest, opts ...PaginateOption) iter.Seq2[*github.PullRequestReview, error] {
+	owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+	return paginate(ctx, c, owner, repo, fmt.Sprintf("list reviews of pull request %d", pr.GetNumber()), func(ctx context.Context, lo *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+		return c.inner.PullRequests.ListReviews(ctx, owner, repo, pr.GetNumber(), lo)
+	}, opts...)
+}
+
+// AllWorkflowRunArtifacts iterates over the artifacts of the workflow run.
+func (c GitHubClient) AllWorkflowRunArtifacts(ctx context.Context, wr *github.WorkflowRun, opts ...PaginateOption) iter.Seq2[*github.Artifact, error] {
+	owner, repo := wr.GetRepository().GetOwner().GetLogin(), wr.GetRepository().GetName()
+	return paginate(ctx, c, owner, repo, "list workflow artifacts", func(ctx context.Context, lo *github.ListOptions) ([]*github.Artifact, *github.Response, error) {
+		list, resp, err := c.inner.Actions.ListWorkflowRunArtifacts(ctx, owner, repo, wr.GetID(), lo)
+		return list.GetArtifacts(), resp, err
+	}, opts...)
+}
+
+// AllCodeSearchResults iterates over the results of a code search query.
+// GitHub returns at most 1000 results per query.
+func (c GitHubClient) AllCodeSearchResults(ctx context.Context, query string, opts ...PaginateOption) iter.Seq2[*github.CodeResult, error] {
+	return Paginate(ctx, fmt.Sprintf("search code %q", query), func(ctx context.Context, lo *github.ListOptions) ([]*github.CodeResult, *github.Response, error) {
+		result, resp, err := c.inner.Search.Code(ctx, query, &github.SearchOptions{ListOptions: *lo})
+		return result.GetCodeResults(), resp, err
+	}, opts...)
+}
+
+// sleep waits for d, or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	"github.com/google/go-github/v88/github"
+)
+
+func TestPaginate(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{})
+	for i := range 7 {
+		srv.AddComment("acme", "widgets", pr.GetNumber(), &github.IssueComment{Body: github.Ptr(fmt.Sprint(i))})
+	}
+	c := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+	path := fmt.Sprintf("/repos/acme/widgets/issues/%d/comments", pr.GetNumber())
+
+	tests := []struct {
+		name      string
+		opts      []PaginateOption
+		stopAfter int
+		want      int
+		wantPages int
+	}{{
+		name:      "all pages",
+		opts:      []PaginateOption{WithPerPage(3)},
+		want:      7,
+		wantPages: 3,
+	}, {
+		name:      "consumer breaks",
+		opts:      []PaginateOption{WithPerPage(3)},
+		stopAfter: 4,
+		want:      4,
+		wantPages: 2,
+	}, {
+		name:      "max items",
+		opts:      []PaginateOption{WithPerPage(3), WithMaxItems(5)},
+		want:      5,
+		wantPages: 2,
+	}, {
+		name:      "max items below page size",
+		opts:      []PaginateOption{WithMaxItems(2)},
+		want:      2,
+		wantPages: 1,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(srv.Requests())
+			var got []string
+			for com, err := range c.AllIssueComments(ctx, "acme", "widgets", pr.GetNumber(), tt.opts...) {
+				if err != nil {
+					t.Fatalf("AllIssueComments: %v", err)
+				}
+				got = append(got, com.GetBody())
+				if len(got) == tt.stopAfter {
+					break
+				}
+			}
+			if len(got) != tt.want {
+				t.Errorf("got %d comments, want %d", len(got), tt.want)
+			}
+			for i, body := range got {
+				if body != fmt.Sprint(i) {
+					t.Errorf("comment %d = %q, want %q", i, body, fmt.Sprint(i))
+				}
+			}
+			pages := 0
+			for _, r := range srv.Requests()[before:] {
+				if r.Method == http.MethodGet && r.Path == path {
+					pages++
+				}
+			}
+			if pages != tt.wantPages {
+				t.Errorf("fetched %d pages, want %d", pages, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestPaginate_Error(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	c := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+
+	var errs int
+	for _, err := range c.AllPullRequestFiles(ctx, &github.PullRequest{
+		Number: github.Ptr(404),
+		Base:   &github.PullRequestBranch{Repo: &github.Repository{Owner: &github.User{Login: github.Ptr("acme")}, Name: github.Ptr("widgets")}},
+	}) {
+		if err == nil {
+			t.Fatal("got an item, want an error")
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Errorf("got %d errors, want 1", errs)
+	}
+}
+
+func TestPaginate_RateLimited(t *testing.T) {
+	ctx := context.Background()
+	var limited atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" && !limited.Swap(true) {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "You have exceeded a secondary rate limit.", "documentation_url": "https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}`))
+			return
+		}
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", `<`+"http://"+r.Host+r.URL.Path+`?page=2>; rel="next"`)
+		}
+		w.Write([]byte(`[{"body": "comment"}]`))
+	}))
+	defer srv.Close()
+	gh, err := github.NewClient(github.WithEnterpriseURLs(srv.URL, srv.URL))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	list := func(ctx context.Context, lo *github.ListOptions) ([]*github.IssueComment, *github.Response, error) {
+		return gh.Issues.ListComments(ctx, "acme", "widgets", 1, &github.IssueListCommentsOptions{ListOptions: *lo})
+	}
+
+	var n int
+	for _, err := range Paginate(ctx, "list comments", list) {
+		if err != nil {
+			t.Fatalf("Paginate: %v", err)
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("got %d comments, want 2 after waiting out the rate limit", n)
+	}
+
+	limited.Store(false)
+	var errs int
+	for _, err := range Paginate(ctx, "list comments", list, WithMaxRateLimitWait(0)) {
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("got %d errors, want the rate limit error without waiting", errs)
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/jonboulle/clockwork"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// clientPoolSize tracks the number of clients held by all ClientPools
+var clientPoolSize = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "github_bots_client_pool_size",
+		Help: "Current number of GitHub clients held in client pools",
+	},
+)
+
+// ClientPool shares Octo STS backed GitHubClients across events, keyed by
+// (org, repo, policy). Pooled clients refresh their tokens before they
+// expire, so they remain usable for long-running handlers.
+//
+// Clients that have been idle for longer than the idle timeout, or that are
+// the least recently used when the pool grows past its maximum size, are
+// evicted and their tokens revoked with GitHubClient.Close. Clients are never
+// evicted while they are in use. Tokens replaced by a refresh are left to
+// expire, as other events may still be using them.
+type ClientPool struct {
+	opts        []GitHubClientOption
+	idleTimeout time.Duration
+	maxSize     int
+	clock       clockwork.Clock
+
+	mu      sync.Mutex
+	closed  bool
+	clients map[clientPoolKey]*pooledClient
+}
+
+type clientPoolKey struct {
+	org, repo, policyName string
+}
+
+type pooledClient struct {
+	client   GitHubClient
+	refs     int
+	lastUsed time.Time
+}
+
+// ClientPoolOption configures a ClientPool.
+type ClientPoolOption func(*ClientPool)
+
+// WithPoolIdleTimeout sets how long a client may go unused before it is
+// evicted. It defaults to 15 minutes.
+func WithPoolIdleTimeout(d time.Duration) ClientPoolOption {
+	return func(p *ClientPool) {
+		p.idleTimeout = d
+	}
+}
+
+// WithPoolMaxSize sets the number of clients above which idle clients are
+// evicted, least recently used first. It defaults to 100.
+func WithPoolMaxSize(n int) ClientPoolOption {
+	return func(p *ClientPool) {
+		p.maxSize = n
+	}
+}
+
+// WithPoolClientOptions sets the options applied to every client created by
+// the pool.
+func WithPoolClientOptions(opts ...GitHubClientOption) ClientPoolOption {
+	return func(p *ClientPool) {
+		p.opts = opts
+	}
+}
+
+// NewClientPool returns an empty ClientPool. Call Close on shutdown to revoke
+// the tokens of all pooled clients.
+func NewClientPool(opts ...ClientPoolOption) *ClientPool {
+	p := &ClientPool{
+		idleTimeout: 15 * time.Minute,
+		maxSize:     100,
+		clock:       clockwork.NewRealClock(),
+		clients:     make(map[clientPoolKey]*pooledClient),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Get returns the pooled client for the given org, repo and policy name,
+// creating it if needed. The returned release function must be called once
+// the caller is done with the client; the client must not be closed directly.
+func (p *ClientPool) Get(ctx context.Context, org, repo, policyName string) (GitHubClient, func(), error) {
+	key := clientPoolKey{org: org, repo: repo, policyName: policyName}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return GitHubClient{}, nil, errors.New("client pool is closed")
+	}
+
+	pc, ok := p.clients[key]
+	if !ok {
+		clog.DebugContextf(ctx, "creating pooled client for %s/%s - %s", org, repo, policyName)
+		// Pooled clients outlive the event that created them.
+		client := NewGitHubClient(context.WithoutCancel(ctx), org, repo, policyName, p.opts...)
+		if client.octo != nil {
+			// Other events may still be using a token when one refreshes
+			// it, so only eviction and Close revoke them.
+			client.octo.pooled = true
+		}
+		pc = &pooledClient{client: client}
+		p.clients[key] = pc
+		clientPoolSize.Inc()
+	}
+	pc.refs++
+	pc.lastUsed = p.clock.Now()
+	evicted := p.evictLocked()
+	p.mu.Unlock()
+
+	if err := p.closeAll(ctx, evicted); err != nil {
+		clog.WarnContextf(ctx, "failed to evict pooled clients: %v", err)
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() { p.release(key, pc) })
+	}
+	return pc.client, release, nil
+}
+
+func (p *ClientPool) release(key clientPoolKey, pc *pooledClient) {
+	p.mu.Lock()
+	pc.refs--
+	pc.lastUsed = p.clock.Now()
+
+	var evicted []*pooledClient
+	if p.closed {
+		if pc.refs == 0 && p.clients[key] == pc {
+			delete(p.clients, key)
+			clientPoolSize.Dec()
+			evicted = append(evicted, pc)
+		}
+	} else {
+		evicted = p.evictLocked()
+	}
+	p.mu.Unlock()
+
+	ctx := context.Background()
+	if err := p.closeAll(ctx, evicted); err != nil {
+		clog.WarnContextf(ctx, "failed to evict pooled clients: %v", err)
+	}
+}
+
+// evictLocked removes idle clients that have expired or that exceed the
+// maximum pool size, and returns them so they can be closed without holding
+// the lock.
+func (p *ClientPool) evictLocked() []*pooledClient {
+	now := p.clock.Now()
+
+	var idle []clientPoolKey
+	for k, pc := range p.clients {
+		if pc.refs == 0 {
+			idle = append(idle, k)
+		}
+	}
+	// Oldest first, so size-based eviction removes the least recently used.
+	slices.SortFunc(idle, func(a, b clientPoolKey) int {
+		return p.clients[a].lastUsed.Compare(p.clients[b].lastUsed)
+	})
+
+	var evicted []*pooledClient
+	for _, k := range idle {
+		pc := p.clients[k]
+		if now.Sub(pc.lastUsed) < p.idleTimeout && len(p.clients) <= p.maxSize {
+			break
+		}
+		delete(p.clients, k)
+		clientPoolSize.Dec()
+		evicted = append(evicted, pc)
+	}
+	return evicted
+}
+
+func (p *ClientPool) closeAll(ctx context.Context, pcs []*pooledClient) error {
+	var errs []error
+	for _, pc := range pcs {
+		if err := pc.client.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("closing client for %s/%s: %w", pc.client.org, pc.client.repo, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Len returns the number of clients currently held by the pool.
+func (p *ClientPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.clients)
+}
+
+// Close revokes the tokens of all idle clients and stops the pool from
+// handing out new ones. Clients still in use are closed when released.
+func (p *ClientPool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	var idle []*pooledClient
+	for k, pc := range p.clients {
+		if pc.refs == 0 {
+			delete(p.clients, k)
+			clientPoolSize.Dec()
+			idle = append(idle, pc)
+		}
+	}
+	p.mu.Unlock()
+
+	// Close in a stable order so logs are easy to follow.
+	slices.SortFunc(idle, func(a, b *pooledClient) int {
+		return cmp.Or(cmp.Compare(a.client.org, b.client.org), cmp.Compare(a.client.repo, b.client.repo))
+	})
+	return p.closeAll(ctx, idle)
+}
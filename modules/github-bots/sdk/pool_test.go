@@ -0,0 +1,258 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	gitHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/jonboulle/clockwork"
+)
+
+// fakeOctoSTS replaces OctoTokenFunc and OctoRevokeFunc for the duration of a
+// test, recording minted and revoked tokens.
+type fakeOctoSTS struct {
+	mu      sync.Mutex
+	minted  []string
+	revoked []string
+}
+
+func newFakeOctoSTS(t *testing.T) *fakeOctoSTS {
+	t.Helper()
+	f := &fakeOctoSTS{}
+	origToken, origRevoke := OctoTokenFunc, OctoRevokeFunc
+	t.Cleanup(func() { OctoTokenFunc, OctoRevokeFunc = origToken, origRevoke })
+
+	OctoTokenFunc = func(_ context.Context, policy, org, repo string) (string, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		tok := fmt.Sprintf("%s/%s/%s#%d", org, repo, policy, len(f.minted))
+		f.minted = append(f.minted, tok)
+		return tok, nil
+	}
+	OctoRevokeFunc = func(_ context.Context, tok string) error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.revoked = append(f.revoked, tok)
+		return nil
+	}
+	return f
+}
+
+func (f *fakeOctoSTS) Revoked() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.revoked)
+}
+
+func TestClientPool_SharesClients(t *testing.T) {
+	octo := newFakeOctoSTS(t)
+	ctx := context.Background()
+	p := NewClientPool()
+
+	c1, release1, err := p.Get(ctx, "acme", "widgets", "bot")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	c2, release2, err := p.Get(ctx, "acme", "widgets", "bot")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if c1.Client() != c2.Client() {
+		t.Error("expected the same client for the same (org, repo, policy)")
+	}
+	if _, _, err := p.Get(ctx, "acme", "widgets", "other-policy"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got, want := p.Len(), 2; got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+
+	for _, c := range []GitHubClient{c1, c2} {
+		if _, err := c.GitAuth(); err != nil {
+			t.Fatalf("GitAuth: %v", err)
+		}
+	}
+	if got, want := len(octo.minted), 1; got != want {
+		t.Errorf("minted %d tokens, want %d", got, want)
+	}
+
+	release1()
+	release2()
+	release2() // releasing twice is a no-op
+	if got := octo.Revoked(); len(got) != 0 {
+		t.Errorf("revoked %v before eviction, want none", got)
+	}
+}
+
+func TestClientPool_EvictsIdleClients(t *testing.T) {
+	octo := newFakeOctoSTS(t)
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+	p := NewClientPool(WithPoolIdleTimeout(time.Minute))
+	p.clock = clock
+
+	c, release, err := p.Get(ctx, "acme", "widgets", "bot")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := c.GitAuth(); err != nil {
+		t.Fatalf("GitAuth: %v", err)
+	}
+
+	// Clients in use are never evicted, however long they are held.
+	clock.Advance(time.Hour)
+	if _, _, err := p.Get(ctx, "acme", "gadgets", "bot"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := octo.Revoked(); len(got) != 0 {
+		t.Errorf("revoked %v while in use, want none", got)
+	}
+
+	release()
+	clock.Advance(2 * time.Minute)
+	if _, _, err := p.Get(ctx, "acme", "gizmos", "bot"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got, want := octo.Revoked(), []string{"acme/widgets/bot#0"}; !slices.Equal(got, want) {
+		t.Errorf("revoked = %v, want %v", got, want)
+	}
+	if got, want := p.Len(), 2; got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+}
+
+func TestClientPool_MaxSize(t *testing.T) {
+	newFakeOctoSTS(t)
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+	p := NewClientPool(WithPoolMaxSize(2))
+	p.clock = clock
+
+	for _, repo := range []string{"a", "b", "c"} {
+		_, release, err := p.Get(ctx, "acme", repo, "bot")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		release()
+		clock.Advance(time.Second)
+	}
+	if got, want := p.Len(), 2; got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+	p.mu.Lock()
+	_, ok := p.clients[clientPoolKey{org: "acme", repo: "a", policyName: "bot"}]
+	p.mu.Unlock()
+	if ok {
+		t.Error("expected the least recently used client to be evicted")
+	}
+}
+
+func TestClientPool_Close(t *testing.T) {
+	octo := newFakeOctoSTS(t)
+	ctx := context.Background()
+	p := NewClientPool()
+
+	idle, releaseIdle, err := p.Get(ctx, "acme", "idle", "bot")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := idle.GitAuth(); err != nil {
+		t.Fatalf("GitAuth: %v", err)
+	}
+	releaseIdle()
+
+	// Clients that never minted a token have nothing to revoke.
+	_, releaseUnused, err := p.Get(ctx, "acme", "unused", "bot")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	releaseUnused()
+
+	busy, releaseBusy, err := p.Get(ctx, "acme", "busy", "bot")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := busy.GitAuth(); err != nil {
+		t.Fatalf("GitAuth: %v", err)
+	}
+
+	if err := p.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got, want := octo.Revoked(), []string{"acme/idle/bot#0"}; !slices.Equal(got, want) {
+		t.Errorf("revoked = %v, want %v", got, want)
+	}
+	if _, _, err := p.Get(ctx, "acme", "idle", "bot"); err == nil {
+		t.Error("Get: expected error after Close")
+	}
+
+	releaseBusy()
+	if got, want := octo.Revoked(), []string{"acme/idle/bot#0", "acme/busy/bot#1"}; !slices.Equal(got, want) {
+		t.Errorf("revoked = %v, want %v", got, want)
+	}
+	if got := p.Len(); got != 0 {
+		t.Errorf("Len() = %d, want 0", got)
+	}
+}
+
+func TestClientPool_SharesClientsAcrossRefresh(t *testing.T) {
+	octo := newFakeOctoSTS(t)
+	ctx := context.Background()
+	p := NewClientPool()
+
+	c1, release1, err := p.Get(ctx, "acme", "widgets", "bot")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	// Refresh the token on every request, as if each came after it expired.
+	p.mu.Lock()
+	pc := p.clients[clientPoolKey{org: "acme", repo: "widgets", policyName: "bot"}]
+	pc.client.ts = pc.client.octo
+	c1 = pc.client
+	p.mu.Unlock()
+
+	c2, release2, err := p.Get(ctx, "acme", "widgets", "bot")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	// The first handler starts a long clone, then the second refreshes the
+	// shared token.
+	auth1, err := c1.GitAuth()
+	if err != nil {
+		t.Fatalf("GitAuth: %v", err)
+	}
+	if _, err := c2.GitAuth(); err != nil {
+		t.Fatalf("GitAuth: %v", err)
+	}
+	if got := octo.Revoked(); len(got) != 0 {
+		t.Errorf("revoked %v on refresh, want none", got)
+	}
+
+	// The clone's next request carries the current token.
+	req := httptest.NewRequest(http.MethodGet, "https://github.com/acme/widgets.git/info/refs", nil)
+	auth1.(gitHttp.AuthMethod).SetAuth(req)
+	if user, pass, _ := req.BasicAuth(); user != "x-access-token" || pass != "acme/widgets/bot#2" {
+		t.Errorf("BasicAuth() = %q, %q, want the refreshed token", user, pass)
+	}
+
+	release1()
+	release2()
+	if err := p.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got, want := octo.Revoked(), []string{"acme/widgets/bot#2"}; !slices.Equal(got, want) {
+		t.Errorf("revoked = %v, want %v", got, want)
+	}
+}
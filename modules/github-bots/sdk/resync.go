@@ -0,0 +1,242 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-events/schemas"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-github/v88/github"
+	"github.com/jonboulle/clockwork"
+	"golang.org/x/time/rate"
+)
+
+// ResyncAction is the action of the events synthesized by a Resyncer.
+const ResyncAction = "resync"
+
+// Resyncer replays a bot's handlers over the open pull requests, and
+// optionally issues, of a set of repositories, to catch up on webhooks the
+// bot missed. It is meant to run periodically, for example from a cron job.
+//
+// For each open pull request, the bot's PullRequestHandler is invoked with a
+// github.PullRequestEvent whose action is "resync", dispatched exactly as
+// Serve dispatches events, so handlers need no changes to support it.
+type Resyncer struct {
+	bot    Bot
+	client ClientFunc
+	repos  []string
+
+	issues       bool
+	limiter      *rate.Limiter
+	minRemaining int
+	clock        clockwork.Clock
+}
+
+// ResyncOption configures a Resyncer.
+type ResyncOption func(*Resyncer)
+
+// WithResyncIssues also replays the bot's IssuesHandler over open issues.
+func WithResyncIssues() ResyncOption {
+	return func(r *Resyncer) {
+		r.issues = true
+	}
+}
+
+// WithResyncRate sets how many handler invocations per second the Resyncer
+// makes. It defaults to 1.
+func WithResyncRate(limit rate.Limit) ResyncOption {
+	return func(r *Resyncer) {
+		r.limiter = rate.NewLimiter(limit, 1)
+	}
+}
+
+// WithResyncMinRemaining sets the number of remaining GitHub API requests
+// below which the Resyncer pauses until the rate limit resets. It defaults
+// to 500, leaving room for the bot's regular event handling. The rate limit
+// is checked before each handler invocation, so requests made by the
+// handlers count against it too.
+func WithResyncMinRemaining(n int) ResyncOption {
+	return func(r *Resyncer) {
+		r.minRemaining = n
+	}
+}
+
+// NewResyncer returns a Resyncer for the bot over the given repositories,
+// each named "owner/repo". client returns the client used to list the open
+// pull requests and issues of each repository.
+func NewResyncer(b Bot, client ClientFunc, repos []string, opts ...ResyncOption) *Resyncer {
+	r := &Resyncer{
+		bot:          b,
+		client:       client,
+		repos:        repos,
+		limiter:      rate.NewLimiter(1, 1),
+		minRemaining: 500,
+		clock:        clockwork.NewRealClock(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run replays the bot's handlers over every configured repository. Handler
+// failures and invalid repository names are logged and do not stop the run;
+// they are returned together once every repository has been resynced.
+func (r *Resyncer) Run(ctx context.Context) error {
+	var errs []error
+	for _, full := range r.repos {
+		owner, repo, ok := strings.Cut(full, "/")
+		if !ok || owner == "" || repo == "" {
+			clog.ErrorContextf(ctx, "invalid repository %q, want owner/repo", full)
+			errs = append(errs, fmt.Errorf("invalid repository %q, want owner/repo", full))
+			continue
+		}
+		if err := r.resyncRepo(ctx, owner, repo); err != nil {
+			if ctx.Err() != nil {
+				return errors.Join(append(errs, err)...)
+			}
+			clog.ErrorContextf(ctx, "failed to resync %s: %v", full, err)
+			errs = append(errs, fmt.Errorf("resync %s: %w", full, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Resyncer) resyncRepo(ctx context.Context, owner, repo string) error {
+	_, hasPRHandler := r.bot.Handlers[PullRequestEvent]
+	_, hasIssuesHandler := r.bot.Handlers[IssuesEvent]
+	resyncIssues := r.issues && hasIssuesHandler
+	if !hasPRHandler && !resyncIssues {
+		return nil
+	}
+
+	gh, err := r.client(ctx, owner, repo)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	var errs []error
+	if hasPRHandler {
+		opts := &github.PullRequestListOptions{
+			State:       "open",
+			ListOptions: github.ListOptions{PerPage: 100},
+		}
+		for {
+			prs, resp, err := gh.inner.PullRequests.List(ctx, owner, repo, opts)
+			if err := validateResponse(ctx, err, resp, fmt.Sprintf("list open pull requests of %s/%s", owner, repo)); err != nil {
+				return errors.Join(append(errs, err)...)
+			}
+			for _, pr := range prs {
+				if err := r.dispatch(ctx, gh, PullRequestEvent, pr.GetHTMLURL(), github.PullRequestEvent{
+					Action:      github.Ptr(ResyncAction),
+					Number:      pr.Number,
+					PullRequest: pr,
+					Repo:        pr.GetBase().GetRepo(),
+				}); err != nil {
+					if ctx.Err() != nil {
+						return err
+					}
+					errs = append(errs, fmt.Errorf("pull request %d: %w", pr.GetNumber(), err))
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+	}
+
+	if resyncIssues {
+		repository, resp, err := gh.inner.Repositories.Get(ctx, owner, repo)
+		if err := validateResponse(ctx, err, resp, fmt.Sprintf("get repository %s/%s", owner, repo)); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		opts := &github.IssueListByRepoOptions{
+			State:       "open",
+			ListOptions: github.ListOptions{PerPage: 100},
+		}
+		for {
+			issues, resp, err := gh.inner.Issues.ListByRepo(ctx, owner, repo, opts)
+			if err := validateResponse(ctx, err, resp, fmt.Sprintf("list open issues of %s/%s", owner, repo)); err != nil {
+				return errors.Join(append(errs, err)...)
+			}
+			for _, issue := range issues {
+				// The issues API lists pull requests too.
+				if issue.IsPullRequest() {
+					continue
+				}
+				if err := r.dispatch(ctx, gh, IssuesEvent, "", github.IssueEvent{
+					Action:     ResyncAction,
+					Issue:      issue,
+					Repository: repository,
+				}); err != nil {
+					if ctx.Err() != nil {
+						return err
+					}
+					errs = append(errs, fmt.Errorf("issue %d: %w", issue.GetNumber(), err))
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.ListOptions.Page = resp.NextPage
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// dispatch paces itself and hands body to the bot as an event of type etype,
+// the way Serve would.
+func (r *Resyncer) dispatch(ctx context.Context, gh GitHubClient, etype EventType, prURL string, body any) error {
+	if err := r.pace(ctx, gh); err != nil {
+		return err
+	}
+
+	event := cloudevents.NewEvent()
+	event.SetID(fmt.Sprintf("resync-%d", r.clock.Now().UnixNano()))
+	event.SetSource("github-bots/resync")
+	event.SetType(string(etype))
+	event.SetTime(r.clock.Now())
+	event.SetExtension("action", ResyncAction)
+	if prURL != "" {
+		event.SetExtension("pullrequesturl", prURL)
+	}
+	if err := event.SetData(cloudevents.ApplicationJSON, schemas.Wrapper[any]{
+		When: r.clock.Now(),
+		Body: body,
+	}); err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", etype, err)
+	}
+	return r.bot.handleEvent(ctx, event)
+}
+
+// pace waits for the next handler invocation slot, and for the rate limit to
+// reset if the client's current quota is nearly exhausted. The quota is read
+// afresh each time, as the handlers spend it as well as the listings.
+func (r *Resyncer) pace(ctx context.Context, gh GitHubClient) error {
+	limits, _, err := gh.inner.RateLimit.Get(ctx)
+	if err != nil {
+		// Don't hold up the resync on a rate limit we can't read; the
+		// handlers' own requests will still be rate limited by GitHub.
+		clog.WarnContextf(ctx, "failed to get GitHub rate limit: %v", err)
+	} else if core := limits.GetCore(); core != nil && core.Remaining < r.minRemaining {
+		if wait := r.clock.Until(core.Reset.Time); wait > 0 {
+			clog.WarnContextf(ctx, "%d GitHub API requests remaining, pausing resync for %v", core.Remaining, wait)
+			select {
+			case <-r.clock.After(wait):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return r.limiter.Wait(ctx)
+}
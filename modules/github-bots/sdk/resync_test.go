@@ -0,0 +1,115 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	"github.com/google/go-github/v88/github"
+	"github.com/jonboulle/clockwork"
+	"golang.org/x/time/rate"
+)
+
+func TestResyncer(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.AddPullRequest("acme", "widgets", &github.PullRequest{HTMLURL: github.Ptr("https://github.com/acme/widgets/pull/1")})
+	srv.AddPullRequest("acme", "widgets", &github.PullRequest{State: github.Ptr("closed")})
+	srv.AddIssue("acme", "widgets", &github.Issue{Title: github.Ptr("bug")})
+	srv.AddPullRequest("acme", "gadgets", &github.PullRequest{})
+	srv.AddPullRequest("acme", "gadgets", &github.PullRequest{})
+
+	var prs, issues []string
+	bot := NewBot("test",
+		BotWithHandler(PullRequestHandler(func(ctx context.Context, pre github.PullRequestEvent) error {
+			if pre.GetAction() != ResyncAction || AttributeFromContext(ctx, "action") != ResyncAction {
+				t.Errorf("action = %q, want %q", pre.GetAction(), ResyncAction)
+			}
+			prs = append(prs, fmt.Sprintf("%s#%d", pre.GetRepo().GetFullName(), pre.GetNumber()))
+			if pre.GetNumber() == 2 {
+				return errors.New("boom")
+			}
+			return nil
+		})),
+		BotWithHandler(IssuesHandler(func(_ context.Context, ie github.IssueEvent) error {
+			issues = append(issues, fmt.Sprintf("%s#%d", ie.GetRepository().GetFullName(), ie.GetIssue().GetNumber()))
+			return nil
+		})),
+	)
+
+	r := NewResyncer(bot, func(ctx context.Context, owner, repo string) (GitHubClient, error) {
+		return NewGitHubClient(ctx, owner, repo, "test", WithClient(srv.Client())), nil
+	}, []string{"acme/widgets", "widgets", "acme/gadgets"}, WithResyncIssues(), WithResyncRate(rate.Inf))
+
+	err := r.Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "boom") || !strings.Contains(err.Error(), `invalid repository "widgets"`) {
+		t.Errorf("Run() = %v, want the handler's error and the invalid repository", err)
+	}
+
+	slices.Sort(prs)
+	if want := []string{"acme/gadgets#1", "acme/gadgets#2", "acme/widgets#1"}; !slices.Equal(prs, want) {
+		t.Errorf("resynced pull requests = %v, want %v", prs, want)
+	}
+	if want := []string{"acme/widgets#3"}; !slices.Equal(issues, want) {
+		t.Errorf("resynced issues = %v, want %v", issues, want)
+	}
+}
+
+func TestResyncer_PausesOnLowQuota(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.AddPullRequest("acme", "widgets", &github.PullRequest{})
+	srv.AddPullRequest("acme", "widgets", &github.PullRequest{})
+	clock := clockwork.NewFakeClock()
+	reset := clock.Now().Add(time.Hour)
+
+	// The first handler spends the quota; the listing response predates it.
+	var mu sync.Mutex
+	var prs []int
+	bot := NewBot("test", BotWithHandler(PullRequestHandler(func(_ context.Context, pre github.PullRequestEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		prs = append(prs, pre.GetNumber())
+		srv.SetRateLimit(10, reset)
+		return nil
+	})))
+
+	r := NewResyncer(bot, func(ctx context.Context, owner, repo string) (GitHubClient, error) {
+		return NewGitHubClient(ctx, owner, repo, "test", WithClient(srv.Client())), nil
+	}, []string{"acme/widgets"}, WithResyncRate(rate.Inf))
+	r.clock = clock
+
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := clock.BlockUntilContext(waitCtx, 1); err != nil {
+		t.Fatalf("resync didn't pause for the rate limit: %v", err)
+	}
+	mu.Lock()
+	if len(prs) != 1 {
+		t.Errorf("resynced %d pull requests before the reset, want 1", len(prs))
+	}
+	mu.Unlock()
+
+	srv.SetRateLimit(5000, reset.Add(time.Hour))
+	clock.Advance(time.Hour)
+	if err := <-done; err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+	if len(prs) != 2 {
+		t.Errorf("resynced %d pull requests, want 2", len(prs))
+	}
+}
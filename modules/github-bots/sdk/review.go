@@ -0,0 +1,354 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/google/go-github/v88/github"
+)
+
+// DiffSide is the side of a diff a review comment applies to.
+type DiffSide string
+
+const (
+	// DiffSideLeft is the base version of a file: deleted and context lines.
+	DiffSideLeft DiffSide = "LEFT"
+	// DiffSideRight is the head version of a file: added and context lines.
+	DiffSideRight DiffSide = "RIGHT"
+)
+
+// DiffLineKind is the kind of a line in a diff hunk.
+type DiffLineKind int
+
+const (
+	// DiffLineContext is a line present in both versions of a file.
+	DiffLineContext DiffLineKind = iota
+	// DiffLineAdded is a line present only in the head version.
+	DiffLineAdded
+	// DiffLineDeleted is a line present only in the base version.
+	DiffLineDeleted
+)
+
+// DiffLine is a line of a diff hunk.
+type DiffLine struct {
+	Kind DiffLineKind
+	// OldLine and NewLine are the line numbers in the base and head versions
+	// of the file, or 0 if the line is absent from that version.
+	OldLine, NewLine int
+	// Position is the line's position in the file's patch, counting from
+	// the line after the first hunk header, as used by the legacy position
+	// field of review comments.
+	Position int
+	Content  string
+}
+
+// DiffHunk is a hunk of a file's patch.
+type DiffHunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	// Section is the text following the hunk header, usually the enclosing
+	// function.
+	Section string
+	Lines   []DiffLine
+}
+
+// contains reports whether line on side is part of the hunk.
+func (h DiffHunk) contains(line int, side DiffSide) bool {
+	for _, l := range h.Lines {
+		switch {
+		case side == DiffSideLeft && l.Kind != DiffLineAdded && l.OldLine == line:
+			return true
+		case side == DiffSideRight && l.Kind != DiffLineDeleted && l.NewLine == line:
+			return true
+		}
+	}
+	return false
+}
+
+// FileDiff is the parsed diff of a file changed by a pull request.
+type FileDiff struct {
+	Path string
+	// PreviousPath is the path of a renamed file before the rename.
+	PreviousPath string
+	// Status is added, removed, modified, renamed, copied, changed or
+	// unchanged.
+	Status string
+	// Hunks is empty for binary files and for files whose patch GitHub
+	// omits because it is too large.
+	Hunks []DiffHunk
+}
+
+// Commentable reports whether an inline review comment can be made on line
+// of side of the file.
+func (f FileDiff) Commentable(line int, side DiffSide) bool {
+	return f.hunkOf(line, side) >= 0
+}
+
+// hunkOf returns the index of the hunk containing line on side, or -1.
+func (f FileDiff) hunkOf(line int, side DiffSide) int {
+	for i, h := range f.Hunks {
+		if h.contains(line, side) {
+			return i
+		}
+	}
+	return -1
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+
+// ParsePatch parses the unified diff of a single file, as found in the patch
+// field of the files of a pull request or commit.
+func ParsePatch(patch string) ([]DiffHunk, error) {
+	var hunks []DiffHunk
+	var oldLine, newLine, first int
+	for i, text := range strings.Split(strings.TrimSuffix(patch, "\n"), "\n") {
+		if m := hunkHeader.FindStringSubmatch(text); m != nil {
+			h := DiffHunk{Section: m[5]}
+			h.OldStart, _ = strconv.Atoi(m[1])
+			h.OldLines = hunkLength(m[2])
+			h.NewStart, _ = strconv.Atoi(m[3])
+			h.NewLines = hunkLength(m[4])
+			if len(hunks) == 0 {
+				first = i
+			}
+			hunks = append(hunks, h)
+			oldLine, newLine = h.OldStart, h.NewStart
+			continue
+		}
+		if len(hunks) == 0 {
+			if text == "" {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: expected hunk header, got %q", i+1, text)
+		}
+		h := &hunks[len(hunks)-1]
+
+		l := DiffLine{Position: i - first}
+		switch {
+		case strings.HasPrefix(text, "+"):
+			l.Kind, l.NewLine, l.Content = DiffLineAdded, newLine, text[1:]
+			newLine++
+		case strings.HasPrefix(text, "-"):
+			l.Kind, l.OldLine, l.Content = DiffLineDeleted, oldLine, text[1:]
+			oldLine++
+		case strings.HasPrefix(text, " "), text == "":
+			l.Kind, l.OldLine, l.NewLine = DiffLineContext, oldLine, newLine
+			if text != "" {
+				l.Content = text[1:]
+			}
+			oldLine++
+			newLine++
+		case strings.HasPrefix(text, `\`):
+			// "\ No newline at end of file" annotates the previous line.
+			continue
+		default:
+			return nil, fmt.Errorf("line %d: unexpected diff line %q", i+1, text)
+		}
+		h.Lines = append(h.Lines, l)
+	}
+	return hunks, nil
+}
+
+// hunkLength parses the optional length of a hunk range, which defaults to 1.
+func hunkLength(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// PullRequestDiff is the parsed diff of a pull request.
+type PullRequestDiff struct {
+	Files []FileDiff
+}
+
+// File returns the diff of the file at path in the head of the pull request.
+func (d *PullRequestDiff) File(path string) (FileDiff, bool) {
+	for _, f := range d.Files {
+		if f.Path == path {
+			return f, true
+		}
+	}
+	return FileDiff{}, false
+}
+
+// Commentable reports whether an inline review comment can be made on line
+// of side of the file at path.
+func (d *PullRequestDiff) Commentable(path string, line int, side DiffSide) bool {
+	f, ok := d.File(path)
+	return ok && f.Commentable(line, side)
+}
+
+// ListPullRequestFiles returns the files changed by the pull request. GitHub
+// lists at most 3000 files.
+func (c GitHubClient) ListPullRequestFiles(ctx context.Context, pr *github.PullRequest) ([]*github.CommitFile, error) {
+	var all []*github.CommitFile
+	for f, err := range c.AllPullRequestFiles(ctx, pr) {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, f)
+	}
+	return all, nil
+}
+
+// GetPullRequestDiff fetches and parses the diff of the pull request at its
+// head commit, by comparing its base and head SHAs, so it still describes
+// that commit if the pull request is pushed to meanwhile. GitHub compares at
+// most 300 files. If pr lacks either SHA, the pull request's current files
+// are listed instead.
+func (c GitHubClient) GetPullRequestDiff(ctx context.Context, pr *github.PullRequest) (*PullRequestDiff, error) {
+	var files []*github.CommitFile
+	if base, head := pr.GetBase().GetSHA(), pr.GetHead().GetSHA(); base != "" && head != "" {
+		owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+		comparison, err := c.CompareCommits(ctx, owner, repo, base, head, nil)
+		if err != nil {
+			return nil, err
+		}
+		files = comparison.Files
+	} else {
+		var err error
+		if files, err = c.ListPullRequestFiles(ctx, pr); err != nil {
+			return nil, err
+		}
+	}
+	d := &PullRequestDiff{Files: make([]FileDiff, 0, len(files))}
+	for _, f := range files {
+		hunks, err := ParsePatch(f.GetPatch())
+		if err != nil {
+			return nil, fmt.Errorf("parsing patch of %s: %w", f.GetFilename(), err)
+		}
+		d.Files = append(d.Files, FileDiff{
+			Path:         f.GetFilename(),
+			PreviousPath: f.GetPreviousFilename(),
+			Status:       f.GetStatus(),
+			Hunks:        hunks,
+		})
+	}
+	return d, nil
+}
+
+// ReviewEvent is the verdict of a pull request review.
+type ReviewEvent string
+
+const (
+	ReviewEventComment        ReviewEvent = "COMMENT"
+	ReviewEventRequestChanges ReviewEvent = "REQUEST_CHANGES"
+	ReviewEventApprove        ReviewEvent = "APPROVE"
+)
+
+// ReviewComment is an inline comment of a review.
+type ReviewComment struct {
+	Path string
+	// Line is the line commented on, or the last line of a multi-line
+	// comment.
+	Line int
+	// StartLine is the first line of a multi-line comment, or 0.
+	StartLine int
+	// Side defaults to DiffSideRight.
+	Side DiffSide
+	Body string
+}
+
+// Review is a pull request review.
+type Review struct {
+	Event    ReviewEvent
+	Body     string
+	Comments []ReviewComment
+}
+
+// SubmitReview submits a single review of the pull request with all of the
+// review's inline comments. Comments on lines that are not part of the diff
+// cannot be made inline, so they are appended to the review body instead.
+// The review body carries the bot's marker, like SetComment's comments. The
+// review is of pr's head commit, which the diff is fetched for, even if the
+// pull request has been pushed to since.
+func (c GitHubClient) SubmitReview(ctx context.Context, pr *github.PullRequest, botName string, review Review) (*github.PullRequestReview, error) {
+	owner, repo := pr.GetBase().GetRepo().GetOwner().GetLogin(), pr.GetBase().GetRepo().GetName()
+	ctx, release, err := c.acquire(ctx, owner, repo, pr.GetNumber(), WriteOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var diff *PullRequestDiff
+	if len(review.Comments) > 0 {
+		if diff, err = c.GetPullRequestDiff(ctx, pr); err != nil {
+			return nil, err
+		}
+	}
+
+	var drafts []*github.DraftReviewComment
+	var outside []ReviewComment
+	for _, rc := range review.Comments {
+		side := rc.Side
+		if side == "" {
+			side = DiffSideRight
+		}
+		f, ok := diff.File(rc.Path)
+		hunk := -1
+		if ok {
+			hunk = f.hunkOf(rc.Line, side)
+		}
+		// Multi-line comments must fall within a single hunk.
+		if hunk < 0 || (rc.StartLine != 0 && rc.StartLine != rc.Line && f.hunkOf(rc.StartLine, side) != hunk) {
+			outside = append(outside, rc)
+			continue
+		}
+		draft := &github.DraftReviewComment{
+			Path: github.Ptr(rc.Path),
+			Line: github.Ptr(rc.Line),
+			Side: github.Ptr(string(side)),
+			Body: github.Ptr(rc.Body),
+		}
+		if rc.StartLine != 0 && rc.StartLine != rc.Line {
+			draft.StartLine = github.Ptr(rc.StartLine)
+			draft.StartSide = github.Ptr(string(side))
+		}
+		drafts = append(drafts, draft)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "<!-- bot:%s -->\n\n%s", botName, review.Body)
+	for _, rc := range outside {
+		lines := fmt.Sprintf("line %d", rc.Line)
+		if rc.StartLine != 0 && rc.StartLine != rc.Line {
+			lines = fmt.Sprintf("lines %d-%d", rc.StartLine, rc.Line)
+		}
+		fmt.Fprintf(&body, "\n\n**`%s`, %s:**\n\n%s", rc.Path, lines, rc.Body)
+	}
+	if len(outside) > 0 {
+		clog.FromContext(ctx).Infof("%d review comments on PR %d are outside the diff, adding them to the review body", len(outside), pr.GetNumber())
+	}
+
+	event := review.Event
+	if event == "" {
+		event = ReviewEventComment
+	}
+	req := &github.PullRequestReviewRequest{
+		Body:     github.Ptr(body.String()),
+		Event:    github.Ptr(string(event)),
+		Comments: drafts,
+	}
+	// Pin the review to the commit the diff was fetched for, so comments
+	// land on the lines checked against it. Without a head SHA, GitHub
+	// reviews the latest commit.
+	if sha := pr.GetHead().GetSHA(); sha != "" {
+		req.CommitID = github.Ptr(sha)
+	}
+	r, resp, err := c.inner.PullRequests.CreateReview(ctx, owner, repo, pr.GetNumber(), req)
+	if err := validateResponse(ctx, err, resp, fmt.Sprintf("submit review of pull request %d", pr.GetNumber())); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
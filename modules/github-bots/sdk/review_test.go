@@ -0,0 +1,229 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v88/github"
+)
+
+const testPatch = `@@ -1,4 +1,5 @@ package main
+ package main
+ 
+-import "fmt"
++import (
++	"fmt"
++)
+ 
+@@ -20,3 +21,3 @@ func main() {
+ 	fmt.Println("a")
+-	fmt.Println("b")
++	fmt.Println("c")
+\ No newline at end of file`
+
+func TestParsePatch(t *testing.T) {
+	hunks, err := ParsePatch(testPatch)
+	if err != nil {
+		t.Fatalf("ParsePatch: %v", err)
+	}
+	want := []DiffHunk{{
+		OldStart: 1, OldLines: 4, NewStart: 1, NewLines: 5,
+		Section: "package main",
+		Lines: []DiffLine{
+			{Kind: DiffLineContext, OldLine: 1, NewLine: 1, Position: 1, Content: "package main"},
+			{Kind: DiffLineContext, OldLine: 2, NewLine: 2, Position: 2},
+			{Kind: DiffLineDeleted, OldLine: 3, Position: 3, Content: `import "fmt"`},
+			{Kind: DiffLineAdded, NewLine: 3, Position: 4, Content: "import ("},
+			{Kind: DiffLineAdded, NewLine: 4, Position: 5, Content: "\t\"fmt\""},
+			{Kind: DiffLineAdded, NewLine: 5, Position: 6, Content: ")"},
+			{Kind: DiffLineContext, OldLine: 4, NewLine: 6, Position: 7},
+		},
+	}, {
+		OldStart: 20, OldLines: 3, NewStart: 21, NewLines: 3,
+		Section: "func main() {",
+		Lines: []DiffLine{
+			{Kind: DiffLineContext, OldLine: 20, NewLine: 21, Position: 9, Content: "\tfmt.Println(\"a\")"},
+			{Kind: DiffLineDeleted, OldLine: 21, Position: 10, Content: "\tfmt.Println(\"b\")"},
+			{Kind: DiffLineAdded, NewLine: 22, Position: 11, Content: "\tfmt.Println(\"c\")"},
+		},
+	}}
+	if diff := cmp.Diff(want, hunks); diff != "" {
+		t.Errorf("ParsePatch() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := ParsePatch("not a patch"); err == nil {
+		t.Error("ParsePatch() = nil error for invalid patch")
+	}
+	if hunks, err := ParsePatch(""); err != nil || len(hunks) != 0 {
+		t.Errorf("ParsePatch(\"\") = %v, %v, want no hunks", hunks, err)
+	}
+}
+
+func TestFileDiff_Commentable(t *testing.T) {
+	hunks, err := ParsePatch(testPatch)
+	if err != nil {
+		t.Fatalf("ParsePatch: %v", err)
+	}
+	f := FileDiff{Path: "main.go", Hunks: hunks}
+
+	for _, tt := range []struct {
+		line int
+		side DiffSide
+		want bool
+	}{
+		{1, DiffSideRight, true},
+		{4, DiffSideRight, true},
+		{6, DiffSideRight, true},
+		{7, DiffSideRight, false},
+		{22, DiffSideRight, true},
+		{23, DiffSideRight, false},
+		{3, DiffSideLeft, true},
+		{21, DiffSideLeft, true},
+		{5, DiffSideLeft, false},
+	} {
+		if got := f.Commentable(tt.line, tt.side); got != tt.want {
+			t.Errorf("Commentable(%d, %s) = %v, want %v", tt.line, tt.side, got, tt.want)
+		}
+	}
+}
+
+func TestSubmitReview(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	pr := srv.AddPullRequest("acme", "widgets", &github.PullRequest{})
+	srv.SetPullRequestFiles("acme", "widgets", pr.GetNumber(), []*github.CommitFile{{
+		Filename: github.Ptr("main.go"),
+		Status:   github.Ptr("modified"),
+		Patch:    github.Ptr(testPatch),
+	}, {
+		Filename: github.Ptr("logo.png"),
+		Status:   github.Ptr("added"),
+	}})
+	c := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+
+	r, err := c.SubmitReview(ctx, pr, "test-bot", Review{
+		Event: ReviewEventRequestChanges,
+		Body:  "Please fix these.",
+		Comments: []ReviewComment{{
+			Path: "main.go", Line: 3, StartLine: 5, Body: "inline multi-line",
+		}, {
+			Path: "main.go", Line: 21, Side: DiffSideLeft, Body: "inline left",
+		}, {
+			Path: "main.go", Line: 40, Body: "outside the hunks",
+		}, {
+			Path: "main.go", Line: 22, StartLine: 5, Body: "across hunks",
+		}, {
+			Path: "logo.png", Line: 1, Body: "binary file",
+		}, {
+			Path: "README.md", Line: 1, Body: "unchanged file",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+	if got, want := r.GetState(), "CHANGES_REQUESTED"; got != want {
+		t.Errorf("review state = %q, want %q", got, want)
+	}
+
+	reviews := srv.Reviews("acme", "widgets", pr.GetNumber())
+	if len(reviews) != 1 {
+		t.Fatalf("got %d reviews, want 1", len(reviews))
+	}
+	body := reviews[0].GetBody()
+	if !strings.HasPrefix(body, "<!-- bot:test-bot -->\n\nPlease fix these.") {
+		t.Errorf("review body does not start with the bot marker and body:\n%s", body)
+	}
+	for _, want := range []string{
+		"**`main.go`, line 40:**\n\noutside the hunks",
+		"**`main.go`, lines 5-22:**\n\nacross hunks",
+		"**`logo.png`, line 1:**\n\nbinary file",
+		"**`README.md`, line 1:**\n\nunchanged file",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("review body does not contain %q:\n%s", want, body)
+		}
+	}
+
+	type inline struct {
+		Path            string
+		StartLine, Line int
+		Side, Body      string
+	}
+	var got []inline
+	for _, rc := range srv.ReviewComments("acme", "widgets", pr.GetNumber()) {
+		got = append(got, inline{rc.GetPath(), rc.GetStartLine(), rc.GetLine(), rc.GetSide(), rc.GetBody()})
+	}
+	want := []inline{
+		{"main.go", 5, 3, "RIGHT", "inline multi-line"},
+		{"main.go", 0, 21, "LEFT", "inline left"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("review comments mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// pushTransport simulates a push to a pull request by calling push once the
+// first read, such as fetching its diff, has been answered.
+type pushTransport struct {
+	push   func()
+	pushed bool
+}
+
+func (t *pushTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil && !t.pushed && req.Method == http.MethodGet {
+		t.pushed = true
+		t.push()
+	}
+	return resp, err
+}
+
+func TestSubmitReview_HeadMoves(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	stored := srv.AddPullRequest("acme", "widgets", &github.PullRequest{
+		Base: &github.PullRequestBranch{Ref: github.Ptr("main"), SHA: github.Ptr("base")},
+		Head: &github.PullRequestBranch{Ref: github.Ptr("feature"), SHA: github.Ptr("head1")},
+	})
+	// The bot handles the event of head1, as delivered in its payload.
+	pr := *stored
+	srv.SetComparison("acme", "widgets", "base", "head1", &github.CommitsComparison{
+		Files: []*github.CommitFile{{
+			Filename: github.Ptr("main.go"),
+			Status:   github.Ptr("modified"),
+			Patch:    github.Ptr(testPatch),
+		}},
+	})
+	// The pushed commit reverts main.go.
+	srv.SetPullRequestFiles("acme", "widgets", pr.GetNumber(), []*github.CommitFile{})
+
+	// The push lands once the diff has been fetched.
+	tr := &pushTransport{push: func() {
+		srv.SetPullRequestHead("acme", "widgets", pr.GetNumber(), "head2")
+	}}
+	c := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client(github.WithTransport(tr))))
+
+	if _, err := c.SubmitReview(ctx, &pr, "test-bot", Review{
+		Comments: []ReviewComment{{Path: "main.go", Line: 3, Body: "inline"}},
+	}); err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+
+	reviews := srv.Reviews("acme", "widgets", pr.GetNumber())
+	if len(reviews) != 1 || reviews[0].GetCommitID() != "head1" {
+		t.Fatalf("reviews = %v, want one of head1", reviews)
+	}
+	comments := srv.ReviewComments("acme", "widgets", pr.GetNumber())
+	if len(comments) != 1 || comments[0].GetCommitID() != "head1" || comments[0].GetLine() != 3 {
+		t.Errorf("review comments = %v, want one on line 3 of head1", comments)
+	}
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/google/go-github/v88/github"
+	"github.com/jonboulle/clockwork"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+// staleEventsDropped tracks events dropped because a newer version of their
+// resource was already processed
+var staleEventsDropped = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "github_bots_stale_events_dropped_total",
+		Help: "Total number of events dropped because a newer version of their resource was already processed",
+	},
+	[]string{"event_type"},
+)
+
+// StaleEventStore records the latest version seen of each resource.
+type StaleEventStore interface {
+	// Advance records version as the latest version of key unless a newer
+	// one is already recorded, and reports whether version is at least as
+	// new as the recorded one.
+	Advance(ctx context.Context, key string, version time.Time) (bool, error)
+}
+
+// StaleEventGuard drops events whose payload is older than a payload already
+// processed for the same resource, as happens when Pub/Sub redelivers or
+// reorders events. Resources are versioned by their updated_at timestamp.
+//
+// Wrap the handlers that should drop stale events, for example:
+//
+//	guard := sdk.NewStaleEventGuard(sdk.NewMemoryStaleEventStore())
+//	bot := sdk.NewBot("my-bot", sdk.BotWithHandler(guard.PullRequestHandler(handlePR)))
+//
+// Equal versions are not considered stale, so handlers still see redelivered
+// events and must remain idempotent. Events are handled as usual if the store
+// fails.
+type StaleEventGuard struct {
+	store StaleEventStore
+}
+
+// NewStaleEventGuard returns a StaleEventGuard that records versions in store.
+func NewStaleEventGuard(store StaleEventStore) *StaleEventGuard {
+	return &StaleEventGuard{store: store}
+}
+
+// Fresh records version as the latest version of key and reports whether an
+// event carrying it should be handled. A zero version is always fresh.
+func (g *StaleEventGuard) Fresh(ctx context.Context, etype EventType, key string, version time.Time) bool {
+	if version.IsZero() {
+		return true
+	}
+	fresh, err := g.store.Advance(ctx, key, version)
+	if err != nil {
+		clog.WarnContextf(ctx, "failed to check whether %s event for %s is stale, handling it: %v", etype, key, err)
+		return true
+	}
+	if !fresh {
+		clog.InfoContextf(ctx, "dropping stale %s event for %s at %s", etype, key, version.Format(time.RFC3339))
+		staleEventsDropped.WithLabelValues(string(etype)).Inc()
+	}
+	return fresh
+}
+
+// guardHandler wraps h so that it only sees events that are fresh according
+// to version, which returns the resource key and version of an event.
+func guardHandler[E any](g *StaleEventGuard, etype EventType, version func(E) (string, time.Time), h func(context.Context, E) error) func(context.Context, E) error {
+	return func(ctx context.Context, event E) error {
+		key, v := version(event)
+		if !g.Fresh(ctx, etype, key, v) {
+			return nil
+		}
+		return h(ctx, event)
+	}
+}
+
+// PullRequestHandler wraps h to drop stale pull request events, versioned by
+// the pull request's updated_at.
+func (g *StaleEventGuard) PullRequestHandler(h PullRequestHandler) PullRequestHandler {
+	return guardHandler(g, PullRequestEvent, func(pre github.PullRequestEvent) (string, time.Time) {
+		return fmt.Sprintf("pull_request:%s#%d", pre.GetRepo().GetFullName(), pre.GetNumber()), pre.GetPullRequest().GetUpdatedAt().Time
+	}, h)
+}
+
+// IssuesHandler wraps h to drop stale issue events, versioned by the issue's
+// updated_at.
+func (g *StaleEventGuard) IssuesHandler(h IssuesHandler) IssuesHandler {
+	return guardHandler(g, IssuesEvent, func(ie github.IssueEvent) (string, time.Time) {
+		return fmt.Sprintf("issue:%s#%d", ie.GetRepository().GetFullName(), ie.GetIssue().GetNumber()), ie.GetIssue().GetUpdatedAt().Time
+	}, h)
+}
+
+// IssueCommentHandler wraps h to drop stale issue comment events, versioned
+// by the comment's updated_at.
+func (g *StaleEventGuard) IssueCommentHandler(h IssueCommentHandler) IssueCommentHandler {
+	return guardHandler(g, IssueCommentEvent, func(ice github.IssueCommentEvent) (string, time.Time) {
+		return fmt.Sprintf("issue_comment:%s:%d", ice.GetRepo().GetFullName(), ice.GetComment().GetID()), ice.GetComment().GetUpdatedAt().Time
+	}, h)
+}
+
+// CheckSuiteHandler wraps h to drop stale check suite events, versioned by
+// the check suite's updated_at.
+func (g *StaleEventGuard) CheckSuiteHandler(h CheckSuiteHandler) CheckSuiteHandler {
+	return guardHandler(g, CheckSuiteEvent, func(cse github.CheckSuiteEvent) (string, time.Time) {
+		return fmt.Sprintf("check_suite:%s:%d", cse.GetRepo().GetFullName(), cse.GetCheckSuite().GetID()), cse.GetCheckSuite().GetUpdatedAt().Time
+	}, h)
+}
+
+// CheckRunHandler wraps h to drop stale check run events. Check runs have no
+// updated_at, so they are versioned by completed_at, or started_at until they
+// complete.
+func (g *StaleEventGuard) CheckRunHandler(h CheckRunHandler) CheckRunHandler {
+	return guardHandler(g, CheckRunEvent, func(cre github.CheckRunEvent) (string, time.Time) {
+		cr := cre.GetCheckRun()
+		v := cr.GetStartedAt().Time
+		if t := cr.GetCompletedAt().Time; t.After(v) {
+			v = t
+		}
+		return fmt.Sprintf("check_run:%s:%d", cre.GetRepo().GetFullName(), cr.GetID()), v
+	}, h)
+}
+
+// MemoryStaleEventStore is a StaleEventStore held in process memory. Use
+// ValkeyStaleEventStore to share versions across replicas.
+type MemoryStaleEventStore struct {
+	ttl   time.Duration
+	clock clockwork.Clock
+
+	mu        sync.Mutex
+	versions  map[string]memoryStaleEntry
+	lastPrune time.Time
+}
+
+type memoryStaleEntry struct {
+	version time.Time
+	seen    time.Time
+}
+
+// NewMemoryStaleEventStore returns an empty MemoryStaleEventStore. Versions
+// not advanced for 24 hours are forgotten.
+func NewMemoryStaleEventStore() *MemoryStaleEventStore {
+	clock := clockwork.NewRealClock()
+	return &MemoryStaleEventStore{
+		ttl:       24 * time.Hour,
+		clock:     clock,
+		versions:  make(map[string]memoryStaleEntry),
+		lastPrune: clock.Now(),
+	}
+}
+
+// Advance implements StaleEventStore.
+func (s *MemoryStaleEventStore) Advance(_ context.Context, key string, version time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if now.Sub(s.lastPrune) > s.ttl {
+		for k, e := range s.versions {
+			if now.Sub(e.seen) > s.ttl {
+				delete(s.versions, k)
+			}
+		}
+		s.lastPrune = now
+	}
+
+	if e, ok := s.versions[key]; ok && version.Before(e.version) {
+		return false, nil
+	}
+	s.versions[key] = memoryStaleEntry{version: version, seen: now}
+	return true, nil
+}
+
+// advanceScript sets KEYS[1] to ARGV[1] unless it holds a larger value, and
+// returns whether it did.
+var advanceScript = redis.NewScript(`
+local cur = redis.call('GET', KEYS[1])
+if cur and tonumber(cur) > tonumber(ARGV[1]) then
+  return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
+// ValkeyStaleEventStore is a StaleEventStore held in Valkey, so that every
+// replica of a bot sees the same versions. Dial the client with
+// valkey.NewClient.
+type ValkeyStaleEventStore struct {
+	client redis.UniversalClient
+	prefix string
+	ttl    time.Duration
+}
+
+// NewValkeyStaleEventStore returns a ValkeyStaleEventStore that stores
+// versions under keys prefixed with prefix, for example the bot's name.
+// Versions not advanced for 24 hours expire.
+func NewValkeyStaleEventStore(client redis.UniversalClient, prefix string) *ValkeyStaleEventStore {
+	return &ValkeyStaleEventStore{
+		client: client,
+		prefix: prefix,
+		ttl:    24 * time.Hour,
+	}
+}
+
+// Advance implements StaleEventStore.
+func (s *ValkeyStaleEventStore) Advance(ctx context.Context, key string, version time.Time) (bool, error) {
+	res, err := advanceScript.Run(ctx, s.client,
+		[]string{s.prefix + ":stale:" + key},
+		strconv.FormatInt(version.UnixMilli(), 10),
+		strconv.FormatInt(s.ttl.Milliseconds(), 10),
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to advance version of %s: %w", key, err)
+	}
+	return res == 1, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v88/github"
+	"github.com/jonboulle/clockwork"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type failingStaleEventStore struct{}
+
+func (failingStaleEventStore) Advance(context.Context, string, time.Time) (bool, error) {
+	return false, errors.New("unavailable")
+}
+
+func TestStaleEventGuard_PullRequestHandler(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	event := func(number int, updated time.Time) github.PullRequestEvent {
+		return github.PullRequestEvent{
+			Number: github.Ptr(number),
+			Repo:   &github.Repository{FullName: github.Ptr("acme/widgets")},
+			PullRequest: &github.PullRequest{
+				Number:    github.Ptr(number),
+				UpdatedAt: &github.Timestamp{Time: updated},
+			},
+		}
+	}
+
+	var handled []time.Time
+	guard := NewStaleEventGuard(NewMemoryStaleEventStore())
+	h := guard.PullRequestHandler(func(_ context.Context, pre github.PullRequestEvent) error {
+		handled = append(handled, pre.GetPullRequest().GetUpdatedAt().Time)
+		return nil
+	})
+
+	dropped := testutil.ToFloat64(staleEventsDropped.WithLabelValues(string(PullRequestEvent)))
+	for _, pre := range []github.PullRequestEvent{
+		event(1, base.Add(time.Minute)),
+		event(1, base),                    // stale
+		event(1, base.Add(time.Minute)),   // redelivered
+		event(2, base),                    // another pull request
+		event(1, base.Add(2*time.Minute)), // newer
+		{Number: github.Ptr(1), Repo: &github.Repository{FullName: github.Ptr("acme/widgets")}}, // unversioned
+	} {
+		if err := h(ctx, pre); err != nil {
+			t.Fatalf("handler: %v", err)
+		}
+	}
+
+	if len(handled) != 5 {
+		t.Errorf("handled %d events, want 5: %v", len(handled), handled)
+	}
+	if got := testutil.ToFloat64(staleEventsDropped.WithLabelValues(string(PullRequestEvent))) - dropped; got != 1 {
+		t.Errorf("dropped %v events, want 1", got)
+	}
+}
+
+func TestStaleEventGuard_FailsOpen(t *testing.T) {
+	guard := NewStaleEventGuard(failingStaleEventStore{})
+	if !guard.Fresh(context.Background(), PushEvent, "key", time.Now()) {
+		t.Error("Fresh() = false with a failing store, want true")
+	}
+}
+
+func TestMemoryStaleEventStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+	s := NewMemoryStaleEventStore()
+	s.clock, s.lastPrune = clock, clock.Now()
+
+	now := clock.Now()
+	if fresh, _ := s.Advance(ctx, "a", now); !fresh {
+		t.Fatal("first version is stale")
+	}
+	if fresh, _ := s.Advance(ctx, "a", now.Add(-time.Hour)); fresh {
+		t.Error("older version is fresh")
+	}
+
+	// Once forgotten, any version is fresh again.
+	clock.Advance(25 * time.Hour)
+	if fresh, _ := s.Advance(ctx, "b", now); !fresh {
+		t.Fatal("first version is stale")
+	}
+	if fresh, _ := s.Advance(ctx, "a", now.Add(-time.Hour)); !fresh {
+		t.Error("version of expired key is stale")
+	}
+}
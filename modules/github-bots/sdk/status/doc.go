@@ -0,0 +1,22 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package status provides utilities for creating GitHub commit statuses, the
+// alternative to check runs for bots that are not GitHub Apps, such as those
+// authenticating with octo-sts tokens.
+//
+// Use [NewBuilder] to construct a status, set its [Builder.State],
+// [Builder.Description] and [Builder.TargetURL], then create it with [Set],
+// which skips statuses identical to the latest one of the same context.
+// [Combined] reads the combined status of a commit.
+//
+// [StateFor] maps check run conclusions onto status states, and [FromCheck]
+// converts a check.Builder into a status Builder. A [Reporter] reports
+// check.Builder results as check runs, or as commit statuses with
+// [WithCommitStatuses], so bots can switch between the two with one option.
+//
+// Descriptions are automatically truncated to GitHub's maximum of 140
+// characters.
+package status
@@ -0,0 +1,53 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status_test
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/check"
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/status"
+)
+
+func ExampleNewBuilder() {
+	b := status.NewBuilder("my-status", "abc123")
+	b.State = status.StateSuccess
+	b.Description = "All checks passed"
+
+	st := b.RepoStatus()
+	fmt.Println(st.GetContext())
+	fmt.Println(st.GetState())
+	fmt.Println(st.GetDescription())
+	// Output:
+	// my-status
+	// success
+	// All checks passed
+}
+
+func ExampleBuilder_RepoStatus() {
+	b := status.NewBuilder("my-status", "abc123")
+	b.Description = strings.Repeat("x", 200)
+
+	fmt.Println(utf8.RuneCountInString(b.RepoStatus().GetDescription()))
+	// Output: 140
+}
+
+func ExampleFromCheck() {
+	cb := check.NewBuilder("my-check", "abc123")
+	cb.Conclusion = check.ConclusionTimedOut
+	cb.Summary = "Tests timed out"
+
+	st := status.FromCheck(cb).RepoStatus()
+	fmt.Println(st.GetContext())
+	fmt.Println(st.GetState())
+	fmt.Println(st.GetDescription())
+	// Output:
+	// my-check
+	// error
+	// Tests timed out
+}
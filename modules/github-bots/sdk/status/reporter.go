@@ -0,0 +1,90 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk"
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/check"
+	"github.com/google/go-github/v88/github"
+)
+
+// Reporter reports results built with check.Builder to GitHub, as check runs
+// or, with WithCommitStatuses, as commit statuses. Bots build their results
+// the same way either way, so switching between the two takes one option.
+type Reporter struct {
+	gh          sdk.GitHubClient
+	owner, repo string
+	statuses    bool
+	targetURL   string
+}
+
+// ReporterOption configures a Reporter.
+type ReporterOption func(*Reporter)
+
+// WithCommitStatuses makes the Reporter create commit statuses rather than
+// check runs. Only GitHub Apps can create check runs, so bots authenticating
+// with other tokens, such as those from octo-sts, need it.
+func WithCommitStatuses() ReporterOption {
+	return func(r *Reporter) {
+		r.statuses = true
+	}
+}
+
+// WithTargetURL sets the URL results link to: the target URL of statuses, or
+// the details URL of check runs.
+func WithTargetURL(url string) ReporterOption {
+	return func(r *Reporter) {
+		r.targetURL = url
+	}
+}
+
+// NewReporter returns a Reporter for owner/repo.
+func NewReporter(gh sdk.GitHubClient, owner, repo string, opts ...ReporterOption) *Reporter {
+	r := &Reporter{gh: gh, owner: owner, repo: repo}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Report creates or updates the check run, or the commit status, for the
+// current state of b.
+func (r *Reporter) Report(ctx context.Context, b *check.Builder) error {
+	if r.statuses {
+		sb := FromCheck(b)
+		sb.TargetURL = r.targetURL
+		_, err := Set(ctx, r.gh, r.owner, r.repo, sb)
+		return err
+	}
+
+	create := b.CheckRunCreate()
+	runs, _, err := r.gh.Client().Checks.ListCheckRunsForRef(ctx, r.owner, r.repo, b.HeadSHA(), &github.ListCheckRunsOptions{
+		CheckName: github.Ptr(create.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list check runs %q on %s: %w", create.Name, b.HeadSHA(), err)
+	}
+	if len(runs.CheckRuns) > 0 {
+		update := b.CheckRunUpdate()
+		if r.targetURL != "" {
+			update.DetailsURL = github.Ptr(r.targetURL)
+		}
+		if _, _, err := r.gh.Client().Checks.UpdateCheckRun(ctx, r.owner, r.repo, runs.CheckRuns[0].GetID(), *update); err != nil {
+			return fmt.Errorf("failed to update check run %q on %s: %w", create.Name, b.HeadSHA(), err)
+		}
+		return nil
+	}
+	if r.targetURL != "" {
+		create.DetailsURL = github.Ptr(r.targetURL)
+	}
+	if _, _, err := r.gh.Client().Checks.CreateCheckRun(ctx, r.owner, r.repo, *create); err != nil {
+		return fmt.Errorf("failed to create check run %q on %s: %w", create.Name, b.HeadSHA(), err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk"
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/check"
+	"github.com/google/go-github/v88/github"
+)
+
+// Docs for Commit Status API: https://docs.github.com/en/rest/commits/statuses?apiVersion=2022-11-28
+
+const maxDescriptionLength = 140
+
+// State is the state of a commit status.
+type State string
+
+const (
+	StateError   State = "error"
+	StateFailure State = "failure"
+	StatePending State = "pending"
+	StateSuccess State = "success"
+)
+
+// StateFor returns the commit status state matching a check run conclusion.
+// Check runs without a conclusion are pending. Conclusions that pass a
+// required check are successes, cancelled and timed out runs are errors, and
+// the rest are failures.
+func StateFor(conclusion check.Conclusion) State {
+	switch conclusion {
+	case "":
+		return StatePending
+	case check.ConclusionSuccess, check.ConclusionNeutral:
+		return StateSuccess
+	case check.ConclusionCancelled, check.ConclusionTimedOut:
+		return StateError
+	default:
+		// Like a skipped check run, a skipped status must not pass a
+		// required check.
+		return StateFailure
+	}
+}
+
+// Builder builds a commit status, like check.Builder builds a check run.
+type Builder struct {
+	context, sha string
+	State        State
+	// Description is truncated to GitHub's limit of 140 characters.
+	Description string
+	// TargetURL is the URL the status links to, such as the logs of the run
+	// that produced it.
+	TargetURL string
+}
+
+// NewBuilder returns a Builder for the status named context on the commit
+// sha. The status is pending until its State is set.
+func NewBuilder(context, sha string) *Builder {
+	return &Builder{
+		context: context,
+		sha:     sha,
+		State:   StatePending,
+	}
+}
+
+// NewMergeGroupBuilder returns a Builder for a status on the head commit of
+// a merge queue's merge group. Required statuses must report on this commit,
+// or the merge queue waits for them until it times out.
+func NewMergeGroupBuilder(context string, mge github.MergeGroupEvent) *Builder {
+	return NewBuilder(context, mge.GetMergeGroup().GetHeadSHA())
+}
+
+// FromCheck returns a Builder for the status equivalent to the check run b
+// builds: its context is the check run's name, its state follows from the
+// check run's conclusion, and its description is the summary.
+func FromCheck(b *check.Builder) *Builder {
+	cr := b.CheckRunCreate()
+	sb := NewBuilder(cr.Name, b.HeadSHA())
+	sb.State = StateFor(b.Conclusion)
+	sb.Description = cr.GetOutput().GetSummary()
+	return sb
+}
+
+// Context returns the name of the status.
+func (b *Builder) Context() string {
+	return b.context
+}
+
+// SHA returns the SHA of the commit the status reports on.
+func (b *Builder) SHA() string {
+	return b.sha
+}
+
+// RepoStatus returns the GitHub RepoStatus to create for the current state of
+// the Builder.
+func (b *Builder) RepoStatus() *github.RepoStatus {
+	st := &github.RepoStatus{
+		State:   github.Ptr(string(b.State)),
+		Context: github.Ptr(b.context),
+	}
+	if b.Description != "" {
+		st.Description = github.Ptr(truncate(b.Description))
+	}
+	if b.TargetURL != "" {
+		st.TargetURL = github.Ptr(b.TargetURL)
+	}
+	return st
+}
+
+// truncate shortens s to maxDescriptionLength characters, marking the cut
+// with an ellipsis.
+func truncate(s string) string {
+	r := []rune(s)
+	if len(r) <= maxDescriptionLength {
+		return s
+	}
+	return string(r[:maxDescriptionLength-1]) + "…"
+}
+
+// Set creates the status built by b on owner/repo, unless the latest status
+// with the same context already matches it. GitHub keeps every status created
+// and allows at most 1000 per commit and context, so bots that report on
+// every event should not repeat themselves.
+func Set(ctx context.Context, gh sdk.GitHubClient, owner, repo string, b *Builder) (*github.RepoStatus, error) {
+	want := b.RepoStatus()
+	combined, err := Combined(ctx, gh, owner, repo, b.sha)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range combined.Statuses {
+		if st.GetContext() == b.context &&
+			st.GetState() == want.GetState() &&
+			st.GetDescription() == want.GetDescription() &&
+			st.GetTargetURL() == want.GetTargetURL() {
+			return st, nil
+		}
+	}
+
+	st, resp, err := gh.Client().Repositories.CreateStatus(ctx, owner, repo, b.sha, *want)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create status %q on %s: %w", b.context, b.sha, err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create status %q on %s: %s", b.context, b.sha, resp.Status)
+	}
+	return st, nil
+}
+
+// Combined returns the combined status of ref in owner/repo: the latest
+// status of each context, and their overall state. The state is failure if
+// any status is an error or a failure, pending if any is pending or there
+// are none, and success otherwise.
+func Combined(ctx context.Context, gh sdk.GitHubClient, owner, repo, ref string) (*github.CombinedStatus, error) {
+	var combined *github.CombinedStatus
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := gh.Client().Repositories.GetCombinedStatus(ctx, owner, repo, ref, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get combined status of %s: %w", ref, err)
+		}
+		if combined == nil {
+			combined = page
+		} else {
+			combined.Statuses = append(combined.Statuses, page.Statuses...)
+		}
+		if resp.NextPage == 0 {
+			return combined, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk"
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/check"
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+)
+
+func TestStateFor(t *testing.T) {
+	for _, tt := range []struct {
+		conclusion check.Conclusion
+		want       State
+	}{
+		{"", StatePending},
+		{check.ConclusionSuccess, StateSuccess},
+		{check.ConclusionNeutral, StateSuccess},
+		{check.ConclusionFailure, StateFailure},
+		{check.ConclusionActionRequired, StateFailure},
+		{check.ConclusionSkipped, StateFailure},
+		{check.ConclusionCancelled, StateError},
+		{check.ConclusionTimedOut, StateError},
+	} {
+		if got := StateFor(tt.conclusion); got != tt.want {
+			t.Errorf("StateFor(%q) = %q, want %q", tt.conclusion, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	short := strings.Repeat("é", maxDescriptionLength)
+	if got := truncate(short); got != short {
+		t.Errorf("truncate(%d runes) = %q, want it unchanged", maxDescriptionLength, got)
+	}
+	got := truncate(short + "é")
+	if n := utf8.RuneCountInString(got); n != maxDescriptionLength {
+		t.Errorf("truncate(%d runes) has %d runes, want %d", maxDescriptionLength+1, n, maxDescriptionLength)
+	}
+	if !strings.HasSuffix(got, "…") {
+		t.Errorf("truncate() = %q, want it to end with an ellipsis", got)
+	}
+}
+
+func TestSet(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	gh := sdk.NewGitHubClient(ctx, "acme", "widgets", "test", sdk.WithClient(srv.Client()))
+
+	b := NewBuilder("lint", "abc123")
+	b.Description = "Linting"
+	if _, err := Set(ctx, gh, "acme", "widgets", b); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	// An identical status isn't created again.
+	if _, err := Set(ctx, gh, "acme", "widgets", b); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	srv.AssertRequestCount(http.MethodPost, "/repos/acme/widgets/statuses/abc123", 1)
+
+	b.State, b.Description = StateFailure, "2 files need formatting"
+	if _, err := Set(ctx, gh, "acme", "widgets", b); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	st := srv.AssertStatus("acme", "widgets", "abc123", "lint", "failure")
+	if got, want := st.GetDescription(), "2 files need formatting"; got != want {
+		t.Errorf("description = %q, want %q", got, want)
+	}
+
+	other := NewBuilder("test", "abc123")
+	other.State = StateSuccess
+	if _, err := Set(ctx, gh, "acme", "widgets", other); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	combined, err := Combined(ctx, gh, "acme", "widgets", "abc123")
+	if err != nil {
+		t.Fatalf("Combined: %v", err)
+	}
+	if got, want := combined.GetState(), "failure"; got != want {
+		t.Errorf("combined state = %q, want %q", got, want)
+	}
+	if got, want := len(combined.Statuses), 2; got != want {
+		t.Errorf("got %d statuses, want the latest of each of the %d contexts", got, want)
+	}
+}
+
+func TestReporter(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	gh := sdk.NewGitHubClient(ctx, "acme", "widgets", "test", sdk.WithClient(srv.Client()))
+
+	for _, r := range []*Reporter{
+		NewReporter(gh, "acme", "widgets"),
+		NewReporter(gh, "acme", "widgets", WithCommitStatuses(), WithTargetURL("https://example.com/logs")),
+	} {
+		b := check.NewBuilder("build", "abc123")
+		if err := r.Report(ctx, b); err != nil {
+			t.Fatalf("Report: %v", err)
+		}
+		b.Conclusion = check.ConclusionSuccess
+		if err := r.Report(ctx, b); err != nil {
+			t.Fatalf("Report: %v", err)
+		}
+	}
+
+	if got := srv.CheckRuns("acme", "widgets"); len(got) != 1 {
+		t.Errorf("got %d check runs, want the one updated", len(got))
+	}
+	srv.AssertCheckRun("acme", "widgets", "build", "success")
+	st := srv.AssertStatus("acme", "widgets", "abc123", "build", "success")
+	if got, want := st.GetTargetURL(), "https://example.com/logs"; got != want {
+		t.Errorf("target URL = %q, want %q", got, want)
+	}
+}
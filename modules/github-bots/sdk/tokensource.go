@@ -7,9 +7,12 @@ package sdk
 
 import (
 	"context"
+	"time"
 
 	"chainguard.dev/sdk/octosts"
 	"github.com/chainguard-dev/clog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"golang.org/x/oauth2"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -20,6 +23,80 @@ import (
 // network. Production code should not reassign this.
 var OctoTokenFunc = octosts.Token
 
+// OctoRevokeFunc is the function used to revoke Octo STS tokens. Like
+// OctoTokenFunc, it is exposed for tests and should not be reassigned by
+// production code.
+var OctoRevokeFunc = octosts.Revoke
+
+var (
+	// tokenMints tracks Octo STS token mint attempts
+	tokenMints = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "github_bots_octosts_token_mints_total",
+			Help: "Total number of Octo STS tokens minted",
+		},
+		[]string{"outcome"},
+	)
+
+	// tokenMintSeconds tracks Octo STS token mint latency
+	tokenMintSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "github_bots_octosts_token_mint_seconds",
+			Help:    "Latency of Octo STS token mints in seconds",
+			Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"outcome"},
+	)
+
+	// tokenRevocations tracks Octo STS token revocation attempts
+	tokenRevocations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "github_bots_octosts_token_revocations_total",
+			Help: "Total number of Octo STS tokens revoked",
+		},
+		[]string{"outcome"},
+	)
+
+	// tokenRevokeSeconds tracks Octo STS token revocation latency
+	tokenRevokeSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "github_bots_octosts_token_revoke_seconds",
+			Help:    "Latency of Octo STS token revocations in seconds",
+			Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"outcome"},
+	)
+)
+
+// mintToken mints an Octo STS token through OctoTokenFunc, recording its
+// outcome and latency.
+func mintToken(ctx context.Context, policyName, org, repo string) (string, error) {
+	start := time.Now()
+	tok, err := OctoTokenFunc(ctx, policyName, org, repo)
+	outcome := outcomeLabel(err)
+	tokenMints.WithLabelValues(outcome).Inc()
+	tokenMintSeconds.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+	return tok, err
+}
+
+// revokeToken revokes an Octo STS token through OctoRevokeFunc, recording
+// its outcome and latency.
+func revokeToken(ctx context.Context, tok string) error {
+	start := time.Now()
+	err := OctoRevokeFunc(ctx, tok)
+	outcome := outcomeLabel(err)
+	tokenRevocations.WithLabelValues(outcome).Inc()
+	tokenRevokeSeconds.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+	return err
+}
+
+func outcomeLabel(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
 // NewRepoTokenSource returns an oauth2.TokenSource that mints repo-scoped
 // tokens from Octo STS for the given (org, repo) using identity as the policy
 // name. The returned source caches valid tokens via oauth2.ReuseTokenSource.
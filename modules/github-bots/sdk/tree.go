@@ -0,0 +1,188 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v88/github"
+)
+
+// WalkTreeOption configures WalkTree.
+type WalkTreeOption func(*walkTreeConfig)
+
+type walkTreeConfig struct {
+	globs []string
+}
+
+// WithTreeGlob restricts WalkTree to the entries whose path matches one of
+// the patterns. Patterns use path.Match syntax per path element, and "**"
+// matches any number of elements, so "**/*.go" matches every Go file.
+func WithTreeGlob(patterns ...string) WalkTreeOption {
+	return func(c *walkTreeConfig) {
+		c.globs = append(c.globs, patterns...)
+	}
+}
+
+func (c walkTreeConfig) match(p string) bool {
+	if len(c.globs) == 0 {
+		return true
+	}
+	for _, g := range c.globs {
+		if matchGlob(strings.Split(g, "/"), strings.Split(p, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// WalkTree iterates over every entry of the tree of owner/repo at ref, a
+// branch, tag or commit SHA, using the Git Trees API. Entries carry their
+// path from the root of the repository; blobs are files, trees directories
+// and commits submodules.
+//
+// The tree is fetched recursively in a single request when GitHub allows it.
+// For trees too large for that, GitHub truncates the response, and WalkTree
+// fetches each subtree in turn instead.
+func (c GitHubClient) WalkTree(ctx context.Context, owner, repo, ref string, opts ...WalkTreeOption) iter.Seq2[*github.TreeEntry, error] {
+	var cfg walkTreeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(yield func(*github.TreeEntry, error) bool) {
+		if err := c.walkTree(ctx, owner, repo, ref, "", cfg, yield); err != nil && !errors.Is(err, errStopWalk) {
+			yield(nil, err)
+		}
+	}
+}
+
+// errStopWalk signals that the consumer of WalkTree stopped iterating.
+var errStopWalk = errors.New("walk stopped")
+
+// walkTree yields the entries of the tree sha, prefixing their paths with
+// prefix.
+func (c GitHubClient) walkTree(ctx context.Context, owner, repo, sha, prefix string, cfg walkTreeConfig, yield func(*github.TreeEntry, error) bool) error {
+	tree, err := c.getTree(ctx, owner, repo, sha, true)
+	if err != nil {
+		return err
+	}
+	if !tree.GetTruncated() {
+		for _, e := range tree.Entries {
+			if err := emit(e, prefix, cfg, yield); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Too large to list at once: list this level and descend into subtrees.
+	if tree, err = c.getTree(ctx, owner, repo, sha, false); err != nil {
+		return err
+	}
+	for _, e := range tree.Entries {
+		if err := emit(e, prefix, cfg, yield); err != nil {
+			return err
+		}
+		// emit has made e's path full.
+		if e.GetType() == "tree" {
+			if err := c.walkTree(ctx, owner, repo, e.GetSHA(), e.GetPath()+"/", cfg, yield); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// emit yields e with its full path if it matches the configured globs.
+func emit(e *github.TreeEntry, prefix string, cfg walkTreeConfig, yield func(*github.TreeEntry, error) bool) error {
+	if prefix != "" {
+		e.Path = github.Ptr(prefix + e.GetPath())
+	}
+	if !cfg.match(e.GetPath()) {
+		return nil
+	}
+	if !yield(e, nil) {
+		return errStopWalk
+	}
+	return nil
+}
+
+func (c GitHubClient) getTree(ctx context.Context, owner, repo, sha string, recursive bool) (*github.Tree, error) {
+	ctx, release, err := c.acquire(ctx, owner, repo, 0, ReadOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	tree, resp, err := c.inner.Git.GetTree(ctx, owner, repo, sha, recursive)
+	if err := validateResponse(ctx, err, resp, fmt.Sprintf("get tree %s of %s/%s", sha, owner, repo)); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// OpenBlob streams the content of the blob sha of owner/repo, such as a
+// blob entry yielded by WalkTree. Blobs of up to 100 MB can be read. The
+// caller must close the returned reader.
+func (c GitHubClient) OpenBlob(ctx context.Context, owner, repo, sha string) (io.ReadCloser, error) {
+	ctx, release, err := c.acquire(ctx, owner, repo, 0, ReadOperation)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.inner.NewRequest(ctx, "GET", fmt.Sprintf("repos/%s/%s/git/blobs/%s", owner, repo, sha), nil)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.raw")
+	resp, err := c.inner.BareDo(req)
+	if err := validateResponse(ctx, err, resp, fmt.Sprintf("get blob %s of %s/%s", sha, owner, repo)); err != nil {
+		release()
+		return nil, err
+	}
+	// The permit is held until the blob has been read.
+	return &blobReader{ReadCloser: resp.Body, release: release}, nil
+}
+
+type blobReader struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *blobReader) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
+
+// matchGlob reports whether the path elements name match the pattern
+// elements pattern, where "**" matches any number of elements.
+func matchGlob(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchGlob(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newTreeServer(t *testing.T) (*githubtest.Server, GitHubClient) {
+	srv := githubtest.NewServer(t)
+	for path, content := range map[string]string{
+		"README.md":            "# widgets",
+		"go.mod":               "module widgets",
+		"cmd/widget/main.go":   "package main",
+		"pkg/gears/gears.go":   "package gears",
+		"pkg/gears/README.md":  "# gears",
+		"pkg/sprockets/doc.go": "package sprockets",
+	} {
+		srv.SetFile("acme", "widgets", "main", path, content)
+	}
+	return srv, NewGitHubClient(context.Background(), "acme", "widgets", "test", WithClient(srv.Client()))
+}
+
+func walk(t *testing.T, gh GitHubClient, opts ...WalkTreeOption) []string {
+	t.Helper()
+	var got []string
+	for e, err := range gh.WalkTree(context.Background(), "acme", "widgets", "main", opts...) {
+		if err != nil {
+			t.Fatalf("WalkTree: %v", err)
+		}
+		got = append(got, e.GetType()+" "+e.GetPath())
+	}
+	return got
+}
+
+func TestWalkTree(t *testing.T) {
+	all := []string{
+		"blob README.md",
+		"tree cmd",
+		"tree cmd/widget",
+		"blob cmd/widget/main.go",
+		"blob go.mod",
+		"tree pkg",
+		"tree pkg/gears",
+		"blob pkg/gears/README.md",
+		"blob pkg/gears/gears.go",
+		"tree pkg/sprockets",
+		"blob pkg/sprockets/doc.go",
+	}
+
+	t.Run("recursive", func(t *testing.T) {
+		srv, gh := newTreeServer(t)
+		if diff := cmp.Diff(all, walk(t, gh)); diff != "" {
+			t.Errorf("WalkTree (-want, +got):\n%s", diff)
+		}
+		srv.AssertRequestCount("GET", "/repos/acme/widgets/git/trees/main", 1)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		srv, gh := newTreeServer(t)
+		srv.SetTreeLimit("acme", "widgets", 4)
+		// Subtrees are walked depth-first after their own entry, in the same
+		// order as a recursive listing.
+		if diff := cmp.Diff(all, walk(t, gh)); diff != "" {
+			t.Errorf("WalkTree (-want, +got):\n%s", diff)
+		}
+	})
+
+	t.Run("glob", func(t *testing.T) {
+		_, gh := newTreeServer(t)
+		got := walk(t, gh, WithTreeGlob("**/*.go", "README.md"))
+		want := []string{
+			"blob README.md",
+			"blob cmd/widget/main.go",
+			"blob pkg/gears/gears.go",
+			"blob pkg/sprockets/doc.go",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("WalkTree (-want, +got):\n%s", diff)
+		}
+	})
+
+	t.Run("stop early", func(t *testing.T) {
+		srv, gh := newTreeServer(t)
+		srv.SetTreeLimit("acme", "widgets", 4)
+		for range gh.WalkTree(context.Background(), "acme", "widgets", "main") {
+			break
+		}
+		// Only the truncated listing and the root level were fetched.
+		if n := len(srv.Requests()); n != 2 {
+			t.Errorf("made %d requests, want 2", n)
+		}
+	})
+
+	t.Run("missing ref", func(t *testing.T) {
+		_, gh := newTreeServer(t)
+		var errs int
+		for _, err := range gh.WalkTree(context.Background(), "acme", "widgets", "nope") {
+			if err == nil {
+				t.Fatal("got an entry, want an error")
+			}
+			errs++
+		}
+		if errs != 1 {
+			t.Errorf("got %d errors, want 1", errs)
+		}
+	})
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/widget/main.go", true},
+		{"pkg/**", "pkg/gears/gears.go", true},
+		{"pkg/**", "cmd/main.go", false},
+		{"pkg/**/doc.go", "pkg/doc.go", true},
+		{"pkg/**/doc.go", "pkg/a/b/doc.go", true},
+		{"pkg/?ears/*.go", "pkg/gears/gears.go", true},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(strings.Split(tt.pattern, "/"), strings.Split(tt.path, "/")); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestOpenBlob(t *testing.T) {
+	ctx := context.Background()
+	_, gh := newTreeServer(t)
+	for e, err := range gh.WalkTree(ctx, "acme", "widgets", "main", WithTreeGlob("go.mod")) {
+		if err != nil {
+			t.Fatalf("WalkTree: %v", err)
+		}
+		r, err := gh.OpenBlob(ctx, "acme", "widgets", e.GetSHA())
+		if err != nil {
+			t.Fatalf("OpenBlob: %v", err)
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("ReadAll: %v", err)
+		}
+		if err := r.Close(); err != nil {
+			t.Errorf("Close: %v", err)
+		}
+		if got, want := string(b), "module widgets"; got != want {
+			t.Errorf("blob = %q, want %q", got, want)
+		}
+	}
+
+	if _, err := gh.OpenBlob(ctx, "acme", "widgets", "0000"); err == nil {
+		t.Error("OpenBlob of a missing blob succeeded")
+	}
+}
+
+func TestGetFileContent_Large(t *testing.T) {
+	ctx := context.Background()
+	srv, gh := newTreeServer(t)
+	large := strings.Repeat("x", 2<<20)
+	srv.SetFile("acme", "widgets", "main", "data/large.bin", large)
+
+	got, err := gh.GetFileContent(ctx, "acme", "widgets", "data/large.bin", "main")
+	if err != nil {
+		t.Fatalf("GetFileContent: %v", err)
+	}
+	if got != large {
+		t.Errorf("GetFileContent returned %d bytes, want %d", len(got), len(large))
+	}
+	var blobs int
+	for _, r := range srv.Requests() {
+		if strings.HasPrefix(r.Path, "/repos/acme/widgets/git/blobs/") {
+			blobs++
+		}
+	}
+	if blobs != 1 {
+		t.Errorf("fetched %d blobs, want 1", blobs)
+	}
+}
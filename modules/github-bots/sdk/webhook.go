@@ -0,0 +1,101 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-events/webhook"
+	"github.com/chainguard-dev/terraform-infra-common/pkg/httpmetrics"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// ServeWebhook starts the bot's HTTP server receiving GitHub webhooks
+// directly, rather than CloudEvents from the broker, for small deployments and
+// local development that don't run github-events. Deliveries must be signed
+// with one of secrets.
+//
+// Each delivery is converted into the same CloudEvent the github-events
+// trampoline publishes, and dispatched to the bot's handlers as Serve does,
+// so handlers work unchanged in either mode. The port is configured as for
+// Serve.
+func ServeWebhook(b Bot, secrets [][]byte, opts ...ServeOption) {
+	cfg := newServeConfig(opts)
+
+	ctx := context.Background()
+
+	defer instrument(ctx)()
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.port),
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler:           httpmetrics.Handler(b.Name, b.webhookHandler(secrets, cfg)),
+	}
+	clog.FromContext(ctx).Infof("starting bot %s webhook receiver on port %d", b.Name, cfg.port)
+	clog.Fatalf("failed to serve webhooks: %v", srv.ListenAndServe())
+}
+
+// WebhookHandler returns the handler ServeWebhook serves, to mount it on an
+// existing server or test the bot end to end with signed deliveries.
+func WebhookHandler(b Bot, secrets [][]byte, opts ...ServeOption) http.Handler {
+	return b.webhookHandler(secrets, newServeConfig(opts))
+}
+
+func (b Bot) webhookHandler(secrets [][]byte, cfg *serveConfig) http.Handler {
+	receive := b.receiver(cfg)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log := clog.FromContext(r.Context())
+
+		payload, err := webhook.ValidatePayload(r, secrets)
+		if err != nil {
+			log.Errorf("failed to verify webhook: %v", err)
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprintf(w, "failed to verify webhook: %v", err)
+			return
+		}
+		event, err := webhook.NewEvent(r, payload, time.Now())
+		if err != nil {
+			log.Errorf("failed to convert webhook: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := wireExtensions(&event); err != nil {
+			log.Errorf("failed to convert webhook extensions: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// GitHub doesn't redeliver failed webhooks, but reports the status in
+		// the webhook's recent deliveries, where they can be redelivered by
+		// hand.
+		if err := receive(r.Context(), event); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "failed to handle event: %v", err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// wireExtensions replaces the extensions of event with their string form, as
+// handlers receive them from the broker over the HTTP binding, so that
+// AttributeFromContext returns the same values in either mode. For example,
+// the merged extension is the string "true" rather than a bool.
+func wireExtensions(event *cloudevents.Event) error {
+	for name, v := range event.Extensions() {
+		s, err := types.Format(v)
+		if err != nil {
+			return fmt.Errorf("formatting extension %s: %w", name, err)
+		}
+		event.SetExtension(name, s)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-events/webhook"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v88/github"
+)
+
+func deliver(t *testing.T, h http.Handler, eventType string, payload, secret []byte) int {
+	t.Helper()
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set(github.SHA256SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	r.Header.Set(github.EventTypeHeader, eventType)
+	r.Header.Set(github.DeliveryIDHeader, "5678")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestWebhookHandler(t *testing.T) {
+	secret := []byte("hunter2")
+	var got github.PullRequestEvent
+	var prURL, headBranch any
+	var fail error
+	bot := NewBot("test", BotWithHandler(PullRequestHandler(func(ctx context.Context, pre github.PullRequestEvent) error {
+		got = pre
+		prURL = AttributeFromContext(ctx, "pullrequesturl")
+		headBranch = AttributeFromContext(ctx, "headbranch")
+		return fail
+	})))
+	h := WebhookHandler(bot, [][]byte{secret})
+
+	payload := []byte(`{"action":"opened",` +
+		`"repository":{"full_name":"foo/bar","name":"bar","owner":{"login":"foo"}},` +
+		`"pull_request":{"number":42,"head":{"ref":"bot/update"}}}`)
+	if code := deliver(t, h, "pull_request", payload, secret); code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", code, http.StatusOK)
+	}
+	if got.GetAction() != "opened" || got.GetPullRequest().GetNumber() != 42 {
+		t.Errorf("handled %+v, want the opened pull request 42", got)
+	}
+	if prURL != "https://github.com/foo/bar/pull/42" || headBranch != "bot/update" {
+		t.Errorf("pullrequesturl, headbranch = %v, %v, want the trampoline's extensions", prURL, headBranch)
+	}
+
+	// Events without a handler are acknowledged.
+	if code := deliver(t, h, "push", []byte(`{}`), secret); code != http.StatusOK {
+		t.Errorf("unhandled event status = %d, want %d", code, http.StatusOK)
+	}
+
+	if code := deliver(t, h, "pull_request", payload, []byte("wrong")); code != http.StatusForbidden {
+		t.Errorf("badly signed delivery status = %d, want %d", code, http.StatusForbidden)
+	}
+
+	fail = errors.New("boom")
+	if code := deliver(t, h, "pull_request", payload, secret); code != http.StatusInternalServerError {
+		t.Errorf("failed handler status = %d, want %d", code, http.StatusInternalServerError)
+	}
+}
+
+func TestWebhookHandler_BrokerParity(t *testing.T) {
+	secret := []byte("hunter2")
+	keys := []string{"action", "pullrequesturl", "headbranch", "merged"}
+	var attrs map[string]any
+	bot := NewBot("test", BotWithHandler(PullRequestHandler(func(ctx context.Context, _ github.PullRequestEvent) error {
+		attrs = make(map[string]any, len(keys))
+		for _, k := range keys {
+			attrs[k] = AttributeFromContext(ctx, k)
+		}
+		return nil
+	})))
+
+	payload := []byte(`{"action":"closed",` +
+		`"repository":{"full_name":"foo/bar","name":"bar","owner":{"login":"foo"}},` +
+		`"pull_request":{"number":42,"merged":true,"head":{"ref":"bot/update"}}}`)
+	if code := deliver(t, WebhookHandler(bot, [][]byte{secret}), "pull_request", payload, secret); code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", code, http.StatusOK)
+	}
+	direct := attrs
+
+	// Deliver the same event as the trampoline would, through the broker's
+	// HTTP binding.
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+	r.Header.Set(github.EventTypeHeader, "pull_request")
+	r.Header.Set(github.DeliveryIDHeader, "5678")
+	event, err := webhook.NewEvent(r, payload, time.Now())
+	if err != nil {
+		t.Fatalf("NewEvent: %v", err)
+	}
+	ctx := context.Background()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	if err := cehttp.WriteRequest(ctx, binding.ToMessage(&event), req); err != nil {
+		t.Fatalf("WriteRequest: %v", err)
+	}
+	brokered, err := binding.ToEvent(ctx, cehttp.NewMessageFromHttpRequest(req))
+	if err != nil {
+		t.Fatalf("ToEvent: %v", err)
+	}
+	if err := bot.handleEvent(ctx, *brokered); err != nil {
+		t.Fatalf("handleEvent: %v", err)
+	}
+
+	if diff := cmp.Diff(attrs, direct); diff != "" {
+		t.Errorf("direct attributes differ from brokered ones (-brokered +direct):\n%s", diff)
+	}
+	if direct["merged"] != "true" {
+		t.Errorf("merged = %#v, want %q", direct["merged"], "true")
+	}
+}
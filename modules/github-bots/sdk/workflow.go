@@ -0,0 +1,259 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/chainguard-dev/clog"
+	"github.com/google/go-github/v88/github"
+)
+
+// DefaultCorrelationInput is the workflow input DispatchWorkflow passes its
+// correlation token in, unless WithCorrelationInput names another.
+const DefaultCorrelationInput = "correlation_id"
+
+// WorkflowOption configures DispatchWorkflow and WaitForWorkflowRun.
+type WorkflowOption func(*workflowConfig)
+
+type workflowConfig struct {
+	correlationInput string
+	lookupTimeout    time.Duration
+	minPoll, maxPoll time.Duration
+}
+
+// WithCorrelationInput sets the workflow input DispatchWorkflow passes its
+// correlation token in.
+func WithCorrelationInput(name string) WorkflowOption {
+	return func(c *workflowConfig) {
+		c.correlationInput = name
+	}
+}
+
+// WithDispatchTimeout sets how long DispatchWorkflow looks for the run it
+// started before giving up. It defaults to 2 minutes.
+func WithDispatchTimeout(d time.Duration) WorkflowOption {
+	return func(c *workflowConfig) {
+		c.lookupTimeout = d
+	}
+}
+
+// WithPollInterval sets the interval between polls of the workflow runs API,
+// which doubles after every poll from initial up to maximum. It defaults to
+// 2 seconds up to 30 seconds.
+func WithPollInterval(initial, maximum time.Duration) WorkflowOption {
+	return func(c *workflowConfig) {
+		c.minPoll, c.maxPoll = initial, maximum
+	}
+}
+
+func newWorkflowConfig(opts []WorkflowOption) workflowConfig {
+	cfg := workflowConfig{
+		correlationInput: DefaultCorrelationInput,
+		lookupTimeout:    2 * time.Minute,
+		minPoll:          2 * time.Second,
+		maxPoll:          30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// poll calls check with exponential backoff until it reports done, fails or
+// ctx is done.
+func (cfg workflowConfig) poll(ctx context.Context, check func(context.Context) (bool, error)) error {
+	interval := cfg.minPoll
+	for {
+		done, err := check(ctx)
+		if err != nil || done {
+			return err
+		}
+		t := time.NewTimer(interval)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+		interval = min(2*interval, cfg.maxPoll)
+	}
+}
+
+// DispatchWorkflow triggers the workflow of owner/repo, named by its file name
+// such as "ci.yaml", on ref with the given inputs, and returns the run it
+// started.
+//
+// GitHub doesn't return the run a dispatch starts, so DispatchWorkflow adds a
+// unique token to the inputs, in DefaultCorrelationInput or the input named
+// with WithCorrelationInput, and looks for the run whose title contains it.
+// The workflow must declare the input and include it in its run-name:
+//
+//	on:
+//	  workflow_dispatch:
+//	    inputs:
+//	      correlation_id:
+//	        required: false
+//	run-name: "CI [${{ inputs.correlation_id }}]"
+//
+// Pass the run to WaitForWorkflowRun to wait for it to complete.
+//
+// With WithDryRun, the dispatch is recorded and no run starts, so
+// DispatchWorkflow returns a nil run without looking for it.
+func (c GitHubClient) DispatchWorkflow(ctx context.Context, owner, repo, workflow, ref string, inputs map[string]any, opts ...WorkflowOption) (*github.WorkflowRun, error) {
+	cfg := newWorkflowConfig(opts)
+	token := rand.Text()
+	all := make(map[string]any, len(inputs)+1)
+	maps.Copy(all, inputs)
+	all[cfg.correlationInput] = token
+
+	// Allow for clock skew between us and GitHub when listing the runs
+	// created since the dispatch.
+	since := time.Now().Add(-time.Minute)
+	if err := c.dispatchWorkflow(ctx, owner, repo, workflow, ref, all); err != nil {
+		return nil, err
+	}
+	if c.dryRun != nil {
+		return nil, nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, cfg.lookupTimeout)
+	defer cancel()
+	var run *github.WorkflowRun
+	if err := cfg.poll(lookupCtx, func(ctx context.Context) (bool, error) {
+		runs, err := c.listDispatchedRuns(ctx, owner, repo, workflow, since)
+		if err != nil {
+			return false, err
+		}
+		for _, r := range runs {
+			if strings.Contains(r.GetDisplayTitle(), token) {
+				run = r
+				return true, nil
+			}
+		}
+		return false, nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to find the run of workflow %s dispatched with %s=%s: %w", workflow, cfg.correlationInput, token, err)
+	}
+	clog.FromContext(ctx).Infof("dispatched workflow %s on %s: run %d", workflow, ref, run.GetID())
+	return run, nil
+}
+
+func (c GitHubClient) dispatchWorkflow(ctx context.Context, owner, repo, workflow, ref string, inputs map[string]any) error {
+	ctx, release, err := c.acquire(ctx, owner, repo, 0, WriteOperation)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if _, resp, err := c.inner.Actions.CreateWorkflowDispatchEventByFileName(ctx, owner, repo, workflow, github.CreateWorkflowDispatchEventRequest{
+		Ref:    ref,
+		Inputs: inputs,
+	}); err != nil || resp.StatusCode != http.StatusNoContent {
+		return validateResponse(ctx, err, resp, fmt.Sprintf("dispatch workflow %s on %s", workflow, ref))
+	}
+	return nil
+}
+
+// listDispatchedRuns lists the most recent runs of workflow triggered by a
+// dispatch since the given time.
+func (c GitHubClient) listDispatchedRuns(ctx context.Context, owner, repo, workflow string, since time.Time) ([]*github.WorkflowRun, error) {
+	ctx, release, err := c.acquire(ctx, owner, repo, 0, ReadOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	runs, resp, err := c.inner.Actions.ListWorkflowRunsByFileName(ctx, owner, repo, workflow, &github.ListWorkflowRunsOptions{
+		Event:       "workflow_dispatch",
+		Created:     ">=" + since.UTC().Format(time.RFC3339),
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err := validateResponse(ctx, err, resp, fmt.Sprintf("list runs of workflow %s", workflow)); err != nil {
+		return nil, err
+	}
+	return runs.WorkflowRuns, nil
+}
+
+// RerunFailedJobs re-runs the failed jobs of the workflow run and their
+// dependents. It returns the run as of the new attempt, to pass to
+// WaitForWorkflowRun. With WithDryRun, no new attempt starts, so it returns
+// wr as is.
+func (c GitHubClient) RerunFailedJobs(ctx context.Context, wr *github.WorkflowRun) (*github.WorkflowRun, error) {
+	owner, repo := wr.GetRepository().GetOwner().GetLogin(), wr.GetRepository().GetName()
+	ctx, release, err := c.acquire(ctx, owner, repo, 0, WriteOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	resp, err := c.inner.Actions.RerunFailedJobsByID(ctx, owner, repo, wr.GetID())
+	if err != nil || resp.StatusCode != http.StatusCreated {
+		if err := validateResponse(ctx, err, resp, fmt.Sprintf("re-run failed jobs of workflow run %d", wr.GetID())); err != nil {
+			return nil, err
+		}
+	}
+	if c.dryRun != nil {
+		return wr, nil
+	}
+
+	rerun := *wr
+	rerun.RunAttempt = github.Ptr(wr.GetRunAttempt() + 1)
+	rerun.Status = github.Ptr("queued")
+	rerun.Conclusion = nil
+	return &rerun, nil
+}
+
+// WaitForWorkflowRun polls the workflow run until it completes, and returns
+// it. Its conclusion, such as "success" or "failure", is in GetConclusion.
+// For a run returned by RerunFailedJobs, it waits for the new attempt to
+// complete.
+//
+// Polls back off as configured with WithPollInterval. Bound the wait with a
+// context deadline. A nil run, as DispatchWorkflow returns in dry run, is
+// returned at once.
+func (c GitHubClient) WaitForWorkflowRun(ctx context.Context, wr *github.WorkflowRun, opts ...WorkflowOption) (*github.WorkflowRun, error) {
+	if wr == nil {
+		return nil, nil
+	}
+	cfg := newWorkflowConfig(opts)
+	owner, repo := wr.GetRepository().GetOwner().GetLogin(), wr.GetRepository().GetName()
+
+	var run *github.WorkflowRun
+	if err := cfg.poll(ctx, func(ctx context.Context) (bool, error) {
+		r, err := c.getWorkflowRun(ctx, owner, repo, wr.GetID())
+		if err != nil {
+			return false, err
+		}
+		run = r
+		// A re-run keeps the run's ID, and the previous attempt may still
+		// show as completed.
+		return r.GetStatus() == "completed" && r.GetRunAttempt() >= wr.GetRunAttempt(), nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed waiting for workflow run %d: %w", wr.GetID(), err)
+	}
+	return run, nil
+}
+
+func (c GitHubClient) getWorkflowRun(ctx context.Context, owner, repo string, id int64) (*github.WorkflowRun, error) {
+	ctx, release, err := c.acquire(ctx, owner, repo, 0, ReadOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	run, resp, err := c.inner.Actions.GetWorkflowRunByID(ctx, owner, repo, id)
+	if err := validateResponse(ctx, err, resp, fmt.Sprintf("get workflow run %d", id)); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
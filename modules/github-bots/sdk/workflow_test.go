@@ -0,0 +1,122 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-bots/sdk/githubtest"
+	"github.com/google/go-github/v88/github"
+)
+
+var fastPoll = WithPollInterval(time.Millisecond, 5*time.Millisecond)
+
+func TestDispatchWorkflow(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+
+	// An unrelated dispatch of the same workflow.
+	other, err := gh.DispatchWorkflow(ctx, "acme", "widgets", "ci.yaml", "main", nil, fastPoll)
+	if err != nil {
+		t.Fatalf("DispatchWorkflow: %v", err)
+	}
+
+	run, err := gh.DispatchWorkflow(ctx, "acme", "widgets", "ci.yaml", "main", map[string]any{"target": "arm64"},
+		WithCorrelationInput("trace"), fastPoll)
+	if err != nil {
+		t.Fatalf("DispatchWorkflow: %v", err)
+	}
+	if run.GetID() == other.GetID() {
+		t.Errorf("DispatchWorkflow returned the run of another dispatch")
+	}
+	if got, want := run.GetEvent(), "workflow_dispatch"; got != want {
+		t.Errorf("event = %q, want %q", got, want)
+	}
+
+	var req github.CreateWorkflowDispatchEventRequest
+	reqs := srv.Requests()
+	for _, r := range reqs {
+		if r.Method == http.MethodPost && r.Path == "/repos/acme/widgets/actions/workflows/ci.yaml/dispatches" {
+			if err := json.Unmarshal(r.Body, &req); err != nil {
+				t.Fatalf("decoding dispatch: %v", err)
+			}
+		}
+	}
+	if req.Ref != "main" || req.Inputs["target"] != "arm64" {
+		t.Errorf("dispatched %+v, want ref main and target arm64", req)
+	}
+	token, _ := req.Inputs["trace"].(string)
+	if token == "" || !strings.Contains(run.GetDisplayTitle(), token) {
+		t.Errorf("run title %q doesn't contain the correlation token %q", run.GetDisplayTitle(), token)
+	}
+
+	if _, err := gh.DispatchWorkflow(ctx, "acme", "widgets", "ci.yaml", "", nil, fastPoll); err == nil {
+		t.Error("DispatchWorkflow without a ref succeeded")
+	}
+}
+
+func TestWaitForWorkflowRun(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+	run := srv.AddWorkflowRun("acme", "widgets", &github.WorkflowRun{
+		Status:     github.Ptr("in_progress"),
+		RunAttempt: github.Ptr(1),
+	})
+
+	time.AfterFunc(20*time.Millisecond, func() {
+		srv.SetWorkflowRunStatus("acme", "widgets", run.GetID(), "completed", "failure")
+	})
+	done, err := gh.WaitForWorkflowRun(ctx, run, fastPoll)
+	if err != nil {
+		t.Fatalf("WaitForWorkflowRun: %v", err)
+	}
+	if got, want := done.GetConclusion(), "failure"; got != want {
+		t.Errorf("conclusion = %q, want %q", got, want)
+	}
+
+	rerun, err := gh.RerunFailedJobs(ctx, done)
+	if err != nil {
+		t.Fatalf("RerunFailedJobs: %v", err)
+	}
+	if got, want := rerun.GetRunAttempt(), 2; got != want {
+		t.Errorf("run attempt = %d, want %d", got, want)
+	}
+	if _, err := gh.RerunFailedJobs(ctx, done); err == nil {
+		t.Error("RerunFailedJobs of a running workflow succeeded")
+	}
+
+	time.AfterFunc(20*time.Millisecond, func() {
+		srv.SetWorkflowRunStatus("acme", "widgets", run.GetID(), "completed", "success")
+	})
+	done, err = gh.WaitForWorkflowRun(ctx, rerun, fastPoll)
+	if err != nil {
+		t.Fatalf("WaitForWorkflowRun: %v", err)
+	}
+	if done.GetConclusion() != "success" || done.GetRunAttempt() != 2 {
+		t.Errorf("got attempt %d concluding %q, want attempt 2 concluding success", done.GetRunAttempt(), done.GetConclusion())
+	}
+}
+
+func TestWaitForWorkflowRun_Cancel(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	gh := NewGitHubClient(ctx, "acme", "widgets", "test", WithClient(srv.Client()))
+	run := srv.AddWorkflowRun("acme", "widgets", &github.WorkflowRun{Status: github.Ptr("queued")})
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := gh.WaitForWorkflowRun(ctx, run, fastPoll); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForWorkflowRun = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -8,38 +8,34 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
-	"flag"
-	"log"
-	"path/filepath"
-
-	"github.com/chainguard-dev/terraform-infra-common/modules/github-events/internal/schemagen"
 	"github.com/chainguard-dev/terraform-infra-common/modules/github-events/schemas"
+	"github.com/chainguard-dev/terraform-infra-common/pkg/schemagen"
 )
 
-var base = flag.String("base", "./../../schemas", "base directory to write to")
-
-func main() {
-	flag.Parse()
-
-	mustGenerate("pull_request.schema.json", schemas.Wrapper[schemas.PullRequestEvent]{})
-	mustGenerate("workflow_run.schema.json", schemas.Wrapper[schemas.WorkflowRunEvent]{})
-	mustGenerate("workflow_job.schema.json", schemas.Wrapper[schemas.WorkflowJobEvent]{})
-	mustGenerate("issue_comment.schema.json", schemas.Wrapper[schemas.IssueCommentEvent]{})
-	mustGenerate("issues.schema.json", schemas.Wrapper[schemas.IssueEvent]{})
-	mustGenerate("push.schema.json", schemas.Wrapper[schemas.PushEvent]{})
-	mustGenerate("check_run.schema.json", schemas.Wrapper[schemas.CheckRunEvent]{})
-	mustGenerate("check_suite.schema.json", schemas.Wrapper[schemas.CheckSuiteEvent]{})
-	mustGenerate("projects_v2_item.schema.json", schemas.Wrapper[schemas.ProjectsV2ItemEvent]{})
-	mustGenerate("pull_request_review.schema.json", schemas.Wrapper[schemas.PullRequestReviewEvent]{})
-	mustGenerate("pull_request_review_comment.schema.json", schemas.Wrapper[schemas.PullRequestReviewCommentEvent]{})
+var manifest = schemagen.Manifest{
+	"pull_request.schema.json":                schemas.Wrapper[schemas.PullRequestEvent]{},
+	"workflow_run.schema.json":                schemas.Wrapper[schemas.WorkflowRunEvent]{},
+	"workflow_job.schema.json":                schemas.Wrapper[schemas.WorkflowJobEvent]{},
+	"issue_comment.schema.json":               schemas.Wrapper[schemas.IssueCommentEvent]{},
+	"issues.schema.json":                      schemas.Wrapper[schemas.IssueEvent]{},
+	"push.schema.json":                        schemas.Wrapper[schemas.PushEvent]{},
+	"check_run.schema.json":                   schemas.Wrapper[schemas.CheckRunEvent]{},
+	"check_suite.schema.json":                 schemas.Wrapper[schemas.CheckSuiteEvent]{},
+	"projects_v2_item.schema.json":            schemas.Wrapper[schemas.ProjectsV2ItemEvent]{},
+	"pull_request_review.schema.json":         schemas.Wrapper[schemas.PullRequestReviewEvent]{},
+	"pull_request_review_comment.schema.json": schemas.Wrapper[schemas.PullRequestReviewCommentEvent]{},
+	"merge_group.schema.json":                 schemas.Wrapper[schemas.MergeGroupEvent]{},
+	"release.schema.json":                     schemas.Wrapper[schemas.ReleaseEvent]{},
+	"create.schema.json":                      schemas.Wrapper[schemas.CreateEvent]{},
+	"delete.schema.json":                      schemas.Wrapper[schemas.DeleteEvent]{},
+	"status.schema.json":                      schemas.Wrapper[schemas.StatusEvent]{},
+	"deployment.schema.json":                  schemas.Wrapper[schemas.DeploymentEvent]{},
+	"deployment_status.schema.json":           schemas.Wrapper[schemas.DeploymentStatusEvent]{},
+	"installation.schema.json":                schemas.Wrapper[schemas.InstallationEvent]{},
+	"installation_repositories.schema.json":   schemas.Wrapper[schemas.InstallationRepositoriesEvent]{},
+	"repository.schema.json":                  schemas.Wrapper[schemas.RepositoryEvent]{},
 }
 
-func mustGenerate[T any](path string, w schemas.Wrapper[T]) {
-	if err := generate(path, w); err != nil {
-		log.Fatalf("Failed to generate %T -> %s: %v", w, path, err)
-	}
-}
-
-func generate[T any](fn string, w schemas.Wrapper[T]) error {
-	return schemagen.Generate(filepath.Join(*base, fn), w)
+func main() {
+	schemagen.Main("./../../schemas", manifest)
 }
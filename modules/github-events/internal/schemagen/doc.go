@@ -1,11 +0,0 @@
-/*
-Copyright 2026 Chainguard, Inc.
-SPDX-License-Identifier: Apache-2.0
-*/
-
-// Package schemagen provides utilities for generating BigQuery JSON schema
-// files from Go types.
-//
-// Use [Generate] to infer a BigQuery schema from a Go value and write it
-// to a file.
-package schemagen
@@ -16,6 +16,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/types"
@@ -73,9 +74,19 @@ func TestTrampoline(t *testing.T) {
 	if err != nil {
 		t.Fatalf("error encoding body: %v", err)
 	}
-	enc, err := json.Marshal(eventData{
+	type headers struct {
+		HookID     string `json:"hook_id,omitempty"`
+		DeliveryID string `json:"delivery_id,omitempty"`
+		UserAgent  string `json:"user_agent,omitempty"`
+		Event      string `json:"event,omitempty"`
+	}
+	enc, err := json.Marshal(struct {
+		When    time.Time       `json:"when"`
+		Headers *headers        `json:"headers,omitempty"`
+		Body    json.RawMessage `json:"body"`
+	}{
 		When: clock.Now(),
-		Headers: &eventHeaders{
+		Headers: &headers{
 			HookID:     "1234",
 			DeliveryID: "5678",
 			UserAgent:  t.Name(),
@@ -203,184 +214,6 @@ func TestRequestedOnlyWebhook(t *testing.T) {
 	}
 }
 
-func TestExtractPullRequestInfo(t *testing.T) {
-	testCases := []struct {
-		name      string
-		eventType string
-		payload   PayloadInfo
-		expected  string
-	}{{
-		name:      "pull_request event with valid data",
-		eventType: "pull_request",
-		payload: PayloadInfo{
-			PullRequest: pullRequestInfo{
-				Number: 123,
-			},
-			Repository: struct {
-				FullName string `json:"full_name,omitempty"`
-				Owner    struct {
-					Login string `json:"login,omitempty"`
-				} `json:"owner,omitempty"`
-				Name string `json:"name,omitempty"`
-			}{
-				FullName: "foo/bar",
-			},
-		},
-		expected: "foo/bar#123",
-	}, {
-		name:      "not a pull_request event",
-		eventType: "push",
-		payload: PayloadInfo{
-			PullRequest: pullRequestInfo{
-				Number: 123,
-			},
-			Repository: struct {
-				FullName string `json:"full_name,omitempty"`
-				Owner    struct {
-					Login string `json:"login,omitempty"`
-				} `json:"owner,omitempty"`
-				Name string `json:"name,omitempty"`
-			}{
-				FullName: "foo/bar",
-			},
-		},
-		expected: "",
-	}, {
-		name:      "pull_request event with missing number",
-		eventType: "pull_request",
-		payload: PayloadInfo{
-			Repository: struct {
-				FullName string `json:"full_name,omitempty"`
-				Owner    struct {
-					Login string `json:"login,omitempty"`
-				} `json:"owner,omitempty"`
-				Name string `json:"name,omitempty"`
-			}{
-				FullName: "foo/bar",
-			},
-		},
-		expected: "",
-	}, {
-		name:      "pull_request event with missing repo",
-		eventType: "pull_request",
-		payload: PayloadInfo{
-			PullRequest: pullRequestInfo{
-				Number: 123,
-			},
-		},
-		expected: "",
-	}}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			// Call the function directly
-			result := extractPullRequestInfo(tc.eventType, tc.payload)
-
-			// Check the result
-			if result != tc.expected {
-				t.Errorf("result: got = %q, wanted = %q", result, tc.expected)
-			}
-		})
-	}
-}
-
-func TestExtractPullRequestURL(t *testing.T) {
-	testCases := []struct {
-		name      string
-		eventType string
-		payload   PayloadInfo
-		expected  string
-	}{{
-		name:      "pull_request event with valid data",
-		eventType: "pull_request",
-		payload: PayloadInfo{
-			PullRequest: pullRequestInfo{
-				Number: 123,
-			},
-			Repository: struct {
-				FullName string `json:"full_name,omitempty"`
-				Owner    struct {
-					Login string `json:"login,omitempty"`
-				} `json:"owner,omitempty"`
-				Name string `json:"name,omitempty"`
-			}{
-				FullName: "foo/bar",
-				Owner: struct {
-					Login string `json:"login,omitempty"`
-				}{
-					Login: "foo",
-				},
-				Name: "bar",
-			},
-		},
-		expected: "https://github.com/foo/bar/pull/123",
-	}, {
-		name:      "not a pull_request event",
-		eventType: "push",
-		payload: PayloadInfo{
-			Number: 123,
-			Repository: struct {
-				FullName string `json:"full_name,omitempty"`
-				Owner    struct {
-					Login string `json:"login,omitempty"`
-				} `json:"owner,omitempty"`
-				Name string `json:"name,omitempty"`
-			}{
-				FullName: "foo/bar",
-				Owner: struct {
-					Login string `json:"login,omitempty"`
-				}{
-					Login: "foo",
-				},
-				Name: "bar",
-			},
-		},
-		expected: "",
-	}, {
-		name:      "pull_request event with missing number",
-		eventType: "pull_request",
-		payload: PayloadInfo{
-			Repository: struct {
-				FullName string `json:"full_name,omitempty"`
-				Owner    struct {
-					Login string `json:"login,omitempty"`
-				} `json:"owner,omitempty"`
-				Name string `json:"name,omitempty"`
-			}{
-				FullName: "foo/bar",
-				Owner: struct {
-					Login string `json:"login,omitempty"`
-				}{
-					Login: "foo",
-				},
-				Name: "bar",
-			},
-		},
-		expected: "",
-	}, {
-		name:      "pull_request event with missing repo",
-		eventType: "pull_request",
-		payload: PayloadInfo{
-			PullRequest: pullRequestInfo{
-				Number: 123,
-			},
-		},
-		expected: "",
-	}}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			// Call the function directly
-			result := extractPullRequestURL(tc.eventType, tc.payload)
-
-			// Check the result
-			if result != tc.expected {
-				t.Errorf("result: got = %q, wanted = %q", result, tc.expected)
-			}
-		})
-	}
-}
-
 func TestPullRequestExtension(t *testing.T) {
 	client := &fakeClient{}
 	secret := []byte("hunter2")
@@ -484,161 +317,51 @@ func TestPullRequestExtension(t *testing.T) {
 	}
 }
 
-func TestExtractHeadBranch(t *testing.T) {
-	for _, tc := range []struct {
-		name      string
-		eventType string
-		payload   string
-		want      string
-	}{{
-		name:      "pull_request head ref",
-		eventType: "pull_request",
-		payload:   `{"pull_request":{"head":{"ref":"reconciler-a/path/foo"}}}`,
-		want:      "reconciler-a/path/foo",
-	}, {
-		name:      "pull_request_review head ref",
-		eventType: "pull_request_review",
-		payload:   `{"pull_request":{"head":{"ref":"reconciler-b/widget/bash"}}}`,
-		want:      "reconciler-b/widget/bash",
-	}, {
-		name:      "check_run PR head ref",
-		eventType: "check_run",
-		payload:   `{"check_run":{"check_suite":{"pull_requests":[{"head":{"ref":"reconciler-a/path/bar"}}]}}}`,
-		want:      "reconciler-a/path/bar",
-	}, {
-		name:      "check_suite PR head ref",
-		eventType: "check_suite",
-		payload:   `{"check_suite":{"pull_requests":[{"head":{"ref":"reconciler-a/path/baz"}}]}}`,
-		want:      "reconciler-a/path/baz",
-	}, {
-		name:      "check_suite not associated with a PR",
-		eventType: "check_suite",
-		payload:   `{"check_suite":{"pull_requests":[]}}`,
-		want:      "",
-	}, {
-		name:      "non-PR event ignored",
-		eventType: "push",
-		payload:   `{"ref":"refs/heads/main"}`,
-		want:      "",
-	}, {
-		name:      "pull_request without head",
-		eventType: "pull_request",
-		payload:   `{"pull_request":{"number":1}}`,
-		want:      "",
-	}} {
-		t.Run(tc.name, func(t *testing.T) {
-			var info PayloadInfo
-			if err := json.Unmarshal([]byte(tc.payload), &info); err != nil {
-				t.Fatalf("unmarshal payload: %v", err)
-			}
-			if got := extractHeadBranch(tc.eventType, info); got != tc.want {
-				t.Errorf("extractHeadBranch() = %q, want %q", got, tc.want)
-			}
-		})
-	}
-}
+func TestMergeGroupExtensions(t *testing.T) {
+	client := &fakeClient{}
+	secret := []byte("hunter2")
+	srv := httptest.NewServer(NewServer(client, ServerOptions{
+		Secrets:              [][]byte{secret},
+		RequestedOnlyWebhook: []string{"1234"},
+	}))
+	defer srv.Close()
 
-func TestExtractIssueURL(t *testing.T) {
-	testCases := []struct {
-		name      string
-		eventType string
-		payload   PayloadInfo
-		expected  string
-	}{{
-		name:      "issues event with valid data",
-		eventType: "issues",
-		payload: PayloadInfo{
-			Issue: struct {
-				Number          int       `json:"number,omitempty"`
-				PullRequestInfo *struct{} `json:"pull_request,omitempty"`
-			}{
-				Number: 456,
-			},
-			Repository: struct {
-				FullName string `json:"full_name,omitempty"`
-				Owner    struct {
-					Login string `json:"login,omitempty"`
-				} `json:"owner,omitempty"`
-				Name string `json:"name,omitempty"`
-			}{
-				FullName: "foo/bar",
-				Owner: struct {
-					Login string `json:"login,omitempty"`
-				}{
-					Login: "foo",
-				},
-				Name: "bar",
-			},
-		},
-		expected: "https://github.com/foo/bar/issues/456",
-	}, {
-		name:      "issue_comment on issue (not PR)",
-		eventType: "issue_comment",
-		payload: PayloadInfo{
-			Issue: struct {
-				Number          int       `json:"number,omitempty"`
-				PullRequestInfo *struct{} `json:"pull_request,omitempty"`
-			}{
-				Number:          789,
-				PullRequestInfo: nil,
-			},
-			Repository: struct {
-				FullName string `json:"full_name,omitempty"`
-				Owner    struct {
-					Login string `json:"login,omitempty"`
-				} `json:"owner,omitempty"`
-				Name string `json:"name,omitempty"`
-			}{
-				FullName: "foo/bar",
-				Owner: struct {
-					Login string `json:"login,omitempty"`
-				}{
-					Login: "foo",
-				},
-				Name: "bar",
-			},
+	resp, err := sendevent(t, srv.Client(), srv.URL, "merge_group", map[string]interface{}{
+		"action": "checks_requested",
+		"merge_group": map[string]interface{}{
+			"head_sha": "abc123",
+			"base_ref": "refs/heads/main",
 		},
-		expected: "https://github.com/foo/bar/issues/789",
-	}, {
-		name:      "issue_comment on PR (should not return issue URL)",
-		eventType: "issue_comment",
-		payload: PayloadInfo{
-			Issue: struct {
-				Number          int       `json:"number,omitempty"`
-				PullRequestInfo *struct{} `json:"pull_request,omitempty"`
-			}{
-				Number:          789,
-				PullRequestInfo: &struct{}{},
-			},
-			Repository: struct {
-				FullName string `json:"full_name,omitempty"`
-				Owner    struct {
-					Login string `json:"login,omitempty"`
-				} `json:"owner,omitempty"`
-				Name string `json:"name,omitempty"`
-			}{
-				FullName: "foo/bar",
-				Owner: struct {
-					Login string `json:"login,omitempty"`
-				}{
-					Login: "foo",
-				},
-				Name: "bar",
-			},
+		"repository": map[string]interface{}{
+			"full_name": "org/repo",
 		},
-		expected: "",
-	}}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			// Call the function directly
-			result := extractIssueURL(tc.eventType, tc.payload)
+	}, secret)
+	if err != nil {
+		t.Fatalf("error sending event: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status: %v", resp.Status)
+	}
+	if len(client.events) != 1 {
+		t.Fatalf("got %d events, want 1", len(client.events))
+	}
+	ext := client.events[0].Extensions()
+	if got := ext["headsha"]; got != "abc123" {
+		t.Errorf("headsha extension = %v, want abc123", got)
+	}
+	if got := ext["baseref"]; got != "refs/heads/main" {
+		t.Errorf("baseref extension = %v, want refs/heads/main", got)
+	}
 
-			// Check the result
-			if result != tc.expected {
-				t.Errorf("result: got = %q, wanted = %q", result, tc.expected)
-			}
-		})
+	// Destroyed merge groups are not check requests.
+	resp, err = sendevent(t, srv.Client(), srv.URL, "merge_group", map[string]interface{}{
+		"action": "destroyed",
+	}, secret)
+	if err != nil {
+		t.Fatalf("error sending event: %v", err)
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("unexpected status: %v", resp.Status)
 	}
 }
 
@@ -904,67 +627,6 @@ func TestPullRequestURLExtensionMultipleEventTypes(t *testing.T) {
 	}
 }
 
-func TestIsPullRequestMerged(t *testing.T) {
-	testCases := []struct {
-		name      string
-		eventType string
-		payload   PayloadInfo
-		expected  bool
-	}{{
-		name:      "merged pull request",
-		eventType: "pull_request",
-		payload: PayloadInfo{
-			Action: "closed",
-			PullRequest: pullRequestInfo{
-				Merged: true,
-			},
-		},
-		expected: true,
-	}, {
-		name:      "closed but not merged pull request",
-		eventType: "pull_request",
-		payload: PayloadInfo{
-			Action: "closed",
-			PullRequest: pullRequestInfo{
-				Merged: false,
-			},
-		},
-		expected: false,
-	}, {
-		name:      "open pull request",
-		eventType: "pull_request",
-		payload: PayloadInfo{
-			Action: "opened",
-			PullRequest: pullRequestInfo{
-				Merged: false,
-			},
-		},
-		expected: false,
-	}, {
-		name:      "not a pull request event",
-		eventType: "push",
-		payload: PayloadInfo{
-			Action: "closed",
-			PullRequest: pullRequestInfo{
-				Merged: true,
-			},
-		},
-		expected: false,
-	}}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			// Call the function directly
-			result := isPullRequestMerged(tc.eventType, tc.payload)
-
-			// Check the result
-			if result != tc.expected {
-				t.Errorf("result: got = %v, wanted = %v", result, tc.expected)
-			}
-		})
-	}
-}
-
 func TestOrgFilter(t *testing.T) {
 	secret := []byte("hunter2")
 	opts := ServerOptions{
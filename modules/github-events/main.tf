@@ -180,5 +180,45 @@ output "recorder-schemas" {
       schema                = file("${path.module}/schemas/pull_request_review_comment.schema.json")
       retention_period_days = 548 # 18 months (365 * 1.5)
     }
+    "dev.chainguard.github.merge_group" : {
+      schema                = file("${path.module}/schemas/merge_group.schema.json")
+      retention_period_days = 548 # 18 months (365 * 1.5)
+    }
+    "dev.chainguard.github.release" : {
+      schema                = file("${path.module}/schemas/release.schema.json")
+      retention_period_days = 548 # 18 months (365 * 1.5)
+    }
+    "dev.chainguard.github.create" : {
+      schema                = file("${path.module}/schemas/create.schema.json")
+      retention_period_days = 548 # 18 months (365 * 1.5)
+    }
+    "dev.chainguard.github.delete" : {
+      schema                = file("${path.module}/schemas/delete.schema.json")
+      retention_period_days = 548 # 18 months (365 * 1.5)
+    }
+    "dev.chainguard.github.status" : {
+      schema                = file("${path.module}/schemas/status.schema.json")
+      retention_period_days = 548 # 18 months (365 * 1.5)
+    }
+    "dev.chainguard.github.deployment" : {
+      schema                = file("${path.module}/schemas/deployment.schema.json")
+      retention_period_days = 548 # 18 months (365 * 1.5)
+    }
+    "dev.chainguard.github.deployment_status" : {
+      schema                = file("${path.module}/schemas/deployment_status.schema.json")
+      retention_period_days = 548 # 18 months (365 * 1.5)
+    }
+    "dev.chainguard.github.installation" : {
+      schema                = file("${path.module}/schemas/installation.schema.json")
+      retention_period_days = 548 # 18 months (365 * 1.5)
+    }
+    "dev.chainguard.github.installation_repositories" : {
+      schema                = file("${path.module}/schemas/installation_repositories.schema.json")
+      retention_period_days = 548 # 18 months (365 * 1.5)
+    }
+    "dev.chainguard.github.repository" : {
+      schema                = file("${path.module}/schemas/repository.schema.json")
+      retention_period_days = 548 # 18 months (365 * 1.5)
+    }
   }
 }
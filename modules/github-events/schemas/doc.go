@@ -10,4 +10,7 @@ SPDX-License-Identifier: Apache-2.0
 // delivery timestamp and GitHub webhook headers. Event-specific types such
 // as [PullRequest], [Repository], [User], and [Installation] mirror the
 // structure of GitHub webhook payloads using BigQuery-compatible field types.
+//
+// [Registry] returns the JSON Schemas generated from the same types, by
+// CloudEvent type, to validate event payloads against the recorded columns.
 package schemas
@@ -449,3 +449,225 @@ type PullRequestReviewCommentEvent struct {
 	Sender       User                     `json:"sender,omitempty" bigquery:"sender"`
 	Installation *Installation            `json:"installation,omitempty" bigquery:"installation"`
 }
+
+// https://pkg.go.dev/github.com/google/go-github/v88/github#MergeGroup
+type MergeGroup struct {
+	HeadSHA bigquery.NullString `json:"head_sha,omitempty" bigquery:"head_sha"`
+	HeadRef bigquery.NullString `json:"head_ref,omitempty" bigquery:"head_ref"`
+	BaseSHA bigquery.NullString `json:"base_sha,omitempty" bigquery:"base_sha"`
+	BaseRef bigquery.NullString `json:"base_ref,omitempty" bigquery:"base_ref"`
+}
+
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#merge_group
+// https://pkg.go.dev/github.com/google/go-github/v88/github#MergeGroupEvent
+type MergeGroupEvent struct {
+	// checks_requested or destroyed
+	Action bigquery.NullString `json:"action,omitempty" bigquery:"action"`
+	// Populated when action is destroyed: merged, invalidated or dequeued
+	Reason       bigquery.NullString `json:"reason,omitempty" bigquery:"reason"`
+	MergeGroup   MergeGroup          `json:"merge_group,omitempty" bigquery:"merge_group"`
+	Repository   Repository          `json:"repository,omitempty" bigquery:"repository"`
+	Organization Organization        `json:"organization,omitempty" bigquery:"organization"`
+	Sender       User                `json:"sender,omitempty" bigquery:"sender"`
+	Installation *Installation       `json:"installation,omitempty" bigquery:"installation"`
+}
+
+// https://pkg.go.dev/github.com/google/go-github/v88/github#RepositoryRelease
+type Release struct {
+	ID              bigquery.NullInt64     `json:"id,omitempty" bigquery:"id"`
+	TagName         bigquery.NullString    `json:"tag_name,omitempty" bigquery:"tag_name"`
+	TargetCommitish bigquery.NullString    `json:"target_commitish,omitempty" bigquery:"target_commitish"`
+	Name            bigquery.NullString    `json:"name,omitempty" bigquery:"name"`
+	Draft           bigquery.NullBool      `json:"draft,omitempty" bigquery:"draft"`
+	Prerelease      bigquery.NullBool      `json:"prerelease,omitempty" bigquery:"prerelease"`
+	Author          User                   `json:"author,omitempty" bigquery:"author"`
+	HTMLURL         bigquery.NullString    `json:"html_url,omitempty" bigquery:"html_url"`
+	CreatedAt       bigquery.NullTimestamp `json:"created_at,omitempty" bigquery:"created_at"`
+	PublishedAt     bigquery.NullTimestamp `json:"published_at,omitempty" bigquery:"published_at"`
+}
+
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#release
+// https://pkg.go.dev/github.com/google/go-github/v88/github#ReleaseEvent
+type ReleaseEvent struct {
+	// published, created, edited, etc.
+	Action       bigquery.NullString `json:"action,omitempty" bigquery:"action"`
+	Release      Release             `json:"release,omitempty" bigquery:"release"`
+	Repository   Repository          `json:"repository,omitempty" bigquery:"repository"`
+	Organization Organization        `json:"organization,omitempty" bigquery:"organization"`
+	Sender       User                `json:"sender,omitempty" bigquery:"sender"`
+	Installation *Installation       `json:"installation,omitempty" bigquery:"installation"`
+}
+
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#create
+// https://pkg.go.dev/github.com/google/go-github/v88/github#CreateEvent
+type CreateEvent struct {
+	Ref bigquery.NullString `json:"ref,omitempty" bigquery:"ref"`
+	// branch or tag
+	RefType      bigquery.NullString `json:"ref_type,omitempty" bigquery:"ref_type"`
+	MasterBranch bigquery.NullString `json:"master_branch,omitempty" bigquery:"master_branch"`
+	Description  bigquery.NullString `json:"description,omitempty" bigquery:"description"`
+	PusherType   bigquery.NullString `json:"pusher_type,omitempty" bigquery:"pusher_type"`
+	Repository   Repository          `json:"repository,omitempty" bigquery:"repository"`
+	Organization Organization        `json:"organization,omitempty" bigquery:"organization"`
+	Sender       User                `json:"sender,omitempty" bigquery:"sender"`
+	Installation *Installation       `json:"installation,omitempty" bigquery:"installation"`
+}
+
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#delete
+// https://pkg.go.dev/github.com/google/go-github/v88/github#DeleteEvent
+type DeleteEvent struct {
+	Ref bigquery.NullString `json:"ref,omitempty" bigquery:"ref"`
+	// branch or tag
+	RefType      bigquery.NullString `json:"ref_type,omitempty" bigquery:"ref_type"`
+	PusherType   bigquery.NullString `json:"pusher_type,omitempty" bigquery:"pusher_type"`
+	Repository   Repository          `json:"repository,omitempty" bigquery:"repository"`
+	Organization Organization        `json:"organization,omitempty" bigquery:"organization"`
+	Sender       User                `json:"sender,omitempty" bigquery:"sender"`
+	Installation *Installation       `json:"installation,omitempty" bigquery:"installation"`
+}
+
+// https://pkg.go.dev/github.com/google/go-github/v88/github#Branch
+type Branch struct {
+	Name bigquery.NullString `json:"name,omitempty" bigquery:"name"`
+}
+
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#status
+// https://pkg.go.dev/github.com/google/go-github/v88/github#StatusEvent
+type StatusEvent struct {
+	ID  bigquery.NullInt64  `json:"id,omitempty" bigquery:"id"`
+	SHA bigquery.NullString `json:"sha,omitempty" bigquery:"sha"`
+	// pending, success, failure or error
+	State        bigquery.NullString    `json:"state,omitempty" bigquery:"state"`
+	Context      bigquery.NullString    `json:"context,omitempty" bigquery:"context"`
+	Description  bigquery.NullString    `json:"description,omitempty" bigquery:"description"`
+	TargetURL    bigquery.NullString    `json:"target_url,omitempty" bigquery:"target_url"`
+	Branches     []Branch               `json:"branches,omitempty" bigquery:"branches"`
+	CreatedAt    bigquery.NullTimestamp `json:"created_at,omitempty" bigquery:"created_at"`
+	UpdatedAt    bigquery.NullTimestamp `json:"updated_at,omitempty" bigquery:"updated_at"`
+	Repository   Repository             `json:"repository,omitempty" bigquery:"repository"`
+	Organization Organization           `json:"organization,omitempty" bigquery:"organization"`
+	Sender       User                   `json:"sender,omitempty" bigquery:"sender"`
+	Installation *Installation          `json:"installation,omitempty" bigquery:"installation"`
+}
+
+// https://pkg.go.dev/github.com/google/go-github/v88/github#Deployment
+type Deployment struct {
+	ID          bigquery.NullInt64     `json:"id,omitempty" bigquery:"id"`
+	SHA         bigquery.NullString    `json:"sha,omitempty" bigquery:"sha"`
+	Ref         bigquery.NullString    `json:"ref,omitempty" bigquery:"ref"`
+	Task        bigquery.NullString    `json:"task,omitempty" bigquery:"task"`
+	Environment bigquery.NullString    `json:"environment,omitempty" bigquery:"environment"`
+	Description bigquery.NullString    `json:"description,omitempty" bigquery:"description"`
+	Creator     User                   `json:"creator,omitempty" bigquery:"creator"`
+	CreatedAt   bigquery.NullTimestamp `json:"created_at,omitempty" bigquery:"created_at"`
+	UpdatedAt   bigquery.NullTimestamp `json:"updated_at,omitempty" bigquery:"updated_at"`
+}
+
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#deployment
+// https://pkg.go.dev/github.com/google/go-github/v88/github#DeploymentEvent
+type DeploymentEvent struct {
+	// created
+	Action       bigquery.NullString `json:"action,omitempty" bigquery:"action"`
+	Deployment   Deployment          `json:"deployment,omitempty" bigquery:"deployment"`
+	Repository   Repository          `json:"repository,omitempty" bigquery:"repository"`
+	Organization Organization        `json:"organization,omitempty" bigquery:"organization"`
+	Sender       User                `json:"sender,omitempty" bigquery:"sender"`
+	Installation *Installation       `json:"installation,omitempty" bigquery:"installation"`
+}
+
+// https://pkg.go.dev/github.com/google/go-github/v88/github#DeploymentStatus
+type DeploymentStatus struct {
+	ID bigquery.NullInt64 `json:"id,omitempty" bigquery:"id"`
+	// pending, success, failure, error, inactive, in_progress or queued
+	State          bigquery.NullString    `json:"state,omitempty" bigquery:"state"`
+	Description    bigquery.NullString    `json:"description,omitempty" bigquery:"description"`
+	Environment    bigquery.NullString    `json:"environment,omitempty" bigquery:"environment"`
+	EnvironmentURL bigquery.NullString    `json:"environment_url,omitempty" bigquery:"environment_url"`
+	LogURL         bigquery.NullString    `json:"log_url,omitempty" bigquery:"log_url"`
+	TargetURL      bigquery.NullString    `json:"target_url,omitempty" bigquery:"target_url"`
+	Creator        User                   `json:"creator,omitempty" bigquery:"creator"`
+	CreatedAt      bigquery.NullTimestamp `json:"created_at,omitempty" bigquery:"created_at"`
+	UpdatedAt      bigquery.NullTimestamp `json:"updated_at,omitempty" bigquery:"updated_at"`
+}
+
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#deployment_status
+// https://pkg.go.dev/github.com/google/go-github/v88/github#DeploymentStatusEvent
+type DeploymentStatusEvent struct {
+	// created
+	Action           bigquery.NullString `json:"action,omitempty" bigquery:"action"`
+	Deployment       Deployment          `json:"deployment,omitempty" bigquery:"deployment"`
+	DeploymentStatus DeploymentStatus    `json:"deployment_status,omitempty" bigquery:"deployment_status"`
+	Repository       Repository          `json:"repository,omitempty" bigquery:"repository"`
+	Organization     Organization        `json:"organization,omitempty" bigquery:"organization"`
+	Sender           User                `json:"sender,omitempty" bigquery:"sender"`
+	Installation     *Installation       `json:"installation,omitempty" bigquery:"installation"`
+}
+
+// AppInstallation is the installation that installation and
+// installation_repositories events are about, with more detail than the
+// Installation other events carry.
+//
+// https://pkg.go.dev/github.com/google/go-github/v88/github#Installation
+type AppInstallation struct {
+	// Installation ID
+	ID bigquery.NullInt64 `json:"id,omitempty" bigquery:"id"`
+	// App ID
+	AppID bigquery.NullInt64 `json:"app_id,omitempty" bigquery:"app_id"`
+	// The user or organization the app is installed on
+	Account User `json:"account,omitempty" bigquery:"account"`
+	// User or Organization
+	TargetType bigquery.NullString `json:"target_type,omitempty" bigquery:"target_type"`
+	// all or selected
+	RepositorySelection bigquery.NullString    `json:"repository_selection,omitempty" bigquery:"repository_selection"`
+	CreatedAt           bigquery.NullTimestamp `json:"created_at,omitempty" bigquery:"created_at"`
+	UpdatedAt           bigquery.NullTimestamp `json:"updated_at,omitempty" bigquery:"updated_at"`
+	SuspendedAt         bigquery.NullTimestamp `json:"suspended_at,omitempty" bigquery:"suspended_at"`
+}
+
+// InstallationRepository is a repository as listed by installation and
+// installation_repositories events, which carry fewer details than
+// Repository.
+type InstallationRepository struct {
+	ID       bigquery.NullInt64  `json:"id,omitempty" bigquery:"id"`
+	Name     bigquery.NullString `json:"name,omitempty" bigquery:"name"`
+	FullName bigquery.NullString `json:"full_name,omitempty" bigquery:"full_name"`
+	Private  bigquery.NullBool   `json:"private,omitempty" bigquery:"private"`
+}
+
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#installation
+// https://pkg.go.dev/github.com/google/go-github/v88/github#InstallationEvent
+type InstallationEvent struct {
+	// created, deleted, suspend, unsuspend, new_permissions_accepted
+	Action bigquery.NullString `json:"action,omitempty" bigquery:"action"`
+	// Populated when action is created
+	Repositories []InstallationRepository `json:"repositories,omitempty" bigquery:"repositories"`
+	Sender       User                     `json:"sender,omitempty" bigquery:"sender"`
+	Installation AppInstallation          `json:"installation,omitempty" bigquery:"installation"`
+}
+
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#installation_repositories
+// https://pkg.go.dev/github.com/google/go-github/v88/github#InstallationRepositoriesEvent
+type InstallationRepositoriesEvent struct {
+	// added or removed
+	Action bigquery.NullString `json:"action,omitempty" bigquery:"action"`
+	// all or selected
+	RepositorySelection bigquery.NullString      `json:"repository_selection,omitempty" bigquery:"repository_selection"`
+	RepositoriesAdded   []InstallationRepository `json:"repositories_added,omitempty" bigquery:"repositories_added"`
+	RepositoriesRemoved []InstallationRepository `json:"repositories_removed,omitempty" bigquery:"repositories_removed"`
+	Sender              User                     `json:"sender,omitempty" bigquery:"sender"`
+	Installation        AppInstallation          `json:"installation,omitempty" bigquery:"installation"`
+}
+
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#repository
+// https://pkg.go.dev/github.com/google/go-github/v88/github#RepositoryEvent
+type RepositoryEvent struct {
+	// created, deleted, archived, unarchived, edited, renamed, transferred,
+	// publicized or privatized
+	Action bigquery.NullString `json:"action,omitempty" bigquery:"action"`
+	// Populated when action is edited, renamed or transferred
+	Changes      bigquery.NullJSON `json:"changes,omitempty" bigquery:"changes"`
+	Repository   Repository        `json:"repository,omitempty" bigquery:"repository"`
+	Organization Organization      `json:"organization,omitempty" bigquery:"organization"`
+	Sender       User              `json:"sender,omitempty" bigquery:"sender"`
+	Installation *Installation     `json:"installation,omitempty" bigquery:"installation"`
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemas
+
+import (
+	"embed"
+	"strings"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-events/webhook"
+	"github.com/chainguard-dev/terraform-infra-common/pkg/eventschema"
+)
+
+//go:embed *.jsonschema.json
+var jsonSchemas embed.FS
+
+// Registry returns an eventschema.Registry of the JSON Schemas generated
+// from the Wrapper types of each event, registered for the CloudEvent types
+// github-events emits, such as "dev.chainguard.github.pull_request".
+func Registry() (*eventschema.Registry, error) {
+	r := eventschema.NewRegistry()
+	if err := r.RegisterFS(jsonSchemas, "*.jsonschema.json", func(name string) string {
+		return webhook.EventTypePrefix + strings.TrimSuffix(name, ".jsonschema.json")
+	}); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
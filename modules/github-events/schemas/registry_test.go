@@ -0,0 +1,68 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemas
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/chainguard-dev/terraform-infra-common/pkg/eventschema"
+)
+
+func TestRegistry(t *testing.T) {
+	r, err := Registry()
+	if err != nil {
+		t.Fatalf("Registry: %v", err)
+	}
+
+	event := func(etype, data string) cloudevents.Event {
+		e := cloudevents.NewEvent()
+		e.SetID("5678")
+		e.SetType(etype)
+		e.SetSource("github.com")
+		if err := e.SetData(cloudevents.ApplicationJSON, []byte(data)); err != nil {
+			t.Fatalf("SetData: %v", err)
+		}
+		return e
+	}
+
+	valid := event("dev.chainguard.github.pull_request", `{
+		"when": "2026-01-02T03:04:05Z",
+		"headers": {"hook_id": "1234", "delivery_id": "5678", "event": "pull_request"},
+		"body": {
+			"action": "opened",
+			"number": 42,
+			"pull_request": {"number": 42, "title": "Fix", "merged_at": null, "labels": [{"name": "bug"}]},
+			"unrecorded": {"anything": true}
+		}
+	}`)
+	if err := r.Validate(valid); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	invalid := event("dev.chainguard.github.pull_request", `{
+		"when": "yesterday",
+		"body": {"action": 1, "pull_request": {"labels": {"name": "bug"}}}
+	}`)
+	verr, ok := errors.AsType[*eventschema.ValidationError](r.Validate(invalid))
+	if !ok {
+		t.Fatalf("Validate() = %v, want a ValidationError", r.Validate(invalid))
+	}
+	var paths []string
+	for _, v := range verr.Violations {
+		paths = append(paths, v.Path)
+	}
+	if want := []string{"/body/action", "/body/pull_request/labels", "/when"}; !slices.Equal(paths, want) {
+		t.Errorf("violations = %v, want paths %q", verr.Violations, want)
+	}
+
+	if err := r.Validate(event("dev.chainguard.github.unknown", `{}`)); !errors.Is(err, eventschema.ErrNoSchema) {
+		t.Errorf("Validate() of an unknown type = %v, want ErrNoSchema", err)
+	}
+}
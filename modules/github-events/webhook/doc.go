@@ -0,0 +1,17 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package webhook converts GitHub webhook deliveries into the CloudEvents that
+// the github-events trampoline publishes to the broker.
+//
+// [ValidatePayload] verifies a delivery's signature against a set of
+// secrets, so secrets can be rotated. [NewEvent] converts the validated
+// delivery into a CloudEvent whose type, subject, extensions and data match
+// what bots subscribed to the broker receive, with the payload wrapped like
+// schemas.Wrapper.
+//
+// The trampoline uses this package, as does sdk.ServeWebhook, which lets a bot
+// receive webhooks directly without the broker.
+package webhook
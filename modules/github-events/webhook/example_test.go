@@ -0,0 +1,31 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook_test
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chainguard-dev/terraform-infra-common/modules/github-events/webhook"
+)
+
+func ExampleNewEvent() {
+	secrets := [][]byte{[]byte("my-secret")}
+	http.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
+		payload, err := webhook.ValidatePayload(r, secrets)
+		if err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		event, err := webhook.NewEvent(r, payload, time.Now())
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Println(event.Type(), event.Extensions()["pullrequesturl"])
+	})
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-github/v88/github"
+)
+
+// EventTypePrefix prefixes the GitHub event type, such as "pull_request", to
+// form the CloudEvent type.
+const EventTypePrefix = "dev.chainguard.github."
+
+// PayloadInfo is a minimal struct for GitHub webhook payload information,
+// containing only the fields we need to process for our needs of setting cloud event headers.
+type PayloadInfo struct {
+	Action     string `json:"action,omitempty"`
+	Number     int    `json:"number,omitempty"`
+	Repository struct {
+		FullName string `json:"full_name,omitempty"`
+		Owner    struct {
+			Login string `json:"login,omitempty"`
+		} `json:"owner,omitempty"`
+		Name string `json:"name,omitempty"`
+	} `json:"repository,omitempty"`
+	Organization struct {
+		Login string `json:"login,omitempty"`
+	} `json:"organization,omitempty"`
+	PullRequest pullRequestInfo `json:"pull_request,omitempty"`
+	Issue       struct {
+		Number          int       `json:"number,omitempty"`
+		PullRequestInfo *struct{} `json:"pull_request,omitempty"`
+	} `json:"issue,omitempty"`
+	CheckRun struct {
+		CheckSuite checkSuiteInfo `json:"check_suite,omitempty"`
+	} `json:"check_run,omitempty"`
+	CheckSuite checkSuiteInfo `json:"check_suite,omitempty"`
+	Comment    struct {
+		ID int `json:"id,omitempty"`
+	} `json:"comment,omitempty"`
+	Review struct {
+		ID int `json:"id,omitempty"`
+	} `json:"review,omitempty"`
+	MergeGroup struct {
+		HeadSHA string `json:"head_sha,omitempty"`
+		BaseRef string `json:"base_ref,omitempty"`
+	} `json:"merge_group,omitempty"`
+}
+
+// pullRequestInfo holds the pull_request fields the extractors use. Named (not
+// anonymous) so it can be constructed in tests and grow fields without breaking
+// every literal.
+type pullRequestInfo struct {
+	Number int  `json:"number,omitempty"`
+	Merged bool `json:"merged,omitempty"`
+	Head   struct {
+		Ref string `json:"ref,omitempty"`
+	} `json:"head,omitempty"`
+}
+
+// prRef is a minimal pull-request reference as it appears in check_suite payloads.
+type prRef struct {
+	Number int `json:"number,omitempty"`
+	Head   struct {
+		Ref string `json:"ref,omitempty"`
+	} `json:"head,omitempty"`
+}
+
+// checkSuiteInfo holds the check_suite fields the extractors use. It appears
+// both at the top level (check_suite events) and nested under check_run.
+type checkSuiteInfo struct {
+	PullRequests []prRef `json:"pull_requests,omitempty"`
+}
+
+// ParsePayload extracts the fields of a webhook payload that the CloudEvent
+// attributes are derived from.
+func ParsePayload(payload []byte) (PayloadInfo, error) {
+	var info PayloadInfo
+	err := json.Unmarshal(payload, &info)
+	return info, err
+}
+
+// NewEvent converts a webhook request, whose payload was validated with
+// ValidatePayload, into a CloudEvent. The event's type is EventTypePrefix
+// followed by the GitHub event type, its subject is the repository's full
+// name, and extensions such as pullrequesturl, headbranch and merged carry
+// the details consumers filter on. Its data wraps the payload with when, the
+// time it was received, and the delivery headers.
+func NewEvent(r *http.Request, payload []byte, when time.Time) (cloudevents.Event, error) {
+	// https://docs.github.com/en/webhooks/webhook-events-and-payloads#delivery-headers
+	t := github.WebHookType(r)
+	if t == "" {
+		return cloudevents.Event{}, errors.New("missing X-GitHub-Event header")
+	}
+	// A payload that doesn't parse leaves the extensions unset.
+	info, _ := ParsePayload(payload)
+
+	event := cloudevents.NewEvent()
+	event.SetID(github.DeliveryID(r))
+	event.SetType(EventTypePrefix + t)
+	event.SetSource(r.Host)
+	event.SetSubject(info.Repository.FullName)
+	event.SetExtension("action", info.Action)
+	// Needs to be an extension to be a filterable attribute.
+	// See https://github.com/chainguard-dev/terraform-infra-common/blob/main/pkg/pubsub/cloudevent.go
+	if hookID := r.Header.Get("X-GitHub-Hook-ID"); hookID != "" {
+		// Cloud Event attribute spec only allows [a-z0-9] :(
+		event.SetExtension("githubhook", hookID)
+	}
+
+	// Add pullrequest extension for pull request events (original format)
+	if prInfo := extractPullRequestInfo(t, info); prInfo != "" {
+		event.SetExtension("pullrequest", prInfo)
+	}
+
+	// Add pullrequest-url extension for PR-related events
+	if prURL := extractPullRequestURL(t, info); prURL != "" {
+		event.SetExtension("pullrequesturl", prURL)
+	}
+
+	// Add issue-url extension for issue-related events
+	if issueURL := extractIssueURL(t, info); issueURL != "" {
+		event.SetExtension("issueurl", issueURL)
+	}
+
+	// Add headbranch extension (the PR's head branch) for PR-related events, so
+	// consumers can filter by branch prefix — e.g. a reconciler subscribing only
+	// to PRs it opened, whose branches are named "<identity>/...".
+	if headBranch := extractHeadBranch(t, info); headBranch != "" {
+		event.SetExtension("headbranch", headBranch)
+	}
+
+	// Add headsha and baseref extensions for merge_group events, so check
+	// bots can report against the merge group's commit and filter by the
+	// branch the queue merges into.
+	if headSHA, baseRef := extractMergeGroup(t, info); headSHA != "" {
+		event.SetExtension("headsha", headSHA)
+		if baseRef != "" {
+			event.SetExtension("baseref", baseRef)
+		}
+	}
+
+	// Add merged extension for merged pull requests
+	if merged := isPullRequestMerged(t, info); merged {
+		event.SetExtension("merged", true)
+	}
+
+	if err := event.SetData(cloudevents.ApplicationJSON, eventData{
+		When: when,
+		Headers: &eventHeaders{
+			HookID:                 r.Header.Get("X-GitHub-Hook-ID"),
+			DeliveryID:             r.Header.Get("X-GitHub-Delivery"),
+			UserAgent:              r.Header.Get("User-Agent"),
+			Event:                  r.Header.Get("X-GitHub-Event"),
+			InstallationTargetType: r.Header.Get("X-GitHub-Installation-Target-Type"),
+			InstallationTargetID:   r.Header.Get("X-GitHub-Installation-Target-ID"),
+		},
+		Body: payload,
+	}); err != nil {
+		return cloudevents.Event{}, fmt.Errorf("failed to set data: %w", err)
+	}
+	return event, nil
+}
+
+type eventData struct {
+	When time.Time `json:"when"`
+	// See https://docs.github.com/en/webhooks/webhook-events-and-payloads#delivery-headers
+	Headers *eventHeaders   `json:"headers,omitempty"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// Relevant headers for GitHub webhook events that we want to record.
+// See https://docs.github.com/en/webhooks/webhook-events-and-payloads#delivery-headers
+type eventHeaders struct {
+	HookID                 string `json:"hook_id,omitempty"`
+	DeliveryID             string `json:"delivery_id,omitempty"`
+	UserAgent              string `json:"user_agent,omitempty"`
+	Event                  string `json:"event,omitempty"`
+	InstallationTargetType string `json:"installation_target_type,omitempty"`
+	InstallationTargetID   string `json:"installation_target_id,omitempty"`
+}
+
+// extractPullRequestInfo extracts pull request information from GitHub payload
+// Returns a formatted string in the format "org/repo#number" or empty string if not a PR event
+func extractPullRequestInfo(eventType string, info PayloadInfo) string {
+	// Only process pull_request events
+	if eventType != "pull_request" {
+		return ""
+	}
+
+	// Extract information from our typed struct
+	if info.PullRequest.Number > 0 && info.Repository.FullName != "" {
+		return fmt.Sprintf("%s#%d", info.Repository.FullName, info.PullRequest.Number)
+	}
+
+	return ""
+}
+
+// extractPullRequestURL extracts the pull request URL from GitHub events that pertain to a PR
+func extractPullRequestURL(eventType string, info PayloadInfo) string {
+	owner := info.Repository.Owner.Login
+	repo := info.Repository.Name
+	if owner == "" || repo == "" {
+		return ""
+	}
+
+	var prNumber int
+	switch eventType {
+	case "pull_request":
+		prNumber = info.PullRequest.Number
+	case "pull_request_review":
+		prNumber = info.PullRequest.Number
+	case "pull_request_review_comment":
+		prNumber = info.PullRequest.Number
+	case "issue_comment":
+		// Check if this is a PR comment (issue comments can be on PRs too)
+		if info.Issue.PullRequestInfo != nil && info.Issue.Number > 0 {
+			prNumber = info.Issue.Number
+		}
+	case "check_run":
+		if len(info.CheckRun.CheckSuite.PullRequests) > 0 {
+			prNumber = info.CheckRun.CheckSuite.PullRequests[0].Number
+		}
+	case "check_suite":
+		if len(info.CheckSuite.PullRequests) > 0 {
+			prNumber = info.CheckSuite.PullRequests[0].Number
+		}
+	}
+
+	if prNumber > 0 {
+		return fmt.Sprintf("https://github.com/%s/%s/pull/%d", owner, repo, prNumber)
+	}
+	return ""
+}
+
+// extractHeadBranch returns the head branch (source ref) of the PR associated
+// with a PR-related event, or "" if none. Automation that opens PRs commonly
+// names its branches "<identity>/...", so consumers can prefix-filter on this
+// attribute to receive only the PRs a given tool opened. For check events it
+// reads the associated PR's head ref — the same pull_requests[0] element
+// extractPullRequestURL reads for the PR number — rather than
+// check_suite.head_branch, which can be null for check suites not tied to a PR.
+//
+// Field sources in the GitHub webhook payloads:
+//   - pull_request[_review[_comment]]: pull_request.head.ref
+//     https://docs.github.com/en/webhooks/webhook-events-and-payloads#pull_request
+//   - check_run: check_run.check_suite.pull_requests[0].head.ref
+//     https://docs.github.com/en/webhooks/webhook-events-and-payloads#check_run
+//   - check_suite: check_suite.pull_requests[0].head.ref
+//     https://docs.github.com/en/webhooks/webhook-events-and-payloads#check_suite
+func extractHeadBranch(eventType string, info PayloadInfo) string {
+	switch eventType {
+	case "pull_request", "pull_request_review", "pull_request_review_comment":
+		return info.PullRequest.Head.Ref
+	case "check_run":
+		if len(info.CheckRun.CheckSuite.PullRequests) > 0 {
+			return info.CheckRun.CheckSuite.PullRequests[0].Head.Ref
+		}
+	case "check_suite":
+		if len(info.CheckSuite.PullRequests) > 0 {
+			return info.CheckSuite.PullRequests[0].Head.Ref
+		}
+	}
+	return ""
+}
+
+// extractMergeGroup returns the head SHA and base ref (e.g. "refs/heads/main")
+// of a merge_group event, or empty strings for other events.
+// See https://docs.github.com/en/webhooks/webhook-events-and-payloads#merge_group
+func extractMergeGroup(eventType string, info PayloadInfo) (headSHA, baseRef string) {
+	if eventType != "merge_group" {
+		return "", ""
+	}
+	return info.MergeGroup.HeadSHA, info.MergeGroup.BaseRef
+}
+
+// extractIssueURL extracts the issue URL from GitHub events that pertain to an issue
+func extractIssueURL(eventType string, info PayloadInfo) string {
+	owner := info.Repository.Owner.Login
+	repo := info.Repository.Name
+	if owner == "" || repo == "" {
+		return ""
+	}
+
+	var issueNumber int
+	switch eventType {
+	case "issues":
+		issueNumber = info.Issue.Number
+	case "issue_comment":
+		// Only add issue URL if this is NOT a PR comment
+		if info.Issue.PullRequestInfo == nil && info.Issue.Number > 0 {
+			issueNumber = info.Issue.Number
+		}
+	}
+
+	if issueNumber > 0 {
+		return fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, repo, issueNumber)
+	}
+	return ""
+}
+
+// isPullRequestMerged checks if a pull request event is for a merged PR
+// Returns true if the event is a pull_request with "closed" action and merged=true
+func isPullRequestMerged(eventType string, info PayloadInfo) bool {
+	// Only process pull_request events
+	if eventType != "pull_request" {
+		return false
+	}
+
+	// A merged PR will have action="closed" and merged=true
+	return info.Action == "closed" && info.PullRequest.Merged
+}
+
+// ValidatePayload validates the payload of a webhook request for a given set of secrets.
+// If any of the secrets are valid, the payload is returned with no error.
+func ValidatePayload(r *http.Request, secrets [][]byte) ([]byte, error) {
+	// Largely forked from github.ValidatePayload - we can't use this directly to avoid consuming the body.
+	signature := r.Header.Get(github.SHA256SignatureHeader)
+	if signature == "" {
+		signature = r.Header.Get(github.SHA1SignatureHeader)
+	}
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, secret := range secrets {
+		payload, err := github.ValidatePayloadFromBody(contentType, bytes.NewBuffer(body), signature, secret)
+		if err == nil {
+			return payload, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to validate payload")
+}
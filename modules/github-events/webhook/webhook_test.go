@@ -0,0 +1,531 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v88/github"
+)
+
+func signedRequest(t *testing.T, eventType string, payload, secret []byte) *http.Request {
+	t.Helper()
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+
+	r := httptest.NewRequest(http.MethodPost, "http://localhost/", bytes.NewReader(payload))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set(github.SHA256SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	r.Header.Set(github.EventTypeHeader, eventType)
+	r.Header.Set("X-GitHub-Hook-ID", "1234")
+	r.Header.Set(github.DeliveryIDHeader, "5678")
+	return r
+}
+
+func TestValidatePayload(t *testing.T) {
+	payload := []byte(`{"action":"opened"}`)
+
+	got, err := ValidatePayload(signedRequest(t, "pull_request", payload, []byte("hunter2")), [][]byte{[]byte("bad"), []byte("hunter2")})
+	if err != nil {
+		t.Fatalf("ValidatePayload: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Errorf("ValidatePayload = %s, want %s", got, payload)
+	}
+
+	if _, err := ValidatePayload(signedRequest(t, "pull_request", payload, []byte("hunter2")), [][]byte{[]byte("bad")}); err == nil {
+		t.Error("ValidatePayload with the wrong secret succeeded")
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	payload := []byte(`{"action":"closed",` +
+		`"repository":{"full_name":"foo/bar","name":"bar","owner":{"login":"foo"}},` +
+		`"pull_request":{"number":42,"merged":true,"head":{"ref":"bot/update"}}}`)
+	when := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	event, err := NewEvent(signedRequest(t, "pull_request", payload, nil), payload, when)
+	if err != nil {
+		t.Fatalf("NewEvent: %v", err)
+	}
+	if got, want := event.Type(), "dev.chainguard.github.pull_request"; got != want {
+		t.Errorf("type = %q, want %q", got, want)
+	}
+	if got, want := event.ID(), "5678"; got != want {
+		t.Errorf("ID = %q, want %q", got, want)
+	}
+	if got, want := event.Subject(), "foo/bar"; got != want {
+		t.Errorf("subject = %q, want %q", got, want)
+	}
+	if diff := cmp.Diff(map[string]any{
+		"action":         "closed",
+		"githubhook":     "1234",
+		"pullrequest":    "foo/bar#42",
+		"pullrequesturl": "https://github.com/foo/bar/pull/42",
+		"headbranch":     "bot/update",
+		"merged":         true,
+	}, event.Extensions()); diff != "" {
+		t.Errorf("extensions (-want +got):\n%s", diff)
+	}
+
+	var data eventData
+	if err := json.Unmarshal(event.Data(), &data); err != nil {
+		t.Fatalf("decoding data: %v", err)
+	}
+	if !data.When.Equal(when) || data.Headers.Event != "pull_request" || data.Headers.DeliveryID != "5678" {
+		t.Errorf("data = %+v, want when %v and the delivery headers", data, when)
+	}
+	if !bytes.Equal(data.Body, payload) {
+		t.Errorf("body = %s, want the payload", data.Body)
+	}
+
+	r := signedRequest(t, "", payload, nil)
+	r.Header.Del(github.EventTypeHeader)
+	if _, err := NewEvent(r, payload, when); err == nil {
+		t.Error("NewEvent without an event type succeeded")
+	}
+}
+
+func TestExtractPullRequestInfo(t *testing.T) {
+	testCases := []struct {
+		name      string
+		eventType string
+		payload   PayloadInfo
+		expected  string
+	}{{
+		name:      "pull_request event with valid data",
+		eventType: "pull_request",
+		payload: PayloadInfo{
+			PullRequest: pullRequestInfo{
+				Number: 123,
+			},
+			Repository: struct {
+				FullName string `json:"full_name,omitempty"`
+				Owner    struct {
+					Login string `json:"login,omitempty"`
+				} `json:"owner,omitempty"`
+				Name string `json:"name,omitempty"`
+			}{
+				FullName: "foo/bar",
+			},
+		},
+		expected: "foo/bar#123",
+	}, {
+		name:      "not a pull_request event",
+		eventType: "push",
+		payload: PayloadInfo{
+			PullRequest: pullRequestInfo{
+				Number: 123,
+			},
+			Repository: struct {
+				FullName string `json:"full_name,omitempty"`
+				Owner    struct {
+					Login string `json:"login,omitempty"`
+				} `json:"owner,omitempty"`
+				Name string `json:"name,omitempty"`
+			}{
+				FullName: "foo/bar",
+			},
+		},
+		expected: "",
+	}, {
+		name:      "pull_request event with missing number",
+		eventType: "pull_request",
+		payload: PayloadInfo{
+			Repository: struct {
+				FullName string `json:"full_name,omitempty"`
+				Owner    struct {
+					Login string `json:"login,omitempty"`
+				} `json:"owner,omitempty"`
+				Name string `json:"name,omitempty"`
+			}{
+				FullName: "foo/bar",
+			},
+		},
+		expected: "",
+	}, {
+		name:      "pull_request event with missing repo",
+		eventType: "pull_request",
+		payload: PayloadInfo{
+			PullRequest: pullRequestInfo{
+				Number: 123,
+			},
+		},
+		expected: "",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Call the function directly
+			result := extractPullRequestInfo(tc.eventType, tc.payload)
+
+			// Check the result
+			if result != tc.expected {
+				t.Errorf("result: got = %q, wanted = %q", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestExtractPullRequestURL(t *testing.T) {
+	testCases := []struct {
+		name      string
+		eventType string
+		payload   PayloadInfo
+		expected  string
+	}{{
+		name:      "pull_request event with valid data",
+		eventType: "pull_request",
+		payload: PayloadInfo{
+			PullRequest: pullRequestInfo{
+				Number: 123,
+			},
+			Repository: struct {
+				FullName string `json:"full_name,omitempty"`
+				Owner    struct {
+					Login string `json:"login,omitempty"`
+				} `json:"owner,omitempty"`
+				Name string `json:"name,omitempty"`
+			}{
+				FullName: "foo/bar",
+				Owner: struct {
+					Login string `json:"login,omitempty"`
+				}{
+					Login: "foo",
+				},
+				Name: "bar",
+			},
+		},
+		expected: "https://github.com/foo/bar/pull/123",
+	}, {
+		name:      "not a pull_request event",
+		eventType: "push",
+		payload: PayloadInfo{
+			Number: 123,
+			Repository: struct {
+				FullName string `json:"full_name,omitempty"`
+				Owner    struct {
+					Login string `json:"login,omitempty"`
+				} `json:"owner,omitempty"`
+				Name string `json:"name,omitempty"`
+			}{
+				FullName: "foo/bar",
+				Owner: struct {
+					Login string `json:"login,omitempty"`
+				}{
+					Login: "foo",
+				},
+				Name: "bar",
+			},
+		},
+		expected: "",
+	}, {
+		name:      "pull_request event with missing number",
+		eventType: "pull_request",
+		payload: PayloadInfo{
+			Repository: struct {
+				FullName string `json:"full_name,omitempty"`
+				Owner    struct {
+					Login string `json:"login,omitempty"`
+				} `json:"owner,omitempty"`
+				Name string `json:"name,omitempty"`
+			}{
+				FullName: "foo/bar",
+				Owner: struct {
+					Login string `json:"login,omitempty"`
+				}{
+					Login: "foo",
+				},
+				Name: "bar",
+			},
+		},
+		expected: "",
+	}, {
+		name:      "pull_request event with missing repo",
+		eventType: "pull_request",
+		payload: PayloadInfo{
+			PullRequest: pullRequestInfo{
+				Number: 123,
+			},
+		},
+		expected: "",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Call the function directly
+			result := extractPullRequestURL(tc.eventType, tc.payload)
+
+			// Check the result
+			if result != tc.expected {
+				t.Errorf("result: got = %q, wanted = %q", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestExtractHeadBranch(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		eventType string
+		payload   string
+		want      string
+	}{{
+		name:      "pull_request head ref",
+		eventType: "pull_request",
+		payload:   `{"pull_request":{"head":{"ref":"reconciler-a/path/foo"}}}`,
+		want:      "reconciler-a/path/foo",
+	}, {
+		name:      "pull_request_review head ref",
+		eventType: "pull_request_review",
+		payload:   `{"pull_request":{"head":{"ref":"reconciler-b/widget/bash"}}}`,
+		want:      "reconciler-b/widget/bash",
+	}, {
+		name:      "check_run PR head ref",
+		eventType: "check_run",
+		payload:   `{"check_run":{"check_suite":{"pull_requests":[{"head":{"ref":"reconciler-a/path/bar"}}]}}}`,
+		want:      "reconciler-a/path/bar",
+	}, {
+		name:      "check_suite PR head ref",
+		eventType: "check_suite",
+		payload:   `{"check_suite":{"pull_requests":[{"head":{"ref":"reconciler-a/path/baz"}}]}}`,
+		want:      "reconciler-a/path/baz",
+	}, {
+		name:      "check_suite not associated with a PR",
+		eventType: "check_suite",
+		payload:   `{"check_suite":{"pull_requests":[]}}`,
+		want:      "",
+	}, {
+		name:      "non-PR event ignored",
+		eventType: "push",
+		payload:   `{"ref":"refs/heads/main"}`,
+		want:      "",
+	}, {
+		name:      "pull_request without head",
+		eventType: "pull_request",
+		payload:   `{"pull_request":{"number":1}}`,
+		want:      "",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			var info PayloadInfo
+			if err := json.Unmarshal([]byte(tc.payload), &info); err != nil {
+				t.Fatalf("unmarshal payload: %v", err)
+			}
+			if got := extractHeadBranch(tc.eventType, info); got != tc.want {
+				t.Errorf("extractHeadBranch() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExtractMergeGroup(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		eventType   string
+		payload     string
+		wantHeadSHA string
+		wantBaseRef string
+	}{{
+		name:        "merge_group checks requested",
+		eventType:   "merge_group",
+		payload:     `{"action":"checks_requested","merge_group":{"head_sha":"abc123","head_ref":"refs/heads/gh-readonly-queue/main/pr-1-def456","base_ref":"refs/heads/main"}}`,
+		wantHeadSHA: "abc123",
+		wantBaseRef: "refs/heads/main",
+	}, {
+		name:      "merge_group without merge group",
+		eventType: "merge_group",
+		payload:   `{"action":"destroyed"}`,
+	}, {
+		name:      "non-merge_group event ignored",
+		eventType: "check_suite",
+		payload:   `{"merge_group":{"head_sha":"abc123","base_ref":"refs/heads/main"}}`,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			var info PayloadInfo
+			if err := json.Unmarshal([]byte(tc.payload), &info); err != nil {
+				t.Fatalf("unmarshal payload: %v", err)
+			}
+			headSHA, baseRef := extractMergeGroup(tc.eventType, info)
+			if headSHA != tc.wantHeadSHA || baseRef != tc.wantBaseRef {
+				t.Errorf("extractMergeGroup() = (%q, %q), want (%q, %q)", headSHA, baseRef, tc.wantHeadSHA, tc.wantBaseRef)
+			}
+		})
+	}
+}
+
+func TestExtractIssueURL(t *testing.T) {
+	testCases := []struct {
+		name      string
+		eventType string
+		payload   PayloadInfo
+		expected  string
+	}{{
+		name:      "issues event with valid data",
+		eventType: "issues",
+		payload: PayloadInfo{
+			Issue: struct {
+				Number          int       `json:"number,omitempty"`
+				PullRequestInfo *struct{} `json:"pull_request,omitempty"`
+			}{
+				Number: 456,
+			},
+			Repository: struct {
+				FullName string `json:"full_name,omitempty"`
+				Owner    struct {
+					Login string `json:"login,omitempty"`
+				} `json:"owner,omitempty"`
+				Name string `json:"name,omitempty"`
+			}{
+				FullName: "foo/bar",
+				Owner: struct {
+					Login string `json:"login,omitempty"`
+				}{
+					Login: "foo",
+				},
+				Name: "bar",
+			},
+		},
+		expected: "https://github.com/foo/bar/issues/456",
+	}, {
+		name:      "issue_comment on issue (not PR)",
+		eventType: "issue_comment",
+		payload: PayloadInfo{
+			Issue: struct {
+				Number          int       `json:"number,omitempty"`
+				PullRequestInfo *struct{} `json:"pull_request,omitempty"`
+			}{
+				Number:          789,
+				PullRequestInfo: nil,
+			},
+			Repository: struct {
+				FullName string `json:"full_name,omitempty"`
+				Owner    struct {
+					Login string `json:"login,omitempty"`
+				} `json:"owner,omitempty"`
+				Name string `json:"name,omitempty"`
+			}{
+				FullName: "foo/bar",
+				Owner: struct {
+					Login string `json:"login,omitempty"`
+				}{
+					Login: "foo",
+				},
+				Name: "bar",
+			},
+		},
+		expected: "https://github.com/foo/bar/issues/789",
+	}, {
+		name:      "issue_comment on PR (should not return issue URL)",
+		eventType: "issue_comment",
+		payload: PayloadInfo{
+			Issue: struct {
+				Number          int       `json:"number,omitempty"`
+				PullRequestInfo *struct{} `json:"pull_request,omitempty"`
+			}{
+				Number:          789,
+				PullRequestInfo: &struct{}{},
+			},
+			Repository: struct {
+				FullName string `json:"full_name,omitempty"`
+				Owner    struct {
+					Login string `json:"login,omitempty"`
+				} `json:"owner,omitempty"`
+				Name string `json:"name,omitempty"`
+			}{
+				FullName: "foo/bar",
+				Owner: struct {
+					Login string `json:"login,omitempty"`
+				}{
+					Login: "foo",
+				},
+				Name: "bar",
+			},
+		},
+		expected: "",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Call the function directly
+			result := extractIssueURL(tc.eventType, tc.payload)
+
+			// Check the result
+			if result != tc.expected {
+				t.Errorf("result: got = %q, wanted = %q", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIsPullRequestMerged(t *testing.T) {
+	testCases := []struct {
+		name      string
+		eventType string
+		payload   PayloadInfo
+		expected  bool
+	}{{
+		name:      "merged pull request",
+		eventType: "pull_request",
+		payload: PayloadInfo{
+			Action: "closed",
+			PullRequest: pullRequestInfo{
+				Merged: true,
+			},
+		},
+		expected: true,
+	}, {
+		name:      "closed but not merged pull request",
+		eventType: "pull_request",
+		payload: PayloadInfo{
+			Action: "closed",
+			PullRequest: pullRequestInfo{
+				Merged: false,
+			},
+		},
+		expected: false,
+	}, {
+		name:      "open pull request",
+		eventType: "pull_request",
+		payload: PayloadInfo{
+			Action: "opened",
+			PullRequest: pullRequestInfo{
+				Merged: false,
+			},
+		},
+		expected: false,
+	}, {
+		name:      "not a pull request event",
+		eventType: "push",
+		payload: PayloadInfo{
+			Action: "closed",
+			PullRequest: pullRequestInfo{
+				Merged: true,
+			},
+		},
+		expected: false,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Call the function directly
+			result := isPullRequestMerged(tc.eventType, tc.payload)
+
+			// Check the result
+			if result != tc.expected {
+				t.Errorf("result: got = %v, wanted = %v", result, tc.expected)
+			}
+		})
+	}
+}
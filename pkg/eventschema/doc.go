@@ -0,0 +1,18 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package eventschema validates CloudEvent payloads against JSON Schema
+// (draft 2020-12) documents, such as those schemagen generates from the Go
+// types of an events module.
+//
+// A [Registry] maps CloudEvent types to their [Schema]. [Registry.Validate]
+// checks an event's data against the schema registered for its type, and
+// returns a [ValidationError] listing each field-level [Violation], so
+// trampolines, the broker ingress and tests can detect payloads that don't
+// match the recorded columns.
+//
+// Only the keywords schemagen emits are supported: type, format, properties,
+// required, items and additionalProperties. Others are ignored.
+package eventschema
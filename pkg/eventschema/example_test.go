@@ -0,0 +1,32 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventschema_test
+
+import (
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/chainguard-dev/terraform-infra-common/pkg/eventschema"
+)
+
+func ExampleRegistry_Validate() {
+	r := eventschema.NewRegistry()
+	if err := r.Register("dev.example.ticket", []byte(`{
+		"type": "object",
+		"properties": {"id": {"type": ["integer", "null"]}}
+	}`)); err != nil {
+		panic(err)
+	}
+
+	event := cloudevents.NewEvent()
+	event.SetType("dev.example.ticket")
+	if err := event.SetData(cloudevents.ApplicationJSON, map[string]any{"id": "42"}); err != nil {
+		panic(err)
+	}
+	fmt.Println(r.Validate(event))
+	// Output: dev.example.ticket: 1 schema violation(s); /id: got string, want integer or null
+}
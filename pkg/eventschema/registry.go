@@ -0,0 +1,89 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventschema
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// ErrNoSchema is returned when validating an event whose type has no
+// registered schema.
+var ErrNoSchema = errors.New("no schema registered for event type")
+
+// Registry maps CloudEvent types to the schemas of their data. It is safe for
+// concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	schemas map[string]*Schema
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{schemas: make(map[string]*Schema)}
+}
+
+// Register parses the JSON Schema document b and registers it for events of
+// the given type, replacing any earlier schema.
+func (r *Registry) Register(eventType string, b []byte) error {
+	s, err := Parse(b)
+	if err != nil {
+		return fmt.Errorf("%s: %w", eventType, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas[eventType] = s
+	return nil
+}
+
+// RegisterFS registers each file of fsys matching pattern, such as
+// "*.jsonschema.json", for the event type eventType returns for its name.
+func (r *Registry) RegisterFS(fsys fs.FS, pattern string, eventType func(name string) string) error {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		if err := r.Register(eventType(name), b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Schema returns the schema registered for the event type, if any.
+func (r *Registry) Schema(eventType string) (*Schema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.schemas[eventType]
+	return s, ok
+}
+
+// Validate checks the data of event against the schema registered for its
+// type. It returns a *ValidationError listing the field-level violations, or
+// an error wrapping ErrNoSchema if the type has no schema.
+func (r *Registry) Validate(event cloudevents.Event) error {
+	s, ok := r.Schema(event.Type())
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoSchema, event.Type())
+	}
+	if err := s.Validate(event.Data()); err != nil {
+		if verr, ok := errors.AsType[*ValidationError](err); ok {
+			verr.Type = event.Type()
+			return verr
+		}
+		return fmt.Errorf("%s: %w", event.Type(), err)
+	}
+	return nil
+}
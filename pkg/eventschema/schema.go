@@ -0,0 +1,218 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of the documents this package handles.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document, or a subschema of one.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Types are the JSON types a value may have, such as "string" and "null".
+// An empty list allows any type.
+type Types []string
+
+// MarshalJSON encodes a single type as a string, and several as an array.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON accepts a type as a string, or several as an array.
+func (t *Types) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*t = Types{s}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(t))
+}
+
+// Parse parses a JSON Schema document.
+func Parse(b []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("parsing JSON Schema: %w", err)
+	}
+	return &s, nil
+}
+
+// Violation is a value that doesn't match its schema.
+type Violation struct {
+	// Path is the JSON Pointer of the value, such as "/body/number", or
+	// empty for the whole document.
+	Path string
+	// Message describes the mismatch.
+	Message string
+}
+
+func (v Violation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "/"
+	}
+	return path + ": " + v.Message
+}
+
+// ValidationError is returned for data that doesn't match its schema.
+type ValidationError struct {
+	// Type is the CloudEvent type, when validating an event.
+	Type string
+	// Violations lists each mismatch, in document order.
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	if e.Type != "" {
+		fmt.Fprintf(&b, "%s: ", e.Type)
+	}
+	fmt.Fprintf(&b, "%d schema violation(s)", len(e.Violations))
+	for _, v := range e.Violations {
+		b.WriteString("; ")
+		b.WriteString(v.String())
+	}
+	return b.String()
+}
+
+// Validate checks the JSON document data against s, returning a
+// *ValidationError listing its violations.
+func (s *Schema) Validate(data []byte) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return fmt.Errorf("decoding JSON: %w", err)
+	}
+	if violations := s.validate("", v, nil); len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func (s *Schema) validate(path string, v any, violations []Violation) []Violation {
+	if t := typeOf(v); len(s.Type) > 0 && !s.allows(t, v) {
+		return append(violations, Violation{
+			Path:    path,
+			Message: fmt.Sprintf("got %s, want %s", t, strings.Join(s.Type, " or ")),
+		})
+	}
+
+	switch v := v.(type) {
+	case string:
+		if err := checkFormat(s.Format, v); err != nil {
+			violations = append(violations, Violation{Path: path, Message: err.Error()})
+		}
+
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				violations = append(violations, Violation{Path: path + "/" + escape(name), Message: "missing required property"})
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			ps, ok := s.Properties[name]
+			if !ok {
+				ps = s.AdditionalProperties
+			}
+			if ps != nil {
+				violations = ps.validate(path+"/"+escape(name), v[name], violations)
+			}
+		}
+
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				violations = s.Items.validate(path+"/"+strconv.Itoa(i), item, violations)
+			}
+		}
+	}
+	return violations
+}
+
+func (s *Schema) allows(t string, v any) bool {
+	for _, want := range s.Type {
+		switch {
+		case want == t:
+			return true
+		case want == "number" && t == "integer":
+			return true
+		case want == "integer" && t == "number":
+			// Numbers with a zero fraction, such as 1.0, are integers.
+			if f, err := v.(json.Number).Float64(); err == nil && f == float64(int64(f)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type of a value decoded with UseNumber.
+func typeOf(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func checkFormat(format, v string) error {
+	var layout string
+	switch format {
+	case "date-time":
+		layout = time.RFC3339
+	case "date":
+		layout = time.DateOnly
+	default:
+		return nil
+	}
+	if _, err := time.Parse(layout, v); err != nil {
+		return fmt.Errorf("%q is not a valid %s", v, format)
+	}
+	return nil
+}
+
+// escape escapes a property name for use in a JSON Pointer.
+func escape(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventschema
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+const testSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["id"],
+	"properties": {
+		"id": {"type": "integer"},
+		"score": {"type": ["number", "null"]},
+		"when": {"type": ["string", "null"], "format": "date-time"},
+		"day": {"type": "string", "format": "date"},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"labels": {"type": "object", "additionalProperties": {"type": "boolean"}},
+		"a/b": {"type": "string"},
+		"extra": {}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		data string
+		want []string
+	}{{
+		name: "valid",
+		data: `{"id": 1, "score": 0.5, "when": "2026-01-02T03:04:05Z", "day": "2026-01-02", "tags": ["a"], "labels": {"x": true}, "extra": [1], "unknown": 1}`,
+	}, {
+		name: "nulls and integral numbers",
+		data: `{"id": 2.0, "score": null, "when": null, "extra": null}`,
+	}, {
+		name: "violations",
+		data: `{"id": 1.5, "score": "high", "when": "now", "day": "2026-13-01", "tags": ["a", 2], "labels": {"x": "yes"}, "a/b": 1}`,
+		want: []string{
+			"/a~1b: got integer, want string",
+			"/day: \"2026-13-01\" is not a valid date",
+			"/id: got number, want integer",
+			"/labels/x: got string, want boolean",
+			"/score: got string, want number or null",
+			"/tags/1: got integer, want string",
+			"/when: \"now\" is not a valid date-time",
+		},
+	}, {
+		name: "missing required",
+		data: `{}`,
+		want: []string{"/id: missing required property"},
+	}, {
+		name: "wrong root type",
+		data: `[]`,
+		want: []string{"/: got array, want object"},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Validate([]byte(tt.data))
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v", err)
+				}
+				return
+			}
+			verr, ok := errors.AsType[*ValidationError](err)
+			if !ok {
+				t.Fatalf("Validate() = %v, want a ValidationError", err)
+			}
+			var got []string
+			for _, v := range verr.Violations {
+				got = append(got, v.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("violations =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+
+	if err := s.Validate([]byte(`{`)); err == nil {
+		t.Error("Validate() of invalid JSON succeeded")
+	}
+}
+
+func TestTypes(t *testing.T) {
+	for _, tt := range []struct {
+		types Types
+		want  string
+	}{
+		{Types{"string"}, `"string"`},
+		{Types{"string", "null"}, `["string","null"]`},
+	} {
+		b, err := json.Marshal(tt.types)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("Marshal(%v) = %s, want %s", tt.types, b, tt.want)
+		}
+		var got Types
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if !slices.Equal(got, tt.types) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", b, got, tt.types)
+		}
+	}
+}
@@ -48,6 +48,14 @@ var githubAPIPatterns = []pathPattern{{
 	// https://docs.github.com/en/rest/issues/labels#add-labels-to-an-issue
 	pattern: regexp.MustCompile(`^/repos/[^/]+/[^/]+/issues/\d+/labels$`),
 	bucket:  "/repos/{org}/{repo}/issues/{number}/labels",
+}, {
+	// https://docs.github.com/en/rest/issues/labels#remove-a-label-from-an-issue
+	pattern: regexp.MustCompile(`^/repos/[^/]+/[^/]+/issues/\d+/labels/.+$`),
+	bucket:  "/repos/{org}/{repo}/issues/{number}/labels/{name}",
+}, {
+	// https://docs.github.com/en/rest/issues/labels#create-a-label
+	pattern: regexp.MustCompile(`^/repos/[^/]+/[^/]+/labels$`),
+	bucket:  "/repos/{org}/{repo}/labels",
 }, {
 	// https://docs.github.com/en/rest/pulls/pulls#list-pull-requests
 	pattern: regexp.MustCompile(`^/repos/[^/]+/[^/]+/pulls$`),
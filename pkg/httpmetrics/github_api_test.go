@@ -112,6 +112,12 @@ func Test_bucketizeGitHubPath(t *testing.T) {
 	}, {
 		path:   "/repos/octocat/hello-world/issues/42/labels",
 		bucket: "/repos/{org}/{repo}/issues/{number}/labels",
+	}, {
+		path:   "/repos/octocat/hello-world/issues/42/labels/size/L",
+		bucket: "/repos/{org}/{repo}/issues/{number}/labels/{name}",
+	}, {
+		path:   "/repos/octocat/hello-world/labels",
+		bucket: "/repos/{org}/{repo}/labels",
 	}, {
 		path:   "/repos/octocat/hello-world/check-runs/123",
 		bucket: "/repos/{org}/{repo}/check-runs/{id}",
@@ -56,6 +56,20 @@ func WithGitHubInstallationID(ctx context.Context, installationID int64) context
 	return context.WithValue(ctx, githubInstallationIDKey, strconv.FormatInt(installationID, 10))
 }
 
+// GitHubAppIDFromContext returns the GitHub App ID attached to ctx with
+// WithGitHubAppID, or false if there is none.
+func GitHubAppIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(githubAppIDKey).(string)
+	return id, ok
+}
+
+// GitHubInstallationIDFromContext returns the GitHub installation ID attached
+// to ctx with WithGitHubInstallationID, or false if there is none.
+func GitHubInstallationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(githubInstallationIDKey).(string)
+	return id, ok
+}
+
 var (
 	mReqCount = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -444,8 +458,8 @@ func instrumentGitHubRateLimits(next http.RoundTripper) promhttp.RoundTripperFun
 			// These are set by callers via WithGitHubAppID and WithGitHubInstallationID.
 			// Empty string when not set — callers that do not set these values produce
 			// time series with app_id="" and installation_id="".
-			appID, _ := r.Context().Value(githubAppIDKey).(string)
-			installationID, _ := r.Context().Value(githubInstallationIDKey).(string)
+			appID, _ := GitHubAppIDFromContext(r.Context())
+			installationID, _ := GitHubInstallationIDFromContext(r.Context())
 
 			val := func(key string) float64 {
 				val := resp.Header.Get(key)
@@ -0,0 +1,210 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemagen
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// Change is a difference between two versions of a BigQuery schema.
+type Change struct {
+	// Field is the dotted path of the changed field, or empty when the
+	// whole schema was added or removed.
+	Field string
+	// Old and New are the field before and after the change. Old is nil
+	// for added fields, and New for removed ones.
+	Old, New *bigquery.FieldSchema
+	// Breaking is set for changes BigQuery can't apply to an existing
+	// table, which would make the recorder's load jobs fail.
+	Breaking bool
+	// Reason describes the change.
+	Reason string
+}
+
+func (c Change) String() string {
+	kind := "additive"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	if c.Field == "" {
+		return fmt.Sprintf("%s: %s", kind, c.Reason)
+	}
+	sign := "~"
+	switch {
+	case c.Old == nil:
+		sign = "+"
+	case c.New == nil:
+		sign = "-"
+	}
+	return fmt.Sprintf("%s %s: %s: %s", sign, c.Field, kind, c.Reason)
+}
+
+// Breaking reports whether any of changes is breaking.
+func Breaking(changes []Change) bool {
+	return slices.ContainsFunc(changes, func(c Change) bool { return c.Breaking })
+}
+
+// Diff returns the changes from before to after, classified by whether BigQuery
+// can apply them to an existing table: adding nullable or repeated columns
+// and relaxing required columns to nullable are additive, while removing,
+// renaming or retyping columns, and tightening their mode, are breaking.
+func Diff(before, after bigquery.Schema) []Change {
+	return diff("", before, after)
+}
+
+func diff(prefix string, before, after bigquery.Schema) []Change {
+	var changes []Change
+	for _, of := range before {
+		path := prefix + of.Name
+		nf := field(after, of.Name)
+		if nf == nil {
+			changes = append(changes, Change{Field: path, Old: of, Breaking: true, Reason: "removed " + describe(of)})
+			continue
+		}
+		if ot, nt := normalize(of.Type), normalize(nf.Type); ot != nt {
+			changes = append(changes, Change{Field: path, Old: of, New: nf, Breaking: true, Reason: fmt.Sprintf("type changed from %s to %s", ot, nt)})
+			continue
+		}
+		if om, nm := mode(of), mode(nf); om != nm {
+			// Only relaxing a required column is allowed.
+			breaking := !(om == "REQUIRED" && nm == "NULLABLE")
+			changes = append(changes, Change{Field: path, Old: of, New: nf, Breaking: breaking, Reason: fmt.Sprintf("mode changed from %s to %s", om, nm)})
+		}
+		if normalize(of.Type) == bigquery.RecordFieldType {
+			changes = append(changes, diff(path+".", of.Schema, nf.Schema)...)
+		}
+	}
+	for _, nf := range after {
+		if field(before, nf.Name) != nil {
+			continue
+		}
+		// Existing rows can't have a value for a new required column.
+		changes = append(changes, Change{Field: prefix + nf.Name, New: nf, Breaking: mode(nf) == "REQUIRED", Reason: "added " + describe(nf)})
+	}
+	return changes
+}
+
+// field returns the field of s with the given name. Column names are case
+// insensitive.
+func field(s bigquery.Schema, name string) *bigquery.FieldSchema {
+	for _, f := range s {
+		if strings.EqualFold(f.Name, name) {
+			return f
+		}
+	}
+	return nil
+}
+
+// normalize maps the Standard SQL names of types to the legacy names
+// bigquery.FieldType uses.
+func normalize(t bigquery.FieldType) bigquery.FieldType {
+	switch strings.ToUpper(string(t)) {
+	case "INT64":
+		return bigquery.IntegerFieldType
+	case "FLOAT64":
+		return bigquery.FloatFieldType
+	case "BOOL":
+		return bigquery.BooleanFieldType
+	case "STRUCT":
+		return bigquery.RecordFieldType
+	}
+	return bigquery.FieldType(strings.ToUpper(string(t)))
+}
+
+func mode(f *bigquery.FieldSchema) string {
+	switch {
+	case f.Repeated:
+		return "REPEATED"
+	case f.Required:
+		return "REQUIRED"
+	}
+	return "NULLABLE"
+}
+
+func describe(f *bigquery.FieldSchema) string {
+	if m := mode(f); m != "NULLABLE" {
+		return fmt.Sprintf("%s %s column", m, normalize(f.Type))
+	}
+	return fmt.Sprintf("%s column", normalize(f.Type))
+}
+
+// Read reads a BigQuery JSON schema file.
+func Read(path string) (bigquery.Schema, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := bigquery.SchemaFromJSON(b)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Check returns the changes from the schema file at path to the schema of v,
+// that is what Generate would change. A missing file is an additive change.
+func Check(path string, v any) ([]Change, error) {
+	s, err := Infer(v)
+	if err != nil {
+		return nil, err
+	}
+	committed, err := Read(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Change{{Reason: "new schema"}}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return Diff(committed, s), nil
+}
+
+// CheckDir returns the changes from the *.schema.json files in oldDir to
+// those in newDir, by file name, for schemas maintained by hand or to compare
+// against an earlier revision. Files missing from newDir are breaking, as the
+// recorder would drop their tables.
+func CheckDir(oldDir, newDir string) (map[string][]Change, error) {
+	oldFiles, err := filepath.Glob(filepath.Join(oldDir, "*.schema.json"))
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := filepath.Glob(filepath.Join(newDir, "*.schema.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string][]Change)
+	for _, of := range oldFiles {
+		name := filepath.Base(of)
+		before, err := Read(of)
+		if err != nil {
+			return nil, err
+		}
+		after, err := Read(filepath.Join(newDir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			changes[name] = []Change{{Breaking: true, Reason: "schema removed"}}
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if c := Diff(before, after); len(c) > 0 {
+			changes[name] = c
+		}
+	}
+	for _, nf := range newFiles {
+		name := filepath.Base(nf)
+		if _, err := os.Stat(filepath.Join(oldDir, name)); errors.Is(err, fs.ErrNotExist) {
+			changes[name] = []Change{{Reason: "new schema"}}
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemagen
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestDiff(t *testing.T) {
+	before := bigquery.Schema{
+		{Name: "when", Type: bigquery.TimestampFieldType},
+		{Name: "id", Type: bigquery.IntegerFieldType, Required: true},
+		{Name: "title", Type: bigquery.StringFieldType},
+		{Name: "labels", Type: bigquery.StringFieldType, Repeated: true},
+		{Name: "body", Type: bigquery.RecordFieldType, Schema: bigquery.Schema{
+			{Name: "action", Type: bigquery.StringFieldType},
+			{Name: "number", Type: bigquery.IntegerFieldType},
+		}},
+	}
+	after := bigquery.Schema{
+		{Name: "when", Type: "TIMESTAMP"},
+		// Relaxed.
+		{Name: "id", Type: "INT64"},
+		// Renamed.
+		{Name: "name", Type: bigquery.StringFieldType},
+		// No longer repeated.
+		{Name: "labels", Type: bigquery.StringFieldType},
+		{Name: "body", Type: "STRUCT", Schema: bigquery.Schema{
+			{Name: "Action", Type: bigquery.StringFieldType},
+			{Name: "number", Type: bigquery.StringFieldType},
+			{Name: "draft", Type: bigquery.BooleanFieldType},
+			{Name: "merged", Type: bigquery.BooleanFieldType, Required: true},
+		}},
+	}
+
+	var got []string
+	for _, c := range Diff(before, after) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"~ id: additive: mode changed from REQUIRED to NULLABLE",
+		"- title: breaking: removed STRING column",
+		"~ labels: breaking: mode changed from REPEATED to NULLABLE",
+		"~ body.number: breaking: type changed from INTEGER to STRING",
+		"+ body.draft: additive: added BOOLEAN column",
+		"+ body.merged: breaking: added REQUIRED BOOLEAN column",
+		"+ name: additive: added STRING column",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Diff() =\n%q\nwant\n%q", got, want)
+	}
+
+	if changes := Diff(before, before); len(changes) != 0 {
+		t.Errorf("Diff() of identical schemas = %v, want none", changes)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	type Event struct {
+		Action string `bigquery:"action"`
+		Number int64  `bigquery:"number"`
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "event.schema.json")
+
+	changes, err := Check(path, Event{})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Breaking {
+		t.Errorf("Check() of a new schema = %v, want one additive change", changes)
+	}
+
+	if err := Generate(path, Event{}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if changes, err := Check(path, Event{}); err != nil || len(changes) != 0 {
+		t.Errorf("Check() = %v, %v, want no changes", changes, err)
+	}
+
+	type Changed struct {
+		Action string `bigquery:"action"`
+		Number string `bigquery:"number"`
+	}
+	changes, err = Check(path, Changed{})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if !Breaking(changes) {
+		t.Errorf("Check() = %v, want a breaking change", changes)
+	}
+}
+
+func TestCheckDir(t *testing.T) {
+	before, after := t.TempDir(), t.TempDir()
+	write := func(dir, name, schema string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(schema), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(before, "same.schema.json", `[{"name": "when", "type": "TIMESTAMP"}]`)
+	write(after, "same.schema.json", `[{"name": "when", "type": "TIMESTAMP", "mode": "NULLABLE"}]`)
+	write(before, "grown.schema.json", `[{"name": "when", "type": "TIMESTAMP"}]`)
+	write(after, "grown.schema.json", `[{"name": "when", "type": "TIMESTAMP"}, {"name": "id", "type": "STRING"}]`)
+	write(before, "removed.schema.json", `[{"name": "when", "type": "TIMESTAMP"}]`)
+	write(after, "added.schema.json", `[{"name": "when", "type": "TIMESTAMP"}]`)
+
+	changes, err := CheckDir(before, after)
+	if err != nil {
+		t.Fatalf("CheckDir: %v", err)
+	}
+	for name, want := range map[string]bool{
+		"grown.schema.json":   false,
+		"removed.schema.json": true,
+		"added.schema.json":   false,
+	} {
+		c, ok := changes[name]
+		if !ok {
+			t.Errorf("no changes to %s", name)
+			continue
+		}
+		if Breaking(c) != want {
+			t.Errorf("changes to %s = %v, want breaking %t", name, c, want)
+		}
+	}
+	if c, ok := changes["same.schema.json"]; ok {
+		t.Errorf("changes to same.schema.json = %v, want none", c)
+	}
+}
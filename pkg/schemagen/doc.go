@@ -0,0 +1,28 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package schemagen provides utilities for generating BigQuery JSON schema
+// files from Go types.
+//
+// Use [Generate] to infer a BigQuery schema from a Go value and write it
+// to a file. [Infer] makes every column nullable, names columns after their
+// bigquery or json tags, describes them with their description tags, and
+// maps slices of structs to REPEATED records.
+//
+// [GenerateJSONSchema] writes a JSON Schema (draft 2020-12) of the same
+// type instead, with [InferJSONSchema], for the eventschema package to
+// validate payloads against.
+//
+// A [Manifest] maps schema file names to the values their schemas are
+// inferred from. An events module's schemagen command calls [Main] with its
+// manifest, and runs it with go generate. It writes both schemas of each
+// entry, the JSON Schema named by [JSONSchemaName].
+//
+// [Check] and [CheckDir] compare schemas against committed files or an
+// earlier revision, and [Diff] classifies each difference as additive or
+// breaking, by whether BigQuery can apply it to an existing table.
+// [Manifest.StaleJSONSchemas] reports JSON Schema files that are out of
+// date, which Main's -check fails on regardless.
+package schemagen
@@ -0,0 +1,40 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemagen_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/chainguard-dev/terraform-infra-common/pkg/schemagen"
+)
+
+func ExampleGenerate() {
+	type MyEvent struct {
+		Action string `json:"action"`
+	}
+
+	path := filepath.Join(os.TempDir(), "schema.json")
+	if err := schemagen.Generate(path, MyEvent{}); err != nil {
+		panic(err)
+	}
+	// Output:
+}
+
+func ExampleMain() {
+	type Comment struct {
+		Body string `json:"body" description:"The comment's Markdown."`
+	}
+	type Ticket struct {
+		ID       int64     `json:"id"`
+		Comments []Comment `json:"comments"`
+	}
+
+	// In cmd/schemagen/main.go, with a //go:generate go run ./ directive:
+	schemagen.Main("./../../schemas", schemagen.Manifest{
+		"ticket.schema.json": Ticket{},
+	})
+}
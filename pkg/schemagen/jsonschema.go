@@ -0,0 +1,192 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemagen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+
+	"github.com/chainguard-dev/terraform-infra-common/pkg/eventschema"
+)
+
+// GenerateJSONSchema writes the JSON Schema of v to the given path.
+func GenerateJSONSchema(path string, v any) error {
+	s, err := InferJSONSchema(v)
+	if err != nil {
+		return err
+	}
+	b, err := marshalJSONSchema(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644) //nolint:gosec
+}
+
+// InferJSONSchema returns the JSON Schema (draft 2020-12) of the JSON
+// encoding of v, which must be a struct or a pointer to one, to validate
+// payloads against the columns Infer returns.
+//
+// Properties are named by the field's json tag, or else its bigquery tag, or
+// else the field name with the first letter lowercased. Like columns, every
+// property is optional and nullable, and other properties are allowed, as
+// payloads carry more fields than are recorded.
+func InferJSONSchema(v any) (*eventschema.Schema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schemagen: %T is not a struct", v)
+	}
+	s, err := jsonSchemaStruct(t, map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+	s.Schema = eventschema.Draft
+	return s, nil
+}
+
+func jsonSchemaStruct(t reflect.Type, visiting map[reflect.Type]bool) (*eventschema.Schema, error) {
+	if visiting[t] {
+		return nil, fmt.Errorf("recursive type %s", t)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	s := &eventschema.Schema{
+		Type:       eventschema.Types{"object"},
+		Properties: make(map[string]*eventschema.Schema),
+	}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, ok := jsonName(f)
+		if !ok {
+			continue
+		}
+		if ft := deref(f.Type); f.Anonymous && name == "" && ft.Kind() == reflect.Struct && !leaf(ft) {
+			embedded, err := jsonSchemaStruct(ft, visiting)
+			if err != nil {
+				return nil, err
+			}
+			for n, p := range embedded.Properties {
+				s.Properties[n] = p
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		p, err := jsonSchemaType(f.Type, visiting)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		if len(p.Type) > 0 {
+			p.Type = append(p.Type, "null")
+		}
+		p.Description = f.Tag.Get("description")
+		if name == "" {
+			name = strings.ToLower(f.Name[:1]) + f.Name[1:]
+		}
+		s.Properties[name] = p
+	}
+	return s, nil
+}
+
+// jsonName returns the name of f's property from its json or bigquery tag, or
+// false if f is excluded from the schema.
+func jsonName(f reflect.StructField) (string, bool) {
+	if tag, ok := f.Tag.Lookup("json"); ok {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name, name != "-"
+		}
+	}
+	return fieldName(f)
+}
+
+var jsonSchemaLeaves = map[reflect.Type]eventschema.Schema{
+	reflect.TypeFor[time.Time]():              {Type: eventschema.Types{"string"}, Format: "date-time"},
+	reflect.TypeFor[civil.Date]():             {Type: eventschema.Types{"string"}, Format: "date"},
+	reflect.TypeFor[civil.Time]():             {Type: eventschema.Types{"string"}},
+	reflect.TypeFor[civil.DateTime]():         {Type: eventschema.Types{"string"}},
+	reflect.TypeFor[*big.Rat]():               {Type: eventschema.Types{"string"}},
+	reflect.TypeFor[bigquery.NullString]():    {Type: eventschema.Types{"string"}},
+	reflect.TypeFor[bigquery.NullGeography](): {Type: eventschema.Types{"string"}},
+	reflect.TypeFor[bigquery.NullInt64]():     {Type: eventschema.Types{"integer"}},
+	reflect.TypeFor[bigquery.NullFloat64]():   {Type: eventschema.Types{"number"}},
+	reflect.TypeFor[bigquery.NullBool]():      {Type: eventschema.Types{"boolean"}},
+	reflect.TypeFor[bigquery.NullTimestamp](): {Type: eventschema.Types{"string"}, Format: "date-time"},
+	reflect.TypeFor[bigquery.NullDate]():      {Type: eventschema.Types{"string"}, Format: "date"},
+	reflect.TypeFor[bigquery.NullTime]():      {Type: eventschema.Types{"string"}},
+	reflect.TypeFor[bigquery.NullDateTime]():  {Type: eventschema.Types{"string"}},
+	// JSON columns hold any value.
+	reflect.TypeFor[bigquery.NullJSON](): {},
+	reflect.TypeFor[json.RawMessage]():   {},
+}
+
+func jsonSchemaType(t reflect.Type, visiting map[reflect.Type]bool) (*eventschema.Schema, error) {
+	if s, ok := jsonSchemaLeaves[t]; ok {
+		return &s, nil
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonSchemaType(t.Elem(), visiting)
+	case reflect.Struct:
+		return jsonSchemaStruct(t, visiting)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &eventschema.Schema{Type: eventschema.Types{"string"}, ContentEncoding: "base64"}, nil
+		}
+		items, err := jsonSchemaType(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &eventschema.Schema{Type: eventschema.Types{"array"}, Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key type %s is not supported", t.Key())
+		}
+		values, err := jsonSchemaType(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &eventschema.Schema{Type: eventschema.Types{"object"}, AdditionalProperties: values}, nil
+	case reflect.Interface:
+		return &eventschema.Schema{}, nil
+	case reflect.String:
+		return &eventschema.Schema{Type: eventschema.Types{"string"}}, nil
+	case reflect.Bool:
+		return &eventschema.Schema{Type: eventschema.Types{"boolean"}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &eventschema.Schema{Type: eventschema.Types{"integer"}}, nil
+	case reflect.Float32, reflect.Float64:
+		return &eventschema.Schema{Type: eventschema.Types{"number"}}, nil
+	}
+	return nil, fmt.Errorf("type %s is not supported", t)
+}
+
+func marshalJSONSchema(s *eventschema.Schema) ([]byte, error) {
+	b, err := json.MarshalIndent(s, "", " ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// JSONSchemaName returns the name of the JSON Schema file Manifest.Generate
+// writes next to the BigQuery schema file fn, such as
+// "pull_request.jsonschema.json" for "pull_request.schema.json".
+func JSONSchemaName(fn string) string {
+	return strings.TrimSuffix(fn, ".schema.json") + ".jsonschema.json"
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemagen
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/chainguard-dev/terraform-infra-common/pkg/eventschema"
+)
+
+func TestInferJSONSchema(t *testing.T) {
+	type Label struct {
+		Name bigquery.NullString `bigquery:"name"`
+	}
+	type Event struct {
+		When    time.Time
+		Action  bigquery.NullString `json:"action" bigquery:"action" description:"The action that was performed."`
+		ID      bigquery.NullInt64  `bigquery:"id"`
+		Labels  []Label             `json:"labels,omitempty"`
+		Payload bigquery.NullJSON   `bigquery:"payload"`
+		Secret  string              `json:"-"`
+	}
+
+	got, err := InferJSONSchema(Event{})
+	if err != nil {
+		t.Fatalf("InferJSONSchema: %v", err)
+	}
+	nullable := func(types ...string) eventschema.Types { return append(types, "null") }
+	want := &eventschema.Schema{
+		Schema: eventschema.Draft,
+		Type:   eventschema.Types{"object"},
+		Properties: map[string]*eventschema.Schema{
+			"when":   {Type: nullable("string"), Format: "date-time"},
+			"action": {Type: nullable("string"), Description: "The action that was performed."},
+			"id":     {Type: nullable("integer")},
+			"labels": {Type: nullable("array"), Items: &eventschema.Schema{
+				Type: eventschema.Types{"object"},
+				Properties: map[string]*eventschema.Schema{
+					"name": {Type: nullable("string")},
+				},
+			}},
+			"payload": {},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("InferJSONSchema() (-want +got):\n%s", diff)
+	}
+
+	if _, err := InferJSONSchema(struct{ Counts map[int]int }{}); err == nil {
+		t.Error("InferJSONSchema() of a map with int keys succeeded")
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemagen
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Manifest maps the names of schema files to the values their schemas are
+// inferred from, such as the zero value of the event type a recorder stores.
+type Manifest map[string]any
+
+// Generate writes the schema of each entry of m to its file in dir, and its
+// JSON Schema next to it, named by JSONSchemaName.
+func (m Manifest) Generate(dir string) error {
+	for _, fn := range slices.Sorted(maps.Keys(m)) {
+		if err := Generate(filepath.Join(dir, fn), m[fn]); err != nil {
+			return fmt.Errorf("generating %T -> %s: %w", m[fn], fn, err)
+		}
+		if err := GenerateJSONSchema(filepath.Join(dir, JSONSchemaName(fn)), m[fn]); err != nil {
+			return fmt.Errorf("generating %T -> %s: %w", m[fn], JSONSchemaName(fn), err)
+		}
+	}
+	return nil
+}
+
+// Check returns the changes from the schema files in dir to the schemas of
+// the entries of m, by file name. JSON Schema files are checked separately,
+// by StaleJSONSchemas.
+func (m Manifest) Check(dir string) (map[string][]Change, error) {
+	changes := make(map[string][]Change, len(m))
+	for fn, v := range m {
+		c, err := Check(filepath.Join(dir, fn), v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+		if len(c) > 0 {
+			changes[fn] = c
+		}
+	}
+	return changes, nil
+}
+
+// StaleJSONSchemas returns the names of the JSON Schema files in dir that
+// Generate would rewrite, because they are missing or out of date, sorted.
+// Unlike BigQuery schemas, they have no notion of additive changes: payloads
+// are validated against whatever is committed.
+func (m Manifest) StaleJSONSchemas(dir string) ([]string, error) {
+	var stale []string
+	for _, fn := range slices.Sorted(maps.Keys(m)) {
+		s, err := InferJSONSchema(m[fn])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", JSONSchemaName(fn), err)
+		}
+		want, err := marshalJSONSchema(s)
+		if err != nil {
+			return nil, err
+		}
+		got, err := os.ReadFile(filepath.Join(dir, JSONSchemaName(fn)))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if !bytes.Equal(got, want) {
+			stale = append(stale, JSONSchemaName(fn))
+		}
+	}
+	return stale, nil
+}
+
+// Main is the entry point of a module's schemagen command, which generates
+// the schemas of m into the directory given by the -base flag, defaulting to
+// base. With -check, it instead prints how the committed schemas differ and
+// exits non-zero on breaking changes or stale JSON Schemas, and with
+// -baseline, it compares the schema files in -base against those in another
+// directory, which works for schemas maintained by hand too.
+func Main(base string, m Manifest) {
+	dir := flag.String("base", base, "base directory to write to")
+	check := flag.Bool("check", false, "compare the schemas against the files in the base directory instead of writing them, and fail on breaking changes or stale JSON Schemas")
+	baseline := flag.String("baseline", "", "with -check, compare the schema files in the base directory against those in this directory, such as a checkout of the main branch, instead of against the generated schemas")
+	flag.Parse()
+
+	if !*check {
+		if err := m.Generate(*dir); err != nil {
+			log.Fatalf("Failed to generate schemas: %v", err)
+		}
+		return
+	}
+
+	var changes map[string][]Change
+	var stale []string
+	var err error
+	if *baseline != "" {
+		changes, err = CheckDir(*baseline, *dir)
+	} else {
+		changes, err = m.Check(*dir)
+		if err == nil {
+			stale, err = m.StaleJSONSchemas(*dir)
+		}
+	}
+	if err != nil {
+		log.Fatalf("Failed to check schemas: %v", err)
+	}
+	breaking := false
+	for _, fn := range slices.Sorted(maps.Keys(changes)) {
+		fmt.Printf("%s:\n", fn)
+		for _, c := range changes[fn] {
+			fmt.Printf("  %s\n", c)
+		}
+		breaking = breaking || Breaking(changes[fn])
+	}
+	for _, fn := range stale {
+		fmt.Printf("%s:\n  out of date\n", fn)
+	}
+	if breaking {
+		fmt.Println("\nBreaking changes can't be applied to the recorder's existing tables, see the github-events README.md#modifying-schema-names-for-recorder.")
+	}
+	// Stale JSON Schemas fail the check even though the BigQuery schemas
+	// may only need additive changes, as payloads are validated against them.
+	if (len(changes) > 0 || len(stale) > 0) && *baseline == "" {
+		fmt.Println("\nSchemas are out of date, run go generate ./...")
+	}
+	if breaking || len(stale) > 0 {
+		os.Exit(1)
+	}
+}
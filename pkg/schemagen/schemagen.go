@@ -0,0 +1,162 @@
+/*
+Copyright 2025 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemagen
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// Generate writes a bigquery schema to the given path.
+func Generate(path string, v any) error {
+	s, err := Infer(v)
+	if err != nil {
+		return err
+	}
+	b, err := s.ToJSONFields()
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n') // or else EOF newline linter yells at us.
+
+	return os.WriteFile(path, b, 0644) //nolint:gosec
+}
+
+// Infer returns the BigQuery schema of v, which must be a struct or a pointer
+// to one, as Generate writes it.
+//
+// Every column is nullable, so that events missing a field can still be
+// recorded. Columns are named by the field's bigquery tag, or else its json
+// tag, or else the field name, with the first letter lowercased, and
+// described by its description tag. Slices become REPEATED columns, of
+// records for slices of structs or pointers to them. Embedded structs are
+// flattened.
+func Infer(v any) (bigquery.Schema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schemagen: %T is not a struct", v)
+	}
+	return inferStruct(t, map[reflect.Type]bool{})
+}
+
+func inferStruct(t reflect.Type, visiting map[reflect.Type]bool) (bigquery.Schema, error) {
+	if visiting[t] {
+		return nil, fmt.Errorf("recursive type %s", t)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var s bigquery.Schema
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		if ft := deref(f.Type); f.Anonymous && name == "" && ft.Kind() == reflect.Struct && !leaf(ft) {
+			fields, err := inferStruct(ft, visiting)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, fields...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		fs, err := inferType(f.Type, visiting)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fs.Name = strings.ToLower(name[:1]) + name[1:]
+		fs.Description = f.Tag.Get("description")
+		s = append(s, fs)
+	}
+	return s, nil
+}
+
+// fieldName returns the name of f's column from its bigquery or json tag, or
+// false if f is excluded from the schema.
+func fieldName(f reflect.StructField) (string, bool) {
+	if tag, ok := f.Tag.Lookup("bigquery"); ok {
+		name, _, _ := strings.Cut(tag, ",")
+		return name, name != "-"
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name, name != "-"
+}
+
+func inferType(t reflect.Type, visiting map[reflect.Type]bool) (*bigquery.FieldSchema, error) {
+	switch {
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		fs, err := inferType(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		if fs.Repeated {
+			return nil, fmt.Errorf("nested repeated type %s is not supported", t)
+		}
+		fs.Repeated = true
+		return fs, nil
+
+	case t.Kind() == reflect.Pointer && !leaf(t):
+		return inferType(t.Elem(), visiting)
+
+	case t.Kind() == reflect.Struct && !leaf(t):
+		s, err := inferStruct(t, visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &bigquery.FieldSchema{Type: bigquery.RecordFieldType, Schema: s}, nil
+	}
+
+	// Leave the mapping of scalar types to the BigQuery client.
+	s, err := bigquery.InferSchema(reflect.New(reflect.StructOf([]reflect.StructField{{
+		Name: "V",
+		Type: t,
+	}})).Elem().Interface())
+	if err != nil {
+		return nil, err
+	}
+	// Copy the field, as the client caches inferred schemas.
+	fs := *s[0]
+	fs.Required = false
+	return &fs, nil
+}
+
+// leaf reports whether the BigQuery client maps t to a scalar column, such as
+// bigquery.NullString, time.Time, civil.Date or *big.Rat.
+func leaf(t reflect.Type) bool {
+	if t == reflect.TypeFor[*big.Rat]() {
+		return true
+	}
+	if t.Kind() != reflect.Struct {
+		return t.Kind() != reflect.Pointer
+	}
+	switch t.PkgPath() {
+	case "time", "cloud.google.com/go/civil", "cloud.google.com/go/bigquery":
+		return true
+	}
+	return false
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
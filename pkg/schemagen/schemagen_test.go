@@ -0,0 +1,128 @@
+/*
+Copyright 2026 Chainguard, Inc.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemagen
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInfer(t *testing.T) {
+	type Label struct {
+		Name bigquery.NullString `bigquery:"name"`
+	}
+	type Common struct {
+		Sender string `json:"sender"`
+	}
+	type Event struct {
+		Common
+		When   time.Time
+		Action bigquery.NullString `bigquery:"action" description:"The action that was performed."`
+		Number int64               `json:"number,omitempty"`
+		Draft  *bool               `json:"draft"`
+		Labels []*Label            `json:"labels" bigquery:"labels"`
+		Topics []string            `json:"topics"`
+		Parent *Label              `json:"parent"`
+		Body   []byte
+		Secret string `json:"-"`
+		Ignore string `bigquery:"-" json:"ignore"`
+		hidden string //nolint:unused
+	}
+
+	got, err := Infer(&Event{})
+	if err != nil {
+		t.Fatalf("Infer: %v", err)
+	}
+	want := bigquery.Schema{
+		{Name: "sender", Type: bigquery.StringFieldType},
+		{Name: "when", Type: bigquery.TimestampFieldType},
+		{Name: "action", Type: bigquery.StringFieldType, Description: "The action that was performed."},
+		{Name: "number", Type: bigquery.IntegerFieldType},
+		{Name: "draft", Type: bigquery.BooleanFieldType},
+		{Name: "labels", Type: bigquery.RecordFieldType, Repeated: true, Schema: bigquery.Schema{
+			{Name: "name", Type: bigquery.StringFieldType},
+		}},
+		{Name: "topics", Type: bigquery.StringFieldType, Repeated: true},
+		{Name: "parent", Type: bigquery.RecordFieldType, Schema: bigquery.Schema{
+			{Name: "name", Type: bigquery.StringFieldType},
+		}},
+		{Name: "body", Type: bigquery.BytesFieldType},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Infer() (-want +got):\n%s", diff)
+	}
+}
+
+func TestInferErrors(t *testing.T) {
+	type Node struct {
+		Children []Node
+	}
+	for name, v := range map[string]any{
+		"not a struct":    "event",
+		"recursive":       Node{},
+		"nested repeated": struct{ Matrix [][]string }{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if s, err := Infer(v); err == nil {
+				t.Errorf("Infer() = %v, want error", s)
+			}
+		})
+	}
+}
+
+func TestManifest(t *testing.T) {
+	type Event struct {
+		Action string `json:"action"`
+	}
+	dir := t.TempDir()
+	m := Manifest{"event.schema.json": Event{}}
+
+	changes, err := m.Check(dir)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if c := changes["event.schema.json"]; len(c) != 1 || Breaking(c) {
+		t.Errorf("Check() of a missing schema = %v, want one additive change", changes)
+	}
+	if stale, err := m.StaleJSONSchemas(dir); err != nil || !slices.Equal(stale, []string{"event.jsonschema.json"}) {
+		t.Errorf("StaleJSONSchemas() of a missing schema = %v, %v, want the JSON Schema", stale, err)
+	}
+
+	if err := m.Generate(dir); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	s, err := Read(filepath.Join(dir, "event.schema.json"))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if want := (bigquery.Schema{{Name: "action", Type: bigquery.StringFieldType}}); !cmp.Equal(s, want) {
+		t.Errorf("generated schema = %v, want %v", s, want)
+	}
+	if changes, err := m.Check(dir); err != nil || len(changes) != 0 {
+		t.Errorf("Check() = %v, %v, want no changes", changes, err)
+	}
+	if stale, err := m.StaleJSONSchemas(dir); err != nil || len(stale) != 0 {
+		t.Errorf("StaleJSONSchemas() = %v, %v, want none", stale, err)
+	}
+
+	// An outdated JSON Schema is stale even though the BigQuery schema is
+	// unchanged.
+	if err := os.WriteFile(filepath.Join(dir, "event.jsonschema.json"), []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if changes, err := m.Check(dir); err != nil || len(changes) != 0 {
+		t.Errorf("Check() = %v, %v, want no changes", changes, err)
+	}
+	if stale, err := m.StaleJSONSchemas(dir); err != nil || !slices.Equal(stale, []string{"event.jsonschema.json"}) {
+		t.Errorf("StaleJSONSchemas() = %v, %v, want the JSON Schema", stale, err)
+	}
+}